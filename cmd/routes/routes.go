@@ -1,18 +1,34 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/chatauth"
 	"github.com/zjoart/eunoia/internal/checkin"
 	"github.com/zjoart/eunoia/internal/config"
 	"github.com/zjoart/eunoia/internal/conversation"
 	"github.com/zjoart/eunoia/internal/conversation/platforms"
+	"github.com/zjoart/eunoia/internal/delegation"
+	"github.com/zjoart/eunoia/internal/discord"
+	"github.com/zjoart/eunoia/internal/facts"
+	"github.com/zjoart/eunoia/internal/memory"
 	"github.com/zjoart/eunoia/internal/middleware"
+	"github.com/zjoart/eunoia/internal/push"
+	"github.com/zjoart/eunoia/internal/realtime"
 	"github.com/zjoart/eunoia/internal/reflection"
+	"github.com/zjoart/eunoia/internal/reminder"
+	"github.com/zjoart/eunoia/internal/slack"
+	"github.com/zjoart/eunoia/internal/task"
+	"github.com/zjoart/eunoia/internal/telegram"
+	"github.com/zjoart/eunoia/internal/usage"
 	"github.com/zjoart/eunoia/internal/user"
+	"github.com/zjoart/eunoia/pkg/a2a"
+	"github.com/zjoart/eunoia/pkg/logger"
 )
 
 func SetUpRoutes(db *sql.DB, cfg *config.Config) http.Handler {
@@ -31,16 +47,203 @@ func SetUpRoutes(db *sql.DB, cfg *config.Config) http.Handler {
 	checkInRepo := checkin.NewRepository(db)
 	reflectionRepo := reflection.NewRepository(db)
 	conversationRepo := conversation.NewRepository(db)
+	memoryRepo := memory.NewRepository(db)
+	factRepo := facts.NewRepository(db)
+	usageRepo := usage.NewRepository(db)
+	reminderRepo := reminder.NewRepository(db)
+	taskRepo := task.NewRepository(db)
+	pushRepo := push.NewRepository(db)
 
-	conversationService := conversation.NewService(conversationRepo, userRepo, checkInRepo, reflectionRepo, geminiService)
+	analysisCache := newAnalysisCache(db, cfg.AI)
 
-	platform := platforms.NewPlatform("telex")
+	usageService := usage.NewService(usageRepo, userRepo, cfg.AI.DailyTokenQuota, analysisCache)
+	if geminiService != nil {
+		geminiService.SetUsageRecorder(usageService)
+		geminiService.SetAnalysisCache(analysisCache)
+	}
+
+	memoryService := memory.NewService(memoryRepo, geminiService)
+	factService := facts.NewService(factRepo, userRepo, geminiService)
+
+	promptBudget := agent.NewPromptBudget(cfg.AI.MaxPromptTokens, geminiService)
+	reminderService := reminder.NewService(reminderRepo)
+
+	delegationService := delegation.NewService(trustedAgents(cfg.A2A.TrustedAgents),
+		time.Duration(cfg.A2A.DelegationTimeoutSeconds)*time.Second)
+
+	conversationService := conversation.NewService(conversationRepo, userRepo, checkInRepo, reflectionRepo, geminiService, memoryService, factService, promptBudget, usageService, reminderService, delegationService)
+
+	platformRegistry, err := platforms.NewRegistryFromNames(cfg.A2A.Platforms)
+	if err != nil {
+		logger.Fatal("invalid platform configuration", logger.WithError(err))
+	}
+
+	taskService := task.NewService(taskRepo)
+	pushService := push.NewService(pushRepo, cfg.A2A.PushSigningSecret, cfg.A2A.PushMaxAttempts,
+		time.Duration(cfg.A2A.PushTimeoutSeconds)*time.Second)
+	if cfg.A2A.PushAllowPrivateNetworks {
+		pushService.AllowPrivateNetworks()
+	}
 
-	conversationHandler := conversation.NewHandler(conversationService, platform)
+	conversationHandler := conversation.NewHandler(conversationService, platformRegistry, taskService, pushService)
+	// room for the most inline images the model accepts, base64 encoded, plus the rest of the message
+	conversationHandler.SetMaxBodyBytes(agent.MaxInlineImagesBytes + a2a.DefaultMaxBodyBytes)
+	factHandler := facts.NewHandler(factService)
+	usageHandler := usage.NewHandler(usageService)
 
-	router.HandleFunc("/a2a/agent/eunoia", conversationHandler.HandleA2AMessage).Methods("POST")
+	a2aAuth := middleware.APIKeyMiddleware(cfg.A2A.APIKey)
+	agentCard := conversationHandler.HandleAgentCard(conversation.AgentCardConfig{
+		PublicURL:      cfg.A2A.PublicURL,
+		APIKeyRequired: cfg.A2A.APIKey != "",
+	})
+
+	router.Handle("/a2a/agent/eunoia", a2aAuth(http.HandlerFunc(conversationHandler.HandleA2AMessage))).Methods("POST")
+	router.Handle("/a2a/agent/eunoia/{platform}", a2aAuth(http.HandlerFunc(conversationHandler.HandlePlatformA2AMessage))).Methods("POST")
 	router.HandleFunc("/agent/health", conversationHandler.HandleHealthCheck).Methods("GET")
-	router.PathPrefix("/.well-known/").Handler(http.StripPrefix("/.well-known/", http.FileServer(http.Dir(".well-known"))))
+	// private user data is only served behind a key
+	if cfg.API.Key != "" {
+		apiAuth := middleware.APIKeyMiddleware(cfg.API.Key)
+		router.Handle("/api/v1/search", apiAuth(http.HandlerFunc(conversationHandler.HandleSearch))).Methods("GET")
+		router.Handle("/api/v1/users/{platform_user_id}/facts", apiAuth(http.HandlerFunc(factHandler.HandleListFacts))).Methods("GET")
+		router.Handle("/api/v1/users/{platform_user_id}/facts/{fact_id}", apiAuth(http.HandlerFunc(factHandler.HandleUpdateFact))).Methods("PUT")
+		router.Handle("/api/v1/users/{platform_user_id}/facts/{fact_id}", apiAuth(http.HandlerFunc(factHandler.HandleDeleteFact))).Methods("DELETE")
+	}
+	// usage is reported across every user, so it needs the operator's own key
+	if cfg.API.AdminKey != "" {
+		adminAuth := middleware.APIKeyMiddleware(cfg.API.AdminKey)
+		router.Handle("/api/v1/usage/report", adminAuth(http.HandlerFunc(usageHandler.HandleReport))).Methods("GET")
+	}
+
+	chatEnabled := cfg.Chat.APIKey != "" && cfg.Chat.TokenSecret != ""
+	if cfg.Chat.APIKey != "" && cfg.Chat.TokenSecret == "" {
+		logger.Warn("chat API disabled: CHAT_TOKEN_SECRET is not set")
+	}
+	if chatEnabled {
+		// the app's backend trades the chat key for a token naming one user, and chat requests only
+		// ever act as the user their token names
+		chatTokens := chatauth.NewTokens(cfg.Chat.TokenSecret, time.Duration(cfg.Chat.TokenTTLMinutes)*time.Minute)
+		chatKeyAuth := middleware.APIKeyMiddleware(cfg.Chat.APIKey)
+		router.Handle("/api/v1/chat/tokens", chatKeyAuth(http.HandlerFunc(chatauth.NewHandler(chatTokens).HandleIssueToken))).Methods("POST")
+		router.Handle("/api/v1/chat", chatTokens.Middleware(http.HandlerFunc(conversationHandler.HandleChat))).Methods("POST")
+		router.Handle("/api/v1/chat/history", chatTokens.Middleware(http.HandlerFunc(conversationHandler.HandleChatHistory))).Methods("GET")
+
+		// the socket authenticates with its first frame, since browsers cannot send headers on the upgrade
+		realtimeHandler := realtime.NewHandler(conversationService, reminderService, userRepo, chatTokens,
+			time.Duration(cfg.Chat.NudgeIntervalSeconds)*time.Second)
+		router.HandleFunc("/api/v1/chat/ws", realtimeHandler.HandleWebSocket).Methods("GET")
+	}
+
+	reminderInterval := time.Duration(cfg.Reminders.DeliveryIntervalSeconds) * time.Second
+	reminderDispatcher := reminder.NewDispatcher(reminderService, reminderInterval)
+
+	if cfg.Slack.SigningSecret != "" && cfg.Slack.BotToken != "" {
+		slackClient := slack.NewClient(cfg.Slack.APIURL, cfg.Slack.BotToken, 10*time.Second)
+		slackHandler := slack.NewHandler(conversationService, slackClient, cfg.Slack.SigningSecret)
+		router.HandleFunc("/slack/events", slackHandler.HandleEvents).Methods("POST")
+		reminderDispatcher.Register("slack", slackHandler)
+	}
+
+	if cfg.Telegram.BotToken != "" {
+		if telegramHandler := setUpTelegram(router, cfg.Telegram, conversationService); telegramHandler != nil {
+			reminderDispatcher.Register("telegram", telegramHandler)
+		}
+	}
+
+	// reminders are only offered where they reach the user: through a platform's own messages, or as
+	// WebSocket nudges; Telex, A2A and Discord cannot message a user first
+	var reminderPlatforms []string
+	if reminderInterval > 0 {
+		reminderPlatforms = reminderDispatcher.Platforms()
+		go reminderDispatcher.Run(context.Background())
+	}
+	if chatEnabled && cfg.Chat.NudgeIntervalSeconds > 0 {
+		reminderPlatforms = append(reminderPlatforms, conversation.ChatAPIPlatform)
+	}
+	conversationService.SetReminderPlatforms(reminderPlatforms...)
+
+	if cfg.Discord.PublicKey != "" {
+		checkInService := checkin.NewService(checkInRepo, userRepo)
+		reflectionService := reflection.NewService(reflectionRepo, userRepo, geminiService)
+		setUpDiscord(router, cfg.Discord, conversationService, checkInService, reflectionService)
+	}
+
+	router.HandleFunc("/.well-known/agent.json", agentCard).Methods("GET")
+	router.HandleFunc("/.well-known/agent-card.json", agentCard).Methods("GET")
 
 	return router
 }
+
+// setUpTelegram serves the webhook, or starts long polling when configured for local development;
+// it returns nil when neither is possible
+func setUpTelegram(router *mux.Router, cfg config.TelegramConfig, service telegram.ConversationService) *telegram.Handler {
+	client := telegram.NewClient(cfg.APIURL, cfg.BotToken, 10*time.Second)
+	handler := telegram.NewHandler(service, client, cfg.WebhookSecret)
+
+	if cfg.Polling {
+		go func() {
+			if err := telegram.NewPoller(client, handler).Run(context.Background()); err != nil {
+				logger.Error("telegram long polling stopped", logger.WithError(err))
+			}
+		}()
+		return handler
+	}
+
+	if cfg.WebhookSecret == "" {
+		logger.Warn("telegram webhook disabled: TELEGRAM_WEBHOOK_SECRET is not set")
+		return nil
+	}
+	router.HandleFunc("/telegram/webhook", handler.HandleWebhook).Methods("POST")
+	return handler
+}
+
+// setUpDiscord serves the interactions endpoint and registers the slash commands when a bot token is set
+func setUpDiscord(router *mux.Router, cfg config.DiscordConfig, conversations discord.ConversationService,
+	checkIns discord.CheckInService, reflections discord.ReflectionService) {
+	publicKey, err := discord.ParsePublicKey(cfg.PublicKey)
+	if err != nil {
+		logger.Fatal("invalid discord configuration", logger.WithError(err))
+	}
+
+	client := discord.NewClient(cfg.APIURL, cfg.BotToken, 10*time.Second)
+	handler := discord.NewHandler(conversations, checkIns, reflections, client, publicKey)
+	router.HandleFunc("/discord/interactions", handler.HandleInteractions).Methods("POST")
+
+	if cfg.BotToken != "" && cfg.ApplicationID != "" {
+		go func() {
+			if err := client.RegisterCommands(context.Background(), cfg.ApplicationID, discord.Commands()); err != nil {
+				logger.Error("failed to register discord commands", logger.WithError(err))
+			}
+		}()
+	}
+}
+
+func newAnalysisCache(db *sql.DB, cfg config.AIConfig) *agent.AnalysisCache {
+	if cfg.AnalysisCacheSize <= 0 {
+		return nil
+	}
+
+	var store agent.AnalysisCacheStore
+	if cfg.AnalysisCachePersist {
+		cacheRepo := agent.NewCacheRepository(db)
+		if deleted, err := cacheRepo.DeleteExpired(); err != nil {
+			logger.Warn("failed to clear expired analysis cache entries", logger.WithError(err))
+		} else if deleted > 0 {
+			logger.Info("cleared expired analysis cache entries", logger.Fields{"count": deleted})
+		}
+		store = cacheRepo
+	}
+
+	return agent.NewAnalysisCache(cfg.AnalysisCacheSize, time.Duration(cfg.AnalysisCacheTTLHours)*time.Hour, store)
+}
+
+func trustedAgents(agents []config.TrustedAgent) []delegation.TrustedAgent {
+	trusted := make([]delegation.TrustedAgent, 0, len(agents))
+	for _, agent := range agents {
+		trusted = append(trusted, delegation.TrustedAgent{
+			Name:  agent.Name,
+			URL:   agent.URL,
+			Token: agent.Token,
+		})
+	}
+	return trusted
+}
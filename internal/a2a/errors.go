@@ -1,14 +0,0 @@
-package a2a
-
-// JSON-RPC 2.0 Error Codes
-const (
-	ParseError = -32700
-
-	InvalidRequest = -32600
-
-	MethodNotFound = -32601
-
-	InvalidParams = -32602
-
-	InternalError = -32603
-)
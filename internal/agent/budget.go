@@ -0,0 +1,113 @@
+package agent
+
+import "unicode/utf8"
+
+// PromptSection is a trimmable group of prompt lines, such as history or recalled memories
+type PromptSection struct {
+	Name string
+	// sections with a higher priority value are trimmed first
+	Priority int
+	Items    []string
+	// number of items that are never trimmed
+	MinItems int
+	// drop from the start of Items (oldest first) instead of the end
+	TrimOldest bool
+}
+
+type Allocation struct {
+	EstimatedTokens int
+	Trimmed         map[string]int
+	OverBudget      bool
+}
+
+// PromptBudget trims prompt sections by priority until the estimate fits the token limit
+type PromptBudget struct {
+	maxTokens int
+	estimator TokenEstimator
+}
+
+func NewPromptBudget(maxTokens int, estimator TokenEstimator) *PromptBudget {
+	return &PromptBudget{
+		maxTokens: maxTokens,
+		estimator: estimator,
+	}
+}
+
+func (b *PromptBudget) MaxTokens() int {
+	return b.maxTokens
+}
+
+func (b *PromptBudget) EstimateTokens(text string) int {
+	return b.estimator.EstimateTokens(text)
+}
+
+// Allocate removes items from sections in place so that fixed text plus all sections fit the budget
+func (b *PromptBudget) Allocate(fixed string, sections ...*PromptSection) Allocation {
+	allocation := Allocation{Trimmed: make(map[string]int)}
+
+	total := b.estimator.EstimateTokens(fixed)
+	for _, section := range sections {
+		for _, item := range section.Items {
+			total += b.itemTokens(item)
+		}
+	}
+
+	for total > b.maxTokens {
+		section := b.nextToTrim(sections)
+		if section == nil {
+			allocation.OverBudget = true
+			break
+		}
+
+		var removed string
+		if section.TrimOldest {
+			removed, section.Items = section.Items[0], section.Items[1:]
+		} else {
+			last := len(section.Items) - 1
+			removed, section.Items = section.Items[last], section.Items[:last]
+		}
+
+		total -= b.itemTokens(removed)
+		allocation.Trimmed[section.Name]++
+	}
+
+	allocation.EstimatedTokens = total
+	return allocation
+}
+
+// Truncate shortens text to roughly maxTokens, keeping the beginning
+func (b *PromptBudget) Truncate(text string, maxTokens int) (string, bool) {
+	estimated := b.estimator.EstimateTokens(text)
+	if estimated <= maxTokens {
+		return text, false
+	}
+
+	runes := []rune(text)
+	keep := len(runes) * maxTokens / estimated
+	if keep > len(runes) {
+		keep = len(runes)
+	}
+
+	return string(runes[:keep]), true
+}
+
+func (b *PromptBudget) nextToTrim(sections []*PromptSection) *PromptSection {
+	var next *PromptSection
+	for _, section := range sections {
+		if len(section.Items) <= section.MinItems {
+			continue
+		}
+		if next == nil || section.Priority > next.Priority {
+			next = section
+		}
+	}
+	return next
+}
+
+// itemTokens includes one token for the separating newline
+func (b *PromptBudget) itemTokens(item string) int {
+	if utf8.RuneCountInString(item) == 0 {
+		return 0
+	}
+	return b.estimator.EstimateTokens(item) + 1
+}
@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPromptBudget_Allocate_WithinBudget(t *testing.T) {
+	budget := NewPromptBudget(1000, CharTokenEstimator{CharsPerToken: 4})
+
+	history := &PromptSection{Name: "history", Priority: 2, Items: []string{"User: hi", "Eunoia: hello"}}
+
+	allocation := budget.Allocate("system prompt", history)
+
+	if allocation.OverBudget {
+		t.Error("expected allocation to fit the budget")
+	}
+
+	if len(history.Items) != 2 {
+		t.Errorf("expected history untouched, got %d items", len(history.Items))
+	}
+
+	if len(allocation.Trimmed) != 0 {
+		t.Errorf("expected nothing trimmed, got %v", allocation.Trimmed)
+	}
+}
+
+func TestPromptBudget_Allocate_TrimsByPriority(t *testing.T) {
+	// every item is 10 tokens plus one for the newline
+	item := strings.Repeat("a", 40)
+	budget := NewPromptBudget(50, CharTokenEstimator{CharsPerToken: 4})
+
+	context := &PromptSection{Name: "context", Priority: 1, Items: []string{item, item}}
+	history := &PromptSection{Name: "history", Priority: 2, Items: []string{"oldest" + item, item, item}, MinItems: 1, TrimOldest: true}
+	memories := &PromptSection{Name: "memories", Priority: 3, Items: []string{item, item}}
+
+	allocation := budget.Allocate("", context, history, memories)
+
+	if allocation.OverBudget {
+		t.Errorf("expected allocation to fit, estimated %d", allocation.EstimatedTokens)
+	}
+
+	if len(memories.Items) != 0 {
+		t.Errorf("expected memories trimmed first, got %d left", len(memories.Items))
+	}
+
+	if allocation.Trimmed["memories"] != 2 || allocation.Trimmed["history"] != 1 {
+		t.Errorf("unexpected trim counts: %v", allocation.Trimmed)
+	}
+
+	if strings.HasPrefix(history.Items[0], "oldest") {
+		t.Error("expected oldest history item to be trimmed first")
+	}
+
+	if len(context.Items) != 2 {
+		t.Errorf("expected context untouched, got %d items", len(context.Items))
+	}
+}
+
+func TestPromptBudget_Allocate_OverBudget(t *testing.T) {
+	budget := NewPromptBudget(5, CharTokenEstimator{CharsPerToken: 4})
+
+	history := &PromptSection{Name: "history", Priority: 1, Items: []string{"User: hello there"}, MinItems: 1}
+
+	allocation := budget.Allocate(strings.Repeat("x", 100), history)
+
+	if !allocation.OverBudget {
+		t.Error("expected allocation to report over budget")
+	}
+
+	if len(history.Items) != 1 {
+		t.Error("expected minimum items to be kept")
+	}
+}
+
+func TestPromptBudget_Truncate(t *testing.T) {
+	budget := NewPromptBudget(100, CharTokenEstimator{CharsPerToken: 4})
+
+	text, truncated := budget.Truncate(strings.Repeat("word ", 100), 25)
+	if !truncated {
+		t.Fatal("expected text to be truncated")
+	}
+
+	if tokens := budget.EstimateTokens(text); tokens > 25 {
+		t.Errorf("expected at most 25 tokens, got %d", tokens)
+	}
+
+	short, truncated := budget.Truncate("short message", 25)
+	if truncated || short != "short message" {
+		t.Errorf("expected short text unchanged, got '%s'", short)
+	}
+}
@@ -0,0 +1,184 @@
+package agent
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// CachedAnalysis is a stored result of a deterministic analysis call
+type CachedAnalysis struct {
+	Key       string
+	Kind      string
+	Value     string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// AnalysisCacheStore persists cached analyses so they survive restarts
+type AnalysisCacheStore interface {
+	// GetCachedAnalysis returns nil when nothing unexpired is stored for the key
+	GetCachedAnalysis(key string) (*CachedAnalysis, error)
+	SaveCachedAnalysis(entry *CachedAnalysis) error
+}
+
+type CacheStats struct {
+	Size      int     `json:"size"`
+	Capacity  int     `json:"capacity"`
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	StoreHits int64   `json:"store_hits"`
+	HitRate   float64 `json:"hit_rate"`
+}
+
+// AnalysisCache is an in-memory LRU with TTL in front of sentiment and theme analysis,
+// optionally backed by a persistent store
+type AnalysisCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	order    *list.List
+	store    AnalysisCacheStore
+	now      func() time.Time
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	storeHits atomic.Int64
+}
+
+// NewAnalysisCache creates a cache holding up to capacity entries; store may be nil
+func NewAnalysisCache(capacity int, ttl time.Duration, store AnalysisCacheStore) *AnalysisCache {
+	return &AnalysisCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		store:    store,
+		now:      time.Now,
+	}
+}
+
+// analysisCacheKey hashes the analysis kind, model and normalised input text
+func analysisCacheKey(kind, model, text string) string {
+	sum := sha256.Sum256([]byte(kind + "\x00" + model + "\x00" + strings.TrimSpace(text)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *AnalysisCache) Get(key string) (string, bool) {
+	if value, ok := c.getLocal(key); ok {
+		c.hits.Add(1)
+		return value, true
+	}
+
+	if c.store != nil {
+		entry, err := c.store.GetCachedAnalysis(key)
+		if err != nil {
+			logger.Warn("failed to read analysis cache", logger.WithError(err))
+		} else if entry != nil && c.now().Before(entry.ExpiresAt) {
+			c.setLocal(entry)
+			c.hits.Add(1)
+			c.storeHits.Add(1)
+			return entry.Value, true
+		}
+	}
+
+	c.misses.Add(1)
+	return "", false
+}
+
+func (c *AnalysisCache) Put(key, kind, value string) {
+	now := c.now()
+	entry := &CachedAnalysis{
+		Key:       key,
+		Kind:      kind,
+		Value:     value,
+		ExpiresAt: now.Add(c.ttl),
+		CreatedAt: now,
+	}
+
+	c.setLocal(entry)
+
+	if c.store != nil {
+		if err := c.store.SaveCachedAnalysis(entry); err != nil {
+			logger.Warn("failed to persist analysis cache entry", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{"kind": kind},
+			))
+		}
+	}
+}
+
+// Stats reports cache size and hit/miss counters; it is safe to call on a nil cache
+func (c *AnalysisCache) Stats() *CacheStats {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	size := c.order.Len()
+	c.mu.Unlock()
+
+	stats := &CacheStats{
+		Size:      size,
+		Capacity:  c.capacity,
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		StoreHits: c.storeHits.Load(),
+	}
+
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+
+	return stats
+}
+
+func (c *AnalysisCache) getLocal(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	entry := element.Value.(*CachedAnalysis)
+	if !c.now().Before(entry.ExpiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return "", false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.Value, true
+}
+
+func (c *AnalysisCache) setLocal(entry *CachedAnalysis) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[entry.Key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[entry.Key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*CachedAnalysis).Key)
+	}
+}
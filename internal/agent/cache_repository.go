@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CacheRepository is the MySQL backed AnalysisCacheStore
+type CacheRepository struct {
+	db *sql.DB
+}
+
+func NewCacheRepository(db *sql.DB) *CacheRepository {
+	return &CacheRepository{db: db}
+}
+
+func (r *CacheRepository) GetCachedAnalysis(key string) (*CachedAnalysis, error) {
+	query := `SELECT cache_key, kind, value, expires_at, created_at
+			  FROM analysis_cache
+			  WHERE cache_key = ? AND expires_at > ?`
+
+	entry := &CachedAnalysis{}
+	err := r.db.QueryRow(query, key, time.Now()).Scan(&entry.Key, &entry.Kind, &entry.Value,
+		&entry.ExpiresAt, &entry.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (r *CacheRepository) SaveCachedAnalysis(entry *CachedAnalysis) error {
+	query := `INSERT INTO analysis_cache (cache_key, kind, value, expires_at, created_at)
+			  VALUES (?, ?, ?, ?, ?)
+			  ON DUPLICATE KEY UPDATE value = VALUES(value), expires_at = VALUES(expires_at),
+			  created_at = VALUES(created_at)`
+
+	_, err := r.db.Exec(query, entry.Key, entry.Kind, entry.Value, entry.ExpiresAt, entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExpired removes persisted entries past their TTL
+func (r *CacheRepository) DeleteExpired() (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM analysis_cache WHERE expires_at <= ?`, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSaveCachedAnalysis(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewCacheRepository(db)
+
+	entry := &CachedAnalysis{
+		Key:       "abc123",
+		Kind:      PurposeSentiment,
+		Value:     "positive",
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+
+	mock.ExpectExec("INSERT INTO analysis_cache").
+		WithArgs(entry.Key, entry.Kind, entry.Value, entry.ExpiresAt, entry.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := repo.SaveCachedAnalysis(entry); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetCachedAnalysis(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewCacheRepository(db)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"cache_key", "kind", "value", "expires_at", "created_at"}).
+		AddRow("abc123", PurposeKeyThemes, "work, sleep", now.Add(time.Hour), now)
+
+	mock.ExpectQuery("SELECT (.+) FROM analysis_cache").
+		WithArgs("abc123", sqlmock.AnyArg()).
+		WillReturnRows(rows)
+
+	entry, err := repo.GetCachedAnalysis("abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if entry == nil || entry.Value != "work, sleep" {
+		t.Errorf("expected cached themes, got %+v", entry)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetCachedAnalysis_Missing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewCacheRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM analysis_cache").
+		WithArgs("missing", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"cache_key", "kind", "value", "expires_at", "created_at"}))
+
+	entry, err := repo.GetCachedAnalysis("missing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if entry != nil {
+		t.Errorf("expected nil entry, got %+v", entry)
+	}
+}
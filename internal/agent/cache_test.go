@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeCacheStore struct {
+	entries map[string]*CachedAnalysis
+}
+
+func (f *fakeCacheStore) GetCachedAnalysis(key string) (*CachedAnalysis, error) {
+	return f.entries[key], nil
+}
+
+func (f *fakeCacheStore) SaveCachedAnalysis(entry *CachedAnalysis) error {
+	f.entries[entry.Key] = entry
+	return nil
+}
+
+func TestAnalysisCache_HitAndMiss(t *testing.T) {
+	cache := NewAnalysisCache(10, time.Hour, nil)
+	key := analysisCacheKey(PurposeSentiment, "model", "I had a good day")
+
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+
+	cache.Put(key, PurposeSentiment, "positive")
+
+	value, ok := cache.Get(key)
+	if !ok || value != "positive" {
+		t.Fatalf("expected hit with 'positive', got %q (hit=%v)", value, ok)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Size != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.HitRate != 0.5 {
+		t.Errorf("expected hit rate 0.5, got %f", stats.HitRate)
+	}
+}
+
+func TestAnalysisCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewAnalysisCache(2, time.Hour, nil)
+
+	cache.Put("a", PurposeSentiment, "positive")
+	cache.Put("b", PurposeSentiment, "negative")
+	cache.Get("a")
+	cache.Put("c", PurposeSentiment, "neutral")
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("expected recently used entry to survive")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Error("expected newest entry to be cached")
+	}
+}
+
+func TestAnalysisCache_ExpiresEntries(t *testing.T) {
+	now := time.Now()
+	cache := NewAnalysisCache(10, time.Minute, nil)
+	cache.now = func() time.Time { return now }
+
+	cache.Put("a", PurposeKeyThemes, "work, sleep")
+
+	now = now.Add(2 * time.Minute)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("expected expired entry to miss")
+	}
+	if stats := cache.Stats(); stats.Size != 0 {
+		t.Errorf("expected expired entry to be removed, size %d", stats.Size)
+	}
+}
+
+func TestAnalysisCache_FallsBackToStore(t *testing.T) {
+	store := &fakeCacheStore{entries: map[string]*CachedAnalysis{}}
+
+	first := NewAnalysisCache(10, time.Hour, store)
+	first.Put("a", PurposeSentiment, "mixed")
+
+	// a fresh cache, as after a restart, should find the persisted entry
+	second := NewAnalysisCache(10, time.Hour, store)
+
+	value, ok := second.Get("a")
+	if !ok || value != "mixed" {
+		t.Fatalf("expected store hit with 'mixed', got %q (hit=%v)", value, ok)
+	}
+
+	if stats := second.Stats(); stats.StoreHits != 1 || stats.Size != 1 {
+		t.Errorf("expected one store hit promoted to memory, got %+v", stats)
+	}
+}
+
+func TestAnalysisCacheKey_NormalisesWhitespace(t *testing.T) {
+	if analysisCacheKey(PurposeSentiment, "m", "hello ") != analysisCacheKey(PurposeSentiment, "m", " hello") {
+		t.Error("expected surrounding whitespace to be ignored")
+	}
+	if analysisCacheKey(PurposeSentiment, "m", "hello") == analysisCacheKey(PurposeKeyThemes, "m", "hello") {
+		t.Error("expected different kinds to use different keys")
+	}
+}
+
+func TestGeminiService_CachedAnalysis(t *testing.T) {
+	g := &GeminiService{modelName: "model", cache: NewAnalysisCache(10, time.Hour, nil)}
+
+	calls := 0
+	analyze := func() (string, error) {
+		calls++
+		return "positive", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		value, err := g.cachedAnalysis(PurposeSentiment, "a retried reflection", analyze)
+		if err != nil || value != "positive" {
+			t.Fatalf("unexpected result %q, %v", value, err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected the model to be called once, got %d", calls)
+	}
+}
+
+func TestAnalysisCache_NilStats(t *testing.T) {
+	var cache *AnalysisCache
+	if cache.Stats() != nil {
+		t.Error("expected nil stats for a disabled cache")
+	}
+}
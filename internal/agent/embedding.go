@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+const embeddingModelName = "text-embedding-004"
+
+// EmbeddingProvider turns text into a vector for semantic retrieval
+type EmbeddingProvider interface {
+	Embed(text string) ([]float32, error)
+	EmbeddingModel() string
+}
+
+func (g *GeminiService) Embed(text string) ([]float32, error) {
+	ctx := context.Background()
+
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("cannot embed empty text")
+	}
+
+	started := time.Now()
+	scoped := g.withPurpose(PurposeEmbedding)
+
+	resp, err := g.embedModel.EmbedContent(ctx, genai.Text(text))
+	scoped.recordUsage(embeddingModelName, g.EstimateTokens(text), 0, started, err == nil)
+	if err != nil {
+		logger.Error("failed to embed content", logger.WithError(err))
+		return nil, fmt.Errorf("failed to embed content: %w", err)
+	}
+
+	if resp.Embedding == nil || len(resp.Embedding.Values) == 0 {
+		return nil, fmt.Errorf("no embedding in response")
+	}
+
+	return resp.Embedding.Values, nil
+}
+
+func (g *GeminiService) EmbeddingModel() string {
+	return embeddingModelName
+}
@@ -2,18 +2,28 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/zjoart/eunoia/pkg/logger"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
 type GeminiService struct {
-	apiKey string
-	client *genai.Client
-	model  *genai.GenerativeModel
+	apiKey     string
+	client     *genai.Client
+	model      *genai.GenerativeModel
+	modelName  string
+	embedModel *genai.EmbeddingModel
+	recorder   UsageRecorder
+	cache      *AnalysisCache
+	// purpose and userID attribute calls for usage accounting, see For
+	purpose string
+	userID  string
 }
 
 func NewGeminiService(apiKey string) *GeminiService {
@@ -35,20 +45,155 @@ func NewGeminiService(apiKey string) *GeminiService {
 
 	model.SetTemperature(0.9)
 
+	embedModel := client.EmbeddingModel(embeddingModelName)
+
 	logger.Info("gemini service initialized", logger.Fields{
-		"model": modelName,
+		"model":           modelName,
+		"embedding_model": embeddingModelName,
 	})
 
 	return &GeminiService{
-		apiKey: apiKey,
-		client: client,
-		model:  model,
+		apiKey:     apiKey,
+		client:     client,
+		model:      model,
+		modelName:  modelName,
+		embedModel: embedModel,
 	}
 }
 
 func (g *GeminiService) GenerateContent(systemPrompt string, userMessage string, conversationHistory []string) (string, error) {
 	ctx := context.Background()
 
+	prompt := buildPrompt(systemPrompt, userMessage, conversationHistory)
+	started := time.Now()
+
+	resp, err := g.model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		g.recordUsage(g.modelName, g.EstimateTokens(prompt), 0, started, false)
+		logger.Error("failed to generate content", logger.WithError(err))
+		return "", fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	g.recordResponseUsage(resp, prompt, started)
+
+	return responseText(resp)
+}
+
+// TokenHandler receives reply text as the model writes it
+type TokenHandler func(text string)
+
+// GenerateWithTools is GenerateContent with function calling: the model may call tools from the
+// registry, on behalf of toolCtx, before writing its reply. Images are sent alongside the current message.
+func (g *GeminiService) GenerateWithTools(systemPrompt string, userMessage string, conversationHistory []string, images []Image, tools *ToolRegistry, toolCtx *ToolContext) (string, error) {
+	return g.StreamWithTools(systemPrompt, userMessage, conversationHistory, images, tools, toolCtx, nil)
+}
+
+// StreamWithTools is GenerateWithTools that also hands the reply to onToken chunk by chunk while it is
+// generated; a nil onToken waits for the whole reply
+func (g *GeminiService) StreamWithTools(systemPrompt string, userMessage string, conversationHistory []string, images []Image, tools *ToolRegistry, toolCtx *ToolContext, onToken TokenHandler) (string, error) {
+	hasTools := tools != nil && tools.Len() > 0
+	if !hasTools && len(images) == 0 && onToken == nil {
+		return g.GenerateContent(systemPrompt, userMessage, conversationHistory)
+	}
+
+	ctx := context.Background()
+
+	model := *g.model
+	if hasTools {
+		model.Tools = []*genai.Tool{{FunctionDeclarations: tools.declarations()}}
+	}
+	chat := model.StartChat()
+
+	prompt := buildPrompt(systemPrompt, userMessage, conversationHistory)
+	parts := []genai.Part{genai.Text(prompt)}
+	for _, image := range images {
+		parts = append(parts, genai.Blob{MIMEType: image.MimeType, Data: image.Data})
+	}
+
+	for round := 0; ; round++ {
+		started := time.Now()
+
+		resp, err := sendChatMessage(ctx, chat, onToken, parts)
+		if err != nil {
+			g.recordUsage(g.modelName, g.EstimateTokens(prompt), 0, started, false)
+			logger.Error("failed to generate content", logger.WithError(err))
+			return "", fmt.Errorf("failed to generate content: %w", err)
+		}
+
+		g.recordResponseUsage(resp, prompt, started)
+
+		if len(resp.Candidates) == 0 {
+			return responseText(resp)
+		}
+
+		calls := resp.Candidates[0].FunctionCalls()
+		if len(calls) == 0 {
+			return responseText(resp)
+		}
+
+		if round >= maxToolRounds {
+			logger.Warn("tool call limit reached", logger.Fields{"rounds": round})
+			return "", fmt.Errorf("model kept calling tools after %d rounds", round)
+		}
+
+		parts = executeToolCalls(tools, toolCtx, calls)
+	}
+}
+
+// sendChatMessage sends parts in the chat, streaming the reply text to onToken when it is set
+func sendChatMessage(ctx context.Context, chat *genai.ChatSession, onToken TokenHandler, parts []genai.Part) (*genai.GenerateContentResponse, error) {
+	if onToken == nil {
+		return chat.SendMessage(ctx, parts...)
+	}
+
+	stream := chat.SendMessageStream(ctx, parts...)
+	for {
+		resp, err := stream.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+			continue
+		}
+		for _, part := range resp.Candidates[0].Content.Parts {
+			if text, ok := part.(genai.Text); ok && text != "" {
+				onToken(string(text))
+			}
+		}
+	}
+
+	merged := stream.MergedResponse()
+	if merged == nil {
+		return nil, fmt.Errorf("model returned an empty stream")
+	}
+	return merged, nil
+}
+
+func executeToolCalls(tools *ToolRegistry, toolCtx *ToolContext, calls []genai.FunctionCall) []genai.Part {
+	parts := make([]genai.Part, 0, len(calls))
+	for _, call := range calls {
+		result := tools.Execute(toolCtx, call.Name, ToolArgs(call.Args))
+
+		logger.Info("executed tool call", logger.Fields{
+			"tool":    call.Name,
+			"user_id": toolCtx.UserID,
+			"failed":  result["error"] != nil,
+		})
+
+		parts = append(parts, genai.FunctionResponse{
+			Name:     call.Name,
+			Response: result,
+		})
+	}
+
+	return parts
+}
+
+func buildPrompt(systemPrompt string, userMessage string, conversationHistory []string) string {
 	// Build full prompt with clear structure
 	var promptBuilder strings.Builder
 	promptBuilder.WriteString(systemPrompt)
@@ -68,12 +213,27 @@ func (g *GeminiService) GenerateContent(systemPrompt string, userMessage string,
 	promptBuilder.WriteString("=== Current Message (respond to this) ===\n")
 	promptBuilder.WriteString(userMessage)
 
-	resp, err := g.model.GenerateContent(ctx, genai.Text(promptBuilder.String()))
-	if err != nil {
-		logger.Error("failed to generate content", logger.WithError(err))
-		return "", fmt.Errorf("failed to generate content: %w", err)
+	return promptBuilder.String()
+}
+
+func (g *GeminiService) recordResponseUsage(resp *genai.GenerateContentResponse, prompt string, started time.Time) {
+	promptTokens, completionTokens := g.EstimateTokens(prompt), 0
+	if resp.UsageMetadata != nil {
+		promptTokens = int(resp.UsageMetadata.PromptTokenCount)
+		completionTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+
+		logger.Info("gemini token usage", logger.Fields{
+			"purpose":           g.purpose,
+			"prompt_tokens":     resp.UsageMetadata.PromptTokenCount,
+			"completion_tokens": resp.UsageMetadata.CandidatesTokenCount,
+			"total_tokens":      resp.UsageMetadata.TotalTokenCount,
+		})
 	}
 
+	g.recordUsage(g.modelName, promptTokens, completionTokens, started, len(resp.Candidates) > 0)
+}
+
+func responseText(resp *genai.GenerateContentResponse) (string, error) {
 	if len(resp.Candidates) == 0 {
 		logger.Error("no candidates in gemini response", logger.Fields{
 			"prompt_feedback": resp.PromptFeedback,
@@ -104,13 +264,14 @@ Text: %s
 
 Sentiment:`, text)
 
-	sentiment, err := g.GenerateContent("You are a sentiment analysis assistant.", prompt, []string{})
-	if err != nil {
-		return "", err
-	}
+	return g.cachedAnalysis(PurposeSentiment, text, func() (string, error) {
+		sentiment, err := g.withPurpose(PurposeSentiment).GenerateContent("You are a sentiment analysis assistant.", prompt, []string{})
+		if err != nil {
+			return "", err
+		}
 
-	sentiment = strings.TrimSpace(strings.ToLower(sentiment))
-	return sentiment, nil
+		return strings.TrimSpace(strings.ToLower(sentiment)), nil
+	})
 }
 
 func (g *GeminiService) ExtractKeyThemes(text string) (string, error) {
@@ -121,13 +282,42 @@ Text: %s
 
 Key themes:`, text)
 
-	themes, err := g.GenerateContent("You are a text analysis assistant.", prompt, []string{})
+	return g.cachedAnalysis(PurposeKeyThemes, text, func() (string, error) {
+		themes, err := g.withPurpose(PurposeKeyThemes).GenerateContent("You are a text analysis assistant.", prompt, []string{})
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(themes), nil
+	})
+}
+
+func (g *GeminiService) SetAnalysisCache(cache *AnalysisCache) {
+	g.cache = cache
+}
+
+// cachedAnalysis serves a deterministic analysis from the cache, running analyze only on a miss
+func (g *GeminiService) cachedAnalysis(kind, text string, analyze func() (string, error)) (string, error) {
+	if g.cache == nil {
+		return analyze()
+	}
+
+	key := analysisCacheKey(kind, g.modelName, text)
+	if value, ok := g.cache.Get(key); ok {
+		logger.Debug("analysis cache hit", logger.Fields{"kind": kind})
+		return value, nil
+	}
+
+	value, err := analyze()
 	if err != nil {
 		return "", err
 	}
 
-	themes = strings.TrimSpace(themes)
-	return themes, nil
+	if value != "" {
+		g.cache.Put(key, kind, value)
+	}
+
+	return value, nil
 }
 
 func (g *GeminiService) Close() error {
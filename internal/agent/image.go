@@ -0,0 +1,30 @@
+package agent
+
+// MaxImageBytes is the largest image sent inline to the model
+const MaxImageBytes = 4 << 20
+
+// MaxImages is the most images sent with one message
+const MaxImages = 4
+
+// MaxInlineImagesBytes is the base64 size of MaxImages images of MaxImageBytes, which a request carrying
+// them inline must have room for
+const MaxInlineImagesBytes = (MaxImages*MaxImageBytes + 2) / 3 * 4
+
+var supportedImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+	"image/heic": true,
+	"image/heif": true,
+}
+
+// Image is an attachment the model can look at alongside a message
+type Image struct {
+	MimeType string
+	Data     []byte
+}
+
+// IsSupportedImage reports whether the model accepts images of this MIME type
+func IsSupportedImage(mimeType string) bool {
+	return supportedImageTypes[mimeType]
+}
@@ -0,0 +1,30 @@
+package agent
+
+import (
+	"math"
+	"unicode/utf8"
+)
+
+// TokenEstimator approximates how many tokens a provider will count for a piece of text
+type TokenEstimator interface {
+	EstimateTokens(text string) int
+}
+
+// CharTokenEstimator estimates tokens from the character count, which is close enough for budgeting
+type CharTokenEstimator struct {
+	CharsPerToken float64
+}
+
+func (e CharTokenEstimator) EstimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return int(math.Ceil(float64(utf8.RuneCountInString(text)) / e.CharsPerToken))
+}
+
+// Gemini averages roughly four characters per token for English text
+var geminiTokenEstimator = CharTokenEstimator{CharsPerToken: 4}
+
+func (g *GeminiService) EstimateTokens(text string) int {
+	return geminiTokenEstimator.EstimateTokens(text)
+}
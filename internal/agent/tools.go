@@ -0,0 +1,300 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// maximum number of model round trips spent on tool calls before a reply is required
+const maxToolRounds = 4
+
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,63}$`)
+
+// ToolParameter types, matching the JSON schema names the model understands
+const (
+	ParamString  = "string"
+	ParamInteger = "integer"
+	ParamNumber  = "number"
+	ParamBoolean = "boolean"
+)
+
+type ToolParameter struct {
+	Type        string
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+// ToolContext identifies the user a tool call is made on behalf of and the message being answered
+type ToolContext struct {
+	UserID         string
+	PlatformUserID string
+	MessageID      string
+	Message        string
+	// AwaitingConfirmation is set once a write tool has been proposed to the user
+	AwaitingConfirmation bool
+}
+
+// ToolArgs are the arguments the model supplied for a tool call
+type ToolArgs map[string]any
+
+// ToolHandler runs a tool; the result is returned to the model as JSON
+type ToolHandler func(ctx *ToolContext, args ToolArgs) (any, error)
+
+// ToolPreparer validates and completes a write tool's arguments before confirmation,
+// returning a short description of the action for the user to approve
+type ToolPreparer func(ctx *ToolContext, args ToolArgs) (ToolArgs, string, error)
+
+// ConfirmationHandler receives write tool calls instead of running them, so the user can approve
+// them first; its result is what the model sees
+type ConfirmationHandler func(ctx *ToolContext, tool *Tool, args ToolArgs, summary string) (any, error)
+
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]*ToolParameter
+	Handler     ToolHandler
+	// RequiresConfirmation marks tools with side effects, which only run once the user agrees
+	RequiresConfirmation bool
+	Prepare              ToolPreparer
+}
+
+// ToolRegistry holds the tools the model may call during a conversation
+type ToolRegistry struct {
+	tools   map[string]*Tool
+	confirm ConfirmationHandler
+}
+
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{
+		tools: make(map[string]*Tool),
+	}
+}
+
+func (r *ToolRegistry) Register(tool *Tool) error {
+	if !toolNamePattern.MatchString(tool.Name) {
+		return fmt.Errorf("invalid tool name %q", tool.Name)
+	}
+	if tool.Handler == nil {
+		return fmt.Errorf("tool %s has no handler", tool.Name)
+	}
+	if _, exists := r.tools[tool.Name]; exists {
+		return fmt.Errorf("tool %s is already registered", tool.Name)
+	}
+
+	r.tools[tool.Name] = tool
+	return nil
+}
+
+func (r *ToolRegistry) SetConfirmationHandler(handler ConfirmationHandler) {
+	r.confirm = handler
+}
+
+func (r *ToolRegistry) Get(name string) (*Tool, bool) {
+	tool, ok := r.tools[name]
+	return tool, ok
+}
+
+// Names returns the registered tool names in alphabetical order
+func (r *ToolRegistry) Names() []string {
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Without returns a registry with the same tools and confirmation handler, minus the named tools
+func (r *ToolRegistry) Without(names ...string) *ToolRegistry {
+	filtered := &ToolRegistry{tools: make(map[string]*Tool, len(r.tools)), confirm: r.confirm}
+	for name, tool := range r.tools {
+		filtered.tools[name] = tool
+	}
+	for _, name := range names {
+		delete(filtered.tools, name)
+	}
+	return filtered
+}
+
+func (r *ToolRegistry) Len() int {
+	return len(r.tools)
+}
+
+// Execute runs the named tool, returning an error result the model can read instead of failing the turn
+func (r *ToolRegistry) Execute(ctx *ToolContext, name string, args ToolArgs) map[string]any {
+	tool, ok := r.tools[name]
+	if !ok {
+		return map[string]any{"error": fmt.Sprintf("unknown tool %s", name)}
+	}
+
+	for paramName, param := range tool.Parameters {
+		if param.Required {
+			if _, present := args[paramName]; !present {
+				return map[string]any{"error": fmt.Sprintf("missing required argument %s", paramName)}
+			}
+		}
+	}
+
+	var result any
+	var err error
+	if tool.RequiresConfirmation {
+		result, err = r.propose(ctx, tool, args)
+	} else {
+		result, err = tool.Handler(ctx, args)
+	}
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+
+	return functionResponse(result)
+}
+
+// ExecuteConfirmed runs a write tool the user has approved
+func (r *ToolRegistry) ExecuteConfirmed(ctx *ToolContext, name string, args ToolArgs) (map[string]any, error) {
+	tool, ok := r.tools[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tool %s", name)
+	}
+
+	result, err := tool.Handler(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return functionResponse(result), nil
+}
+
+func (r *ToolRegistry) propose(ctx *ToolContext, tool *Tool, args ToolArgs) (any, error) {
+	if r.confirm == nil {
+		return nil, fmt.Errorf("%s needs user confirmation, which is not available", tool.Name)
+	}
+
+	summary := tool.Description
+	if tool.Prepare != nil {
+		prepared, description, err := tool.Prepare(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		args, summary = prepared, description
+	}
+
+	result, err := r.confirm(ctx, tool, args, summary)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.AwaitingConfirmation = true
+	return result, nil
+}
+
+func functionResponse(result any) map[string]any {
+	response, err := toFunctionResponse(result)
+	if err != nil {
+		return map[string]any{"error": "tool returned an unreadable result"}
+	}
+
+	return response
+}
+
+func (r *ToolRegistry) declarations() []*genai.FunctionDeclaration {
+	var declarations []*genai.FunctionDeclaration
+	for _, name := range r.Names() {
+		tool := r.tools[name]
+
+		declaration := &genai.FunctionDeclaration{
+			Name:        tool.Name,
+			Description: tool.Description,
+		}
+
+		if len(tool.Parameters) > 0 {
+			schema := &genai.Schema{
+				Type:       genai.TypeObject,
+				Properties: make(map[string]*genai.Schema),
+			}
+			for paramName, param := range tool.Parameters {
+				schema.Properties[paramName] = &genai.Schema{
+					Type:        schemaType(param.Type),
+					Description: param.Description,
+					Enum:        param.Enum,
+				}
+				if param.Required {
+					schema.Required = append(schema.Required, paramName)
+				}
+			}
+			sort.Strings(schema.Required)
+			declaration.Parameters = schema
+		}
+
+		declarations = append(declarations, declaration)
+	}
+
+	return declarations
+}
+
+func schemaType(paramType string) genai.Type {
+	switch paramType {
+	case ParamInteger:
+		return genai.TypeInteger
+	case ParamNumber:
+		return genai.TypeNumber
+	case ParamBoolean:
+		return genai.TypeBoolean
+	default:
+		return genai.TypeString
+	}
+}
+
+// toFunctionResponse converts a handler result into the plain JSON object form the API accepts
+func toFunctionResponse(result any) (map[string]any, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	if object, ok := decoded.(map[string]any); ok {
+		return object, nil
+	}
+
+	return map[string]any{"result": decoded}, nil
+}
+
+// Int returns an integer argument, falling back to def when missing or invalid
+func (a ToolArgs) Int(name string, def int) int {
+	switch value := a[name].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	case string:
+		var parsed int
+		if _, err := fmt.Sscanf(value, "%d", &parsed); err == nil {
+			return parsed
+		}
+	}
+	return def
+}
+
+// String returns a trimmed string argument, or "" when missing
+func (a ToolArgs) String(name string) string {
+	if value, ok := a[name].(string); ok {
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
+
+// Bool returns a boolean argument, or false when missing
+func (a ToolArgs) Bool(name string) bool {
+	value, _ := a[name].(bool)
+	return value
+}
@@ -0,0 +1,251 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+func newTestRegistry(t *testing.T) *ToolRegistry {
+	registry := NewToolRegistry()
+
+	err := registry.Register(&Tool{
+		Name:        "get_checkin_stats",
+		Description: "Mood stats",
+		Parameters: map[string]*ToolParameter{
+			"days": {Type: ParamInteger, Description: "Days to look back", Required: true},
+		},
+		Handler: func(ctx *ToolContext, args ToolArgs) (any, error) {
+			return struct {
+				UserID string    `json:"user_id"`
+				Days   int       `json:"days"`
+				At     time.Time `json:"at"`
+			}{ctx.UserID, args.Int("days", 0), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to register tool: %v", err)
+	}
+
+	err = registry.Register(&Tool{
+		Name:        "broken",
+		Description: "Always fails",
+		Handler: func(ctx *ToolContext, args ToolArgs) (any, error) {
+			return nil, errors.New("database unavailable")
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to register tool: %v", err)
+	}
+
+	return registry
+}
+
+func TestToolRegistry_Register_Validation(t *testing.T) {
+	registry := newTestRegistry(t)
+	handler := func(ctx *ToolContext, args ToolArgs) (any, error) { return nil, nil }
+
+	if err := registry.Register(&Tool{Name: "get_checkin_stats", Handler: handler}); err == nil {
+		t.Error("expected duplicate tool name to be rejected")
+	}
+	if err := registry.Register(&Tool{Name: "has spaces", Handler: handler}); err == nil {
+		t.Error("expected invalid tool name to be rejected")
+	}
+	if err := registry.Register(&Tool{Name: "no_handler"}); err == nil {
+		t.Error("expected tool without handler to be rejected")
+	}
+
+	if registry.Len() != 2 {
+		t.Errorf("expected 2 tools, got %d", registry.Len())
+	}
+}
+
+func TestToolRegistry_Execute(t *testing.T) {
+	registry := newTestRegistry(t)
+	ctx := &ToolContext{UserID: "user-1", PlatformUserID: "platform-1"}
+
+	result := registry.Execute(ctx, "get_checkin_stats", ToolArgs{"days": float64(7)})
+	if result["error"] != nil {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if result["user_id"] != "user-1" || result["days"] != float64(7) || result["at"] != "2025-01-01T00:00:00Z" {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	tests := []struct {
+		name string
+		tool string
+		args ToolArgs
+	}{
+		{"unknown tool", "delete_everything", nil},
+		{"missing required argument", "get_checkin_stats", ToolArgs{}},
+		{"handler error", "broken", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := registry.Execute(ctx, tt.tool, tt.args)
+			if result["error"] == nil {
+				t.Errorf("expected an error result, got %v", result)
+			}
+		})
+	}
+}
+
+func TestToolRegistry_Declarations(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	declarations := registry.declarations()
+	if len(declarations) != 2 {
+		t.Fatalf("expected 2 declarations, got %d", len(declarations))
+	}
+
+	// declarations are sorted by name
+	if declarations[0].Name != "broken" || declarations[0].Parameters != nil {
+		t.Errorf("unexpected first declaration: %+v", declarations[0])
+	}
+
+	stats := declarations[1]
+	if stats.Parameters.Type != genai.TypeObject {
+		t.Errorf("expected object parameters, got %v", stats.Parameters.Type)
+	}
+	if stats.Parameters.Properties["days"].Type != genai.TypeInteger {
+		t.Errorf("expected integer days parameter, got %v", stats.Parameters.Properties["days"].Type)
+	}
+	if len(stats.Parameters.Required) != 1 || stats.Parameters.Required[0] != "days" {
+		t.Errorf("expected days to be required, got %v", stats.Parameters.Required)
+	}
+}
+
+func TestExecuteToolCalls(t *testing.T) {
+	registry := newTestRegistry(t)
+	ctx := &ToolContext{UserID: "user-1"}
+
+	parts := executeToolCalls(registry, ctx, []genai.FunctionCall{
+		{Name: "get_checkin_stats", Args: map[string]any{"days": float64(3)}},
+		{Name: "broken"},
+	})
+
+	if len(parts) != 2 {
+		t.Fatalf("expected a response per call, got %d", len(parts))
+	}
+
+	first, ok := parts[0].(genai.FunctionResponse)
+	if !ok || first.Name != "get_checkin_stats" || first.Response["days"] != float64(3) {
+		t.Errorf("unexpected first response: %+v", parts[0])
+	}
+
+	second, ok := parts[1].(genai.FunctionResponse)
+	if !ok || second.Response["error"] != "database unavailable" {
+		t.Errorf("expected error response, got %+v", parts[1])
+	}
+}
+
+func TestToFunctionResponse_WrapsNonObjects(t *testing.T) {
+	response, err := toFunctionResponse([]string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items, ok := response["result"].([]any)
+	if !ok || len(items) != 2 {
+		t.Errorf("expected wrapped list, got %v", response)
+	}
+}
+
+func TestToolArgs(t *testing.T) {
+	args := ToolArgs{"days": float64(14), "text": "  sister ", "bad": "x", "flag": true}
+
+	if got := args.Int("days", 7); got != 14 {
+		t.Errorf("expected 14, got %d", got)
+	}
+	if got := args.Int("bad", 7); got != 7 {
+		t.Errorf("expected default for invalid value, got %d", got)
+	}
+	if got := args.Int("missing", 7); got != 7 {
+		t.Errorf("expected default for missing value, got %d", got)
+	}
+	if got := args.String("text"); got != "sister" {
+		t.Errorf("expected trimmed string, got %q", got)
+	}
+	if !args.Bool("flag") || args.Bool("missing") {
+		t.Error("unexpected bool values")
+	}
+}
+
+func TestToolRegistry_WriteToolsNeedConfirmation(t *testing.T) {
+	registry := NewToolRegistry()
+	ran := false
+
+	err := registry.Register(&Tool{
+		Name:                 "log_checkin",
+		Description:          "Log a check-in",
+		RequiresConfirmation: true,
+		Prepare: func(ctx *ToolContext, args ToolArgs) (ToolArgs, string, error) {
+			score := args.Int("mood_score", 0)
+			if score < 1 {
+				return nil, "", errors.New("mood_score must be between 1 and 10")
+			}
+			return ToolArgs{"mood_score": score}, "log your mood as 4/10", nil
+		},
+		Handler: func(ctx *ToolContext, args ToolArgs) (any, error) {
+			ran = true
+			return map[string]any{"message": "logged"}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to register tool: %v", err)
+	}
+
+	ctx := &ToolContext{UserID: "user-1"}
+
+	result := registry.Execute(ctx, "log_checkin", ToolArgs{"mood_score": float64(4)})
+	if result["error"] == nil {
+		t.Error("expected an error without a confirmation handler")
+	}
+
+	var proposedSummary string
+	var proposedArgs ToolArgs
+	registry.SetConfirmationHandler(func(ctx *ToolContext, tool *Tool, args ToolArgs, summary string) (any, error) {
+		proposedSummary, proposedArgs = summary, args
+		return map[string]any{"status": "awaiting_confirmation"}, nil
+	})
+
+	result = registry.Execute(ctx, "log_checkin", ToolArgs{"mood_score": float64(4)})
+	if result["status"] != "awaiting_confirmation" {
+		t.Errorf("expected the call to await confirmation, got %v", result)
+	}
+	if ran {
+		t.Error("expected the handler not to run before confirmation")
+	}
+	if proposedSummary != "log your mood as 4/10" || proposedArgs.Int("mood_score", 0) != 4 {
+		t.Errorf("expected prepared args and summary, got %q %v", proposedSummary, proposedArgs)
+	}
+
+	result = registry.Execute(ctx, "log_checkin", ToolArgs{"mood_score": float64(0)})
+	if result["error"] == nil {
+		t.Error("expected invalid arguments to be rejected before confirmation")
+	}
+
+	confirmed, err := registry.ExecuteConfirmed(ctx, "log_checkin", proposedArgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ran || confirmed["message"] != "logged" {
+		t.Errorf("expected the confirmed handler to run, got %v", confirmed)
+	}
+}
+
+func TestToolRegistry_Without(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	filtered := registry.Without("broken")
+	if names := filtered.Names(); len(names) != 1 || names[0] != "get_checkin_stats" {
+		t.Errorf("unexpected tools %v", names)
+	}
+	if registry.Len() != 2 {
+		t.Error("expected the original registry to keep every tool")
+	}
+}
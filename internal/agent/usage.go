@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"time"
+)
+
+// Purposes identify which feature an LLM call was made for
+const (
+	PurposeChat               = "chat"
+	PurposeSentiment          = "sentiment"
+	PurposeKeyThemes          = "key_themes"
+	PurposeReflectionAnalysis = "reflection_analysis"
+	PurposeSummary            = "summary"
+	PurposeFactExtraction     = "fact_extraction"
+	PurposeEmbedding          = "embedding"
+)
+
+// Usage describes a single LLM call for cost accounting
+type Usage struct {
+	Purpose          string
+	UserID           string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	Latency          time.Duration
+	EstimatedCost    float64
+	Success          bool
+	CreatedAt        time.Time
+}
+
+// UsageRecorder receives a record of every LLM call
+type UsageRecorder interface {
+	RecordUsage(usage *Usage)
+}
+
+type modelPricing struct {
+	inputPerMillion  float64
+	outputPerMillion float64
+}
+
+// USD list prices per million tokens
+var pricing = map[string]modelPricing{
+	"gemini-2.5-flash":   {inputPerMillion: 0.30, outputPerMillion: 2.50},
+	"text-embedding-004": {inputPerMillion: 0, outputPerMillion: 0},
+}
+
+// EstimateCost returns the USD cost of a call, or 0 for models without known pricing
+func EstimateCost(model string, promptTokens, completionTokens int) float64 {
+	price, ok := pricing[model]
+	if !ok {
+		return 0
+	}
+
+	return (float64(promptTokens)*price.inputPerMillion + float64(completionTokens)*price.outputPerMillion) / 1_000_000
+}
+
+func (g *GeminiService) SetUsageRecorder(recorder UsageRecorder) {
+	g.recorder = recorder
+}
+
+// For returns a view of the service that attributes its calls to the given purpose and user
+func (g *GeminiService) For(purpose, userID string) *GeminiService {
+	scoped := *g
+	scoped.purpose = purpose
+	scoped.userID = userID
+	return &scoped
+}
+
+func (g *GeminiService) withPurpose(purpose string) *GeminiService {
+	return g.For(purpose, g.userID)
+}
+
+func (g *GeminiService) recordUsage(model string, promptTokens, completionTokens int, started time.Time, success bool) {
+	if g.recorder == nil {
+		return
+	}
+
+	purpose := g.purpose
+	if purpose == "" {
+		purpose = PurposeChat
+	}
+
+	g.recorder.RecordUsage(&Usage{
+		Purpose:          purpose,
+		UserID:           g.userID,
+		Model:            model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		Latency:          time.Since(started),
+		EstimatedCost:    EstimateCost(model, promptTokens, completionTokens),
+		Success:          success,
+		CreatedAt:        started,
+	})
+}
@@ -0,0 +1,18 @@
+package agent
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateCost(t *testing.T) {
+	cost := EstimateCost("gemini-2.5-flash", 1_000_000, 100_000)
+	expected := 0.30 + 0.25
+	if math.Abs(cost-expected) > 1e-9 {
+		t.Errorf("expected cost %f, got %f", expected, cost)
+	}
+
+	if cost := EstimateCost("unknown-model", 1000, 1000); cost != 0 {
+		t.Errorf("expected unknown model to cost 0, got %f", cost)
+	}
+}
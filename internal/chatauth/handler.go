@@ -0,0 +1,73 @@
+package chatauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+const maxTokenRequestBytes = 1 << 10
+
+type Handler struct {
+	tokens *Tokens
+}
+
+func NewHandler(tokens *Tokens) *Handler {
+	return &Handler{
+		tokens: tokens,
+	}
+}
+
+type issueRequest struct {
+	PlatformUserID string `json:"platform_user_id"`
+}
+
+type issueResponse struct {
+	Token          string    `json:"token"`
+	PlatformUserID string    `json:"platform_user_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// HandleIssueToken gives the app's backend a token for one of its signed-in users. It must be served
+// behind the chat API key, since whoever can call it can act as any user.
+func (h *Handler) HandleIssueToken(w http.ResponseWriter, r *http.Request) {
+	var req issueRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTokenRequestBytes)).Decode(&req); err != nil {
+		h.sendJSONError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	req.PlatformUserID = strings.TrimSpace(req.PlatformUserID)
+	if req.PlatformUserID == "" {
+		h.sendJSONError(w, http.StatusBadRequest, "platform_user_id is required")
+		return
+	}
+
+	token, expiresAt, err := h.tokens.Issue(req.PlatformUserID)
+	if err != nil {
+		logger.Error("failed to issue chat token", logger.WithError(err))
+		h.sendJSONError(w, http.StatusInternalServerError, "failed to issue token")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, issueResponse{
+		Token:          token,
+		PlatformUserID: req.PlatformUserID,
+		ExpiresAt:      expiresAt,
+	})
+}
+
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+func (h *Handler) sendJSONError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,77 @@
+package chatauth
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleIssueToken(t *testing.T) {
+	tokens := NewTokens("secret", time.Hour)
+	handler := NewHandler(tokens)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/chat/tokens", bytes.NewReader([]byte(`{"platform_user_id":"u1"}`)))
+	w := httptest.NewRecorder()
+	handler.HandleIssueToken(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp issueResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if userID, err := tokens.Verify(resp.Token); err != nil || userID != "u1" || resp.PlatformUserID != "u1" {
+		t.Errorf("expected a token for u1, got %+v (%v)", resp, err)
+	}
+
+	for _, body := range []string{`{"platform_user_id":`, `{"platform_user_id":"  "}`} {
+		w := httptest.NewRecorder()
+		handler.HandleIssueToken(w, httptest.NewRequest(http.MethodPost, "/api/v1/chat/tokens", bytes.NewReader([]byte(body))))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tokens := NewTokens("secret", time.Hour)
+	token, _, _ := tokens.Issue("u1")
+
+	var seen string
+	protected := tokens.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = User(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		header http.Header
+		status int
+		user   string
+	}{
+		{"bearer token", http.Header{"Authorization": {"Bearer " + token}}, http.StatusOK, "u1"},
+		{"api key header", http.Header{"X-Api-Key": {token}}, http.StatusOK, "u1"},
+		{"missing", nil, http.StatusUnauthorized, ""},
+		{"shared key instead of a token", http.Header{"Authorization": {"Bearer chat-api-key"}}, http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = ""
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/chat/history", nil)
+			for name, values := range tt.header {
+				req.Header[name] = values
+			}
+			w := httptest.NewRecorder()
+			protected.ServeHTTP(w, req)
+
+			if w.Code != tt.status || seen != tt.user {
+				t.Errorf("expected %d for %q, got %d for %q", tt.status, tt.user, w.Code, seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package chatauth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/zjoart/eunoia/internal/middleware"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+type contextKey struct{}
+
+// Middleware requires a user token as a bearer token or in the X-API-Key header, and passes its user
+// on in the request context
+func (t *Tokens) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		platformUserID, err := t.Verify(middleware.RequestAPIKey(r))
+		if err != nil {
+			logger.Warn("rejected request with invalid user token", logger.Fields{
+				"path": r.URL.Path,
+			})
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), platformUserID)))
+	})
+}
+
+// WithUser returns a context carrying the authenticated user's platform user ID
+func WithUser(ctx context.Context, platformUserID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, platformUserID)
+}
+
+// User returns the platform user ID the request's token was issued to, or "" outside Middleware
+func User(ctx context.Context) string {
+	platformUserID, _ := ctx.Value(contextKey{}).(string)
+	return platformUserID
+}
@@ -0,0 +1,80 @@
+package chatauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// claims are the signed part of a token
+type claims struct {
+	PlatformUserID string `json:"sub"`
+	ExpiresAt      int64  `json:"exp"`
+}
+
+// Tokens issues and checks the per-user tokens of the chat API. A token is the base64url JSON claims
+// and their HMAC-SHA256, joined by a dot, so it names its user without any server-side state.
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewTokens(secret string, ttl time.Duration) *Tokens {
+	return &Tokens{
+		secret: []byte(secret),
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Issue returns a token for platformUserID, the user's ID in the app, and when it expires
+func (t *Tokens) Issue(platformUserID string) (string, time.Time, error) {
+	if platformUserID == "" {
+		return "", time.Time{}, errors.New("platform user ID is required")
+	}
+
+	expiresAt := t.now().Add(t.ttl).Truncate(time.Second)
+	payload, err := json.Marshal(claims{PlatformUserID: platformUserID, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + t.sign(encoded), expiresAt, nil
+}
+
+// Verify returns the user a token was issued to, or ErrInvalidToken when it is forged or expired
+func (t *Tokens) Verify(token string) (string, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(t.sign(encoded))) {
+		return "", ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.PlatformUserID == "" {
+		return "", ErrInvalidToken
+	}
+	if !t.now().Before(time.Unix(c.ExpiresAt, 0)) {
+		return "", ErrInvalidToken
+	}
+
+	return c.PlatformUserID, nil
+}
+
+func (t *Tokens) sign(encoded string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,57 @@
+package chatauth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokens_IssueAndVerify(t *testing.T) {
+	tokens := NewTokens("secret", time.Hour)
+	now := time.Unix(1700000000, 0)
+	tokens.now = func() time.Time { return now }
+
+	token, expiresAt, err := tokens.Issue("u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !expiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected the token to expire in an hour, got %v", expiresAt)
+	}
+
+	if userID, err := tokens.Verify(token); err != nil || userID != "u1" {
+		t.Errorf("expected u1, got %q (%v)", userID, err)
+	}
+
+	encoded, signature, _ := strings.Cut(token, ".")
+	other, _, _ := tokens.Issue("u2")
+	otherEncoded, _, _ := strings.Cut(other, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", encoded},
+		{"another user's claims", otherEncoded + "." + signature},
+		{"tampered signature", encoded + "." + strings.Repeat("A", len(signature))},
+		{"other secret", func() string {
+			forged, _, _ := NewTokens("guess", time.Hour).Issue("u1")
+			return forged
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tokens.Verify(tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := tokens.Verify(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected an expired token to be rejected, got %v", err)
+	}
+}
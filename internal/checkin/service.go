@@ -96,3 +96,21 @@ func (s *Service) GenerateMoodInsight(stats *CheckInStats) string {
 
 	return insight
 }
+
+// LabelForScore names a mood score when the user gave no label of their own
+func LabelForScore(score int) string {
+	switch {
+	case score >= 9:
+		return "joyful"
+	case score >= 7:
+		return "content"
+	case score >= 5:
+		return "neutral"
+	case score >= 4:
+		return "low"
+	case score >= 3:
+		return "sad"
+	default:
+		return "very low"
+	}
+}
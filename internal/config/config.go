@@ -2,7 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type DBConfig struct {
@@ -14,14 +19,107 @@ type DBConfig struct {
 }
 
 type AIConfig struct {
-	GeminiAPIKey string
+	GeminiAPIKey    string
+	MaxPromptTokens int
+	DailyTokenQuota int
+	// analysis cache for sentiment and key themes, a size of 0 disables it
+	AnalysisCacheSize     int
+	AnalysisCacheTTLHours int
+	AnalysisCachePersist  bool
 }
 
+type A2AConfig struct {
+	// PublicURL is the base URL advertised in the agent card; the request host is used when empty
+	PublicURL string
+	// APIKey protects the A2A endpoint when set
+	APIKey string
+	// PushSigningSecret signs push notification bodies with HMAC-SHA256; empty disables signing
+	PushSigningSecret  string
+	PushMaxAttempts    int
+	PushTimeoutSeconds int
+	// PushAllowPrivateNetworks lets push URLs point to loopback and private addresses, for local development only
+	PushAllowPrivateNetworks bool
+	// TrustedAgents are the only agents Eunoia may delegate requests to
+	TrustedAgents            []TrustedAgent
+	DelegationTimeoutSeconds int
+	// Platforms are the enabled chat platforms; the first is used when a message names none
+	Platforms []string
+}
+
+type TrustedAgent struct {
+	Name string
+	// URL is the base URL the agent card is discovered under
+	URL string
+	// Token is sent as a bearer token when the agent requires one
+	Token string
+}
+
+// APIConfig protects the REST endpoints over users' private data; they are not served without a key
+type APIConfig struct {
+	Key string
+	// AdminKey protects the operator endpoints, such as the usage report across all users
+	AdminKey string
+}
+
+// ChatConfig protects the chat API used by Eunoia's own apps; the API is not served without a key and a token secret
+type ChatConfig struct {
+	// APIKey lets the app's backend issue user tokens
+	APIKey string
+	// TokenSecret signs the user tokens chat requests are made with
+	TokenSecret     string
+	TokenTTLMinutes int
+	// NudgeIntervalSeconds is how often WebSocket connections check for due reminders; 0 turns nudges off
+	NudgeIntervalSeconds int
+}
+
+// ReminderConfig sets how often due reminders are sent to the platforms that can message users first
+type ReminderConfig struct {
+	// DeliveryIntervalSeconds of 0 stops delivery, and with it the reminder tool on those platforms
+	DeliveryIntervalSeconds int
+}
+
+// SlackConfig enables the Slack Events API adapter when both secrets are set
+type SlackConfig struct {
+	SigningSecret string
+	BotToken      string
+	// APIURL is the Web API base URL, overridable for testing
+	APIURL string
+}
+
+// TelegramConfig enables the Telegram adapter when BotToken is set, served by webhook unless Polling is on
+type TelegramConfig struct {
+	BotToken string
+	// WebhookSecret is the secret_token given to setWebhook; the webhook is not served without it
+	WebhookSecret string
+	APIURL        string
+	// Polling fetches updates with getUpdates instead, for local development
+	Polling bool
+}
+
+// DiscordConfig enables the Discord interactions endpoint when PublicKey is set
+type DiscordConfig struct {
+	// PublicKey is the application's hex encoded Ed25519 key interactions are verified with
+	PublicKey     string
+	ApplicationID string
+	// BotToken registers the slash commands on startup when set
+	BotToken string
+	APIURL   string
+}
+
+var agentNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,40}$`)
+
 type Config struct {
-	AppEnv string
-	Port   string
-	DB     DBConfig
-	AI     AIConfig
+	AppEnv    string
+	Port      string
+	DB        DBConfig
+	AI        AIConfig
+	A2A       A2AConfig
+	API       APIConfig
+	Chat      ChatConfig
+	Reminders ReminderConfig
+	Slack     SlackConfig
+	Telegram  TelegramConfig
+	Discord   DiscordConfig
 }
 
 func LoadConfig() *Config {
@@ -37,7 +135,57 @@ func LoadConfig() *Config {
 
 		AppEnv: getEnv("APP_ENV"),
 		AI: AIConfig{
-			GeminiAPIKey: getEnv("GEMINI_API_KEY"),
+			GeminiAPIKey:    getEnv("GEMINI_API_KEY"),
+			MaxPromptTokens: getEnvInt("AI_MAX_PROMPT_TOKENS", 8000),
+			DailyTokenQuota: getEnvInt("AI_DAILY_TOKEN_QUOTA", 0),
+
+			AnalysisCacheSize:     getEnvInt("AI_ANALYSIS_CACHE_SIZE", 1000),
+			AnalysisCacheTTLHours: getEnvInt("AI_ANALYSIS_CACHE_TTL_HOURS", 168),
+			AnalysisCachePersist:  getEnvBool("AI_ANALYSIS_CACHE_PERSIST", false),
+		},
+		A2A: A2AConfig{
+			PublicURL:          getEnvOrDefault("A2A_PUBLIC_URL", ""),
+			APIKey:             getEnvOrDefault("A2A_API_KEY", ""),
+			PushSigningSecret:  getEnvOrDefault("A2A_PUSH_SIGNING_SECRET", ""),
+			PushMaxAttempts:    getEnvInt("A2A_PUSH_MAX_ATTEMPTS", 3),
+			PushTimeoutSeconds: getEnvInt("A2A_PUSH_TIMEOUT_SECONDS", 10),
+
+			PushAllowPrivateNetworks: getEnvBool("A2A_PUSH_ALLOW_PRIVATE_NETWORKS", false),
+
+			TrustedAgents:            getEnvTrustedAgents("A2A_TRUSTED_AGENTS", "A2A_TRUSTED_AGENT_TOKENS"),
+			DelegationTimeoutSeconds: getEnvInt("A2A_DELEGATION_TIMEOUT_SECONDS", 60),
+
+			Platforms: getEnvList("A2A_PLATFORMS", "telex"),
+		},
+		API: APIConfig{
+			Key:      getEnvOrDefault("API_KEY", ""),
+			AdminKey: getEnvOrDefault("ADMIN_API_KEY", ""),
+		},
+		Chat: ChatConfig{
+			APIKey:               getEnvOrDefault("CHAT_API_KEY", ""),
+			TokenSecret:          getEnvOrDefault("CHAT_TOKEN_SECRET", ""),
+			TokenTTLMinutes:      getEnvInt("CHAT_TOKEN_TTL_MINUTES", 1440),
+			NudgeIntervalSeconds: getEnvInt("CHAT_NUDGE_INTERVAL_SECONDS", 30),
+		},
+		Reminders: ReminderConfig{
+			DeliveryIntervalSeconds: getEnvInt("REMINDER_DELIVERY_INTERVAL_SECONDS", 60),
+		},
+		Slack: SlackConfig{
+			SigningSecret: getEnvOrDefault("SLACK_SIGNING_SECRET", ""),
+			BotToken:      getEnvOrDefault("SLACK_BOT_TOKEN", ""),
+			APIURL:        getEnvOrDefault("SLACK_API_URL", "https://slack.com/api"),
+		},
+		Telegram: TelegramConfig{
+			BotToken:      getEnvOrDefault("TELEGRAM_BOT_TOKEN", ""),
+			WebhookSecret: getEnvOrDefault("TELEGRAM_WEBHOOK_SECRET", ""),
+			APIURL:        getEnvOrDefault("TELEGRAM_API_URL", "https://api.telegram.org"),
+			Polling:       getEnvBool("TELEGRAM_POLLING", false),
+		},
+		Discord: DiscordConfig{
+			PublicKey:     getEnvOrDefault("DISCORD_PUBLIC_KEY", ""),
+			ApplicationID: getEnvOrDefault("DISCORD_APPLICATION_ID", ""),
+			BotToken:      getEnvOrDefault("DISCORD_BOT_TOKEN", ""),
+			APIURL:        getEnvOrDefault("DISCORD_API_URL", "https://discord.com/api/v10"),
 		},
 	}
 
@@ -51,3 +199,102 @@ func getEnv(key string) string {
 
 	panic(fmt.Sprintf("%s is required", key))
 }
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := getEnvOrDefault(key, "")
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be an integer", key))
+	}
+
+	return parsed
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := getEnvOrDefault(key, "")
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be a boolean", key))
+	}
+
+	return parsed
+}
+
+// getEnvList parses a comma separated list, lowercasing entries and dropping empty ones
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for _, entry := range strings.Split(getEnvOrDefault(key, defaultValue), ",") {
+		if entry = strings.ToLower(strings.TrimSpace(entry)); entry != "" {
+			values = append(values, entry)
+		}
+	}
+
+	if len(values) == 0 {
+		panic(fmt.Sprintf("%s must list at least one value", key))
+	}
+	return values
+}
+
+// getEnvTrustedAgents parses comma separated name=url pairs, with bearer tokens as name=token pairs in tokensKey
+func getEnvTrustedAgents(key, tokensKey string) []TrustedAgent {
+	tokens := parsePairs(tokensKey)
+
+	urls := parsePairs(key)
+	names := make([]string, 0, len(urls))
+	for name := range urls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var agents []TrustedAgent
+	for _, name := range names {
+		value := urls[name]
+		if !agentNamePattern.MatchString(name) {
+			panic(fmt.Sprintf("%s: agent name %q must be lowercase letters, digits, - or _", key, name))
+		}
+
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			panic(fmt.Sprintf("%s: agent %s needs an http or https URL", key, name))
+		}
+
+		agents = append(agents, TrustedAgent{Name: name, URL: value, Token: tokens[name]})
+	}
+
+	return agents
+}
+
+func parsePairs(key string) map[string]string {
+	pairs := make(map[string]string)
+
+	for _, entry := range strings.Split(getEnvOrDefault(key, ""), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(name) == "" || strings.TrimSpace(value) == "" {
+			panic(fmt.Sprintf("%s entries must look like name=value", key))
+		}
+		pairs[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return pairs
+}
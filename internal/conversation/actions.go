@@ -0,0 +1,392 @@
+package conversation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/checkin"
+	"github.com/zjoart/eunoia/internal/memory"
+	"github.com/zjoart/eunoia/internal/reflection"
+	"github.com/zjoart/eunoia/internal/reminder"
+	"github.com/zjoart/eunoia/pkg/id"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+const (
+	// how long a proposed action waits for the user's yes or no
+	actionConfirmationWindow = 15 * time.Minute
+	minReflectionWords       = 15
+	reminderTimeLayout       = "Mon, Jan 2 at 3:04 PM"
+)
+
+var affirmativeReplies = map[string]bool{
+	"y": true, "yes": true, "yeah": true, "yep": true, "yup": true, "sure": true, "ok": true, "okay": true,
+	"please": true, "yes please": true, "go ahead": true, "do it": true, "confirm": true, "sounds good": true,
+}
+
+var negativeReplies = map[string]bool{
+	"n": true, "no": true, "nope": true, "nah": true, "no thanks": true, "no thank you": true, "don't": true,
+	"dont": true, "cancel": true, "never mind": true, "nevermind": true, "not now": true,
+}
+
+// registerActionTools adds the write tools, which only run after the user confirms them
+func (s *Service) registerActionTools(registry *agent.ToolRegistry) {
+	tools := []*agent.Tool{
+		{
+			Name:        "log_checkin",
+			Description: "Propose logging a mood check-in for the user. The user is asked to confirm before it is saved.",
+			Parameters: map[string]*agent.ToolParameter{
+				"mood_score":  {Type: agent.ParamInteger, Description: "Mood from 1 (very low) to 10 (great).", Required: true},
+				"mood_label":  {Type: agent.ParamString, Description: "One or two words describing the mood, e.g. anxious, content."},
+				"description": {Type: agent.ParamString, Description: "What the check-in is about. Defaults to the user's current message."},
+			},
+			RequiresConfirmation: true,
+			Prepare:              prepareCheckIn,
+			Handler:              s.actionLogCheckIn,
+		},
+		{
+			Name:        "save_reflection",
+			Description: "Propose saving the user's current message as a journal reflection. The user is asked to confirm before it is saved.",
+			Parameters: map[string]*agent.ToolParameter{
+				"content": {Type: agent.ParamString, Description: "Reflection text. Defaults to the user's current message."},
+			},
+			RequiresConfirmation: true,
+			Prepare:              prepareReflection,
+			Handler:              s.actionSaveReflection,
+		},
+		{
+			Name:        "schedule_reminder",
+			Description: "Propose a reminder sent to the user later. The user is asked to confirm before it is scheduled.",
+			Parameters: map[string]*agent.ToolParameter{
+				"note":       {Type: agent.ParamString, Description: "What to remind the user about.", Required: true},
+				"in_minutes": {Type: agent.ParamInteger, Description: "Minutes from now. Use this or remind_at."},
+				"remind_at":  {Type: agent.ParamString, Description: "Exact time in RFC 3339 format, e.g. 2025-01-02T09:00:00Z."},
+			},
+			RequiresConfirmation: true,
+			Prepare:              prepareReminder,
+			Handler:              s.actionScheduleReminder,
+		},
+	}
+	if s.factService != nil {
+		tools = append(tools, s.forgetFactTool())
+	}
+
+	for _, tool := range tools {
+		if err := registry.Register(tool); err != nil {
+			logger.Error("failed to register tool", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{"tool": tool.Name},
+			))
+		}
+	}
+
+	registry.SetConfirmationHandler(s.proposeAction)
+}
+
+func prepareCheckIn(ctx *agent.ToolContext, args agent.ToolArgs) (agent.ToolArgs, string, error) {
+	score := args.Int("mood_score", 0)
+	if score < 1 || score > 10 {
+		return nil, "", fmt.Errorf("mood_score must be between 1 and 10")
+	}
+
+	label := strings.ToLower(args.String("mood_label"))
+	if label == "" {
+		label = checkin.LabelForScore(score)
+	}
+
+	description := args.String("description")
+	if description == "" {
+		description = ctx.Message
+	}
+
+	prepared := agent.ToolArgs{"mood_score": score, "mood_label": label, "description": description}
+	return prepared, fmt.Sprintf("log your mood as %d/10 (%s)", score, label), nil
+}
+
+func prepareReflection(ctx *agent.ToolContext, args agent.ToolArgs) (agent.ToolArgs, string, error) {
+	content := args.String("content")
+	if content == "" {
+		content = strings.TrimSpace(ctx.Message)
+	}
+
+	if len(strings.Fields(content)) < minReflectionWords {
+		return nil, "", fmt.Errorf("reflection is too short to save")
+	}
+
+	return agent.ToolArgs{"content": content}, fmt.Sprintf("save \"%s\" as a reflection", truncateRunes(content, 60)), nil
+}
+
+func prepareReminder(ctx *agent.ToolContext, args agent.ToolArgs) (agent.ToolArgs, string, error) {
+	now := time.Now()
+
+	var remindAt time.Time
+	if minutes := args.Int("in_minutes", 0); minutes > 0 {
+		remindAt = now.Add(time.Duration(minutes) * time.Minute)
+	} else if value := args.String("remind_at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, "", fmt.Errorf("remind_at must be an RFC 3339 time")
+		}
+		remindAt = parsed
+	} else {
+		return nil, "", fmt.Errorf("either in_minutes or remind_at is required")
+	}
+
+	note, err := reminder.Validate(args.String("note"), remindAt, now)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prepared := agent.ToolArgs{"note": note, "remind_at": remindAt.Format(time.RFC3339)}
+	return prepared, fmt.Sprintf("remind you to %s on %s", note, remindAt.Format(reminderTimeLayout)), nil
+}
+
+func (s *Service) actionLogCheckIn(ctx *agent.ToolContext, args agent.ToolArgs) (any, error) {
+	created, err := s.checkInService.CreateCheckIn(&checkin.CreateCheckInRequest{
+		PlatformUserID: ctx.PlatformUserID,
+		MoodScore:      args.Int("mood_score", 0),
+		MoodLabel:      args.String("mood_label"),
+		Description:    args.String("description"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"check_in_id": created.ID,
+		"mood_score":  created.MoodScore,
+		"mood_label":  created.MoodLabel,
+		"message":     fmt.Sprintf("Done - I've logged your mood as %d/10 (%s).", created.MoodScore, created.MoodLabel),
+	}, nil
+}
+
+func (s *Service) actionSaveReflection(ctx *agent.ToolContext, args agent.ToolArgs) (any, error) {
+	created, err := s.reflectionService.CreateReflection(&reflection.CreateReflectionRequest{
+		PlatformUserID: ctx.PlatformUserID,
+		Content:        args.String("content"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.rememberAsync(created.UserID, memory.SourceReflection, created.ID, created.Content, created.CreatedAt)
+
+	message := "Done - I've saved that as a reflection."
+	if created.AIAnalysis != "" {
+		message += "\n\n" + created.AIAnalysis
+	}
+
+	return map[string]any{
+		"reflection_id": created.ID,
+		"sentiment":     created.Sentiment,
+		"key_themes":    created.KeyThemes,
+		"analysis":      created.AIAnalysis,
+		"message":       message,
+	}, nil
+}
+
+func (s *Service) actionScheduleReminder(ctx *agent.ToolContext, args agent.ToolArgs) (any, error) {
+	if s.reminderService == nil {
+		return nil, fmt.Errorf("reminders are not available")
+	}
+
+	remindAt, err := time.Parse(time.RFC3339, args.String("remind_at"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid reminder time")
+	}
+
+	created, err := s.reminderService.Schedule(ctx.UserID, args.String("note"), remindAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"reminder_id": created.ID,
+		"message":     fmt.Sprintf("Done - I'll remind you to %s on %s.", created.Note, created.RemindAt.Format(reminderTimeLayout)),
+	}, nil
+}
+
+// proposeAction records a write tool call as pending so the user can confirm it on their next message
+func (s *Service) proposeAction(ctx *agent.ToolContext, tool *agent.Tool, args agent.ToolArgs, summary string) (any, error) {
+	arguments, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record action")
+	}
+
+	// only the most recent proposal can be confirmed
+	if err := s.repo.ExpirePendingToolInvocations(ctx.UserID, time.Now()); err != nil {
+		logger.Warn("failed to expire pending actions", logger.Merge(
+			logger.WithError(err),
+			logger.WithUserID(ctx.UserID),
+		))
+	}
+
+	invocation := &ToolInvocation{
+		ID:        id.Generate(),
+		UserID:    ctx.UserID,
+		MessageID: ctx.MessageID,
+		ToolName:  tool.Name,
+		Arguments: string(arguments),
+		Summary:   summary,
+		Status:    InvocationPending,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.repo.CreateToolInvocation(invocation); err != nil {
+		return nil, fmt.Errorf("failed to record action")
+	}
+
+	logger.Info("proposed tool action", logger.Fields{
+		"user_id":       ctx.UserID,
+		"tool":          tool.Name,
+		"invocation_id": invocation.ID,
+	})
+
+	return map[string]any{
+		"status":      "awaiting_confirmation",
+		"action":      summary,
+		"instruction": "Nothing has been done yet. Ask the user whether you should " + summary + ", and let them answer yes or no.",
+	}, nil
+}
+
+// handleActionConfirmation runs or declines the pending action when the user answers yes or no,
+// returning the reply and, for an action that ran, its result
+func (s *Service) handleActionConfirmation(userID, platformUserID, messageID, message string) (string, map[string]any, bool) {
+	if s.tools == nil {
+		return "", nil, false
+	}
+
+	pending, err := s.repo.GetPendingToolInvocation(userID, time.Now().Add(-actionConfirmationWindow))
+	if err != nil {
+		logger.Warn("failed to load pending action", logger.Merge(
+			logger.WithError(err),
+			logger.WithUserID(userID),
+		))
+		return "", nil, false
+	}
+	if pending == nil {
+		return "", nil, false
+	}
+
+	switch confirmationReply(message) {
+	case "yes":
+		reply, result := s.executeAction(pending, &agent.ToolContext{
+			UserID:         userID,
+			PlatformUserID: platformUserID,
+			MessageID:      messageID,
+			Message:        message,
+		})
+		return reply, result, true
+	case "no":
+		if !s.resolveAction(pending, InvocationPending, InvocationDeclined, "", "") {
+			// a "yes" sent just before claimed it first
+			return "", nil, false
+		}
+		return fmt.Sprintf("Okay, I won't %s.", pending.Summary), nil, true
+	default:
+		// the user moved on, so a later "yes" must not trigger this action
+		s.resolveAction(pending, InvocationPending, InvocationExpired, "", "")
+		return "", nil, false
+	}
+}
+
+// executeAction claims the invocation before running it, so a retried or repeated "yes" that loaded the
+// same pending row never runs the action a second time
+func (s *Service) executeAction(pending *ToolInvocation, ctx *agent.ToolContext) (string, map[string]any) {
+	failure := fmt.Sprintf("Sorry, I couldn't %s just now. Could you try again in a little while?", pending.Summary)
+
+	claimed, err := s.repo.ClaimToolInvocation(pending.ID)
+	if err != nil {
+		logger.Warn("failed to claim confirmed action", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"invocation_id": pending.ID},
+		))
+		return failure, nil
+	}
+	if !claimed {
+		logger.Info("confirmed action already claimed", logger.Fields{"invocation_id": pending.ID})
+		return fmt.Sprintf("That one's already in hand, so I'll only %s once.", pending.Summary), nil
+	}
+
+	var args agent.ToolArgs
+	if err := json.Unmarshal([]byte(pending.Arguments), &args); err != nil {
+		s.resolveAction(pending, InvocationExecuting, InvocationFailed, "", "invalid stored arguments")
+		return failure, nil
+	}
+
+	result, err := s.tools.ExecuteConfirmed(ctx, pending.ToolName, args)
+	if err != nil {
+		logger.Warn("confirmed action failed", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{
+				"tool":          pending.ToolName,
+				"invocation_id": pending.ID,
+			},
+		))
+		s.resolveAction(pending, InvocationExecuting, InvocationFailed, "", err.Error())
+		return failure, nil
+	}
+
+	encoded, _ := json.Marshal(result)
+	s.resolveAction(pending, InvocationExecuting, InvocationExecuted, string(encoded), "")
+
+	logger.Info("executed confirmed action", logger.Fields{
+		"user_id":       ctx.UserID,
+		"tool":          pending.ToolName,
+		"invocation_id": pending.ID,
+	})
+
+	if message, ok := result["message"].(string); ok && message != "" {
+		return message, result
+	}
+	return "Done.", result
+}
+
+// resolveAction moves the invocation from its current status to its final one, reporting whether it did
+func (s *Service) resolveAction(pending *ToolInvocation, from, status, result, errorMessage string) bool {
+	resolved, err := s.repo.ResolveToolInvocation(pending.ID, from, status, result, errorMessage, time.Now())
+	if err != nil {
+		logger.Warn("failed to resolve action", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{
+				"invocation_id": pending.ID,
+				"status":        status,
+			},
+		))
+	}
+	return resolved
+}
+
+// confirmationReply classifies a short reply as "yes", "no" or "" when it is neither
+func confirmationReply(message string) string {
+	reply := strings.Trim(strings.ToLower(strings.TrimSpace(message)), "!.,? ")
+	reply = strings.Join(strings.Fields(reply), " ")
+
+	switch {
+	case affirmativeReplies[reply]:
+		return "yes"
+	case negativeReplies[reply]:
+		return "no"
+	}
+
+	return ""
+}
+
+// actionHints points the model at write tools the user's message might call for, instead of acting on keywords
+func (s *Service) actionHints(message string) []string {
+	messageLower := strings.ToLower(message)
+
+	var hints []string
+	if score, label := s.detectMoodIntent(messageLower); score > 0 {
+		hints = append(hints, fmt.Sprintf("The user described their mood (roughly %d/10, %s). If it feels natural, offer to log a check-in with log_checkin.", score, label))
+	}
+
+	if len(strings.Fields(message)) >= minReflectionWords && s.isReflectionIntent(messageLower) {
+		hints = append(hints, "This message reads like a reflection. If it feels natural, offer to save it with save_reflection.")
+	}
+
+	return hints
+}
@@ -0,0 +1,268 @@
+package conversation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/checkin"
+	"github.com/zjoart/eunoia/internal/user"
+)
+
+var toolInvocationColumns = []string{"id", "user_id", "message_id", "tool_name", "arguments", "summary", "status", "created_at"}
+
+func TestConfirmationReply(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{"Yes", "yes"},
+		{"yes please!", "yes"},
+		{"  go   ahead ", "yes"},
+		{"No.", "no"},
+		{"not now", "no"},
+		{"yes, but first tell me about my week", ""},
+		{"I had a long day", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := confirmationReply(tt.message); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPrepareCheckIn(t *testing.T) {
+	ctx := &agent.ToolContext{Message: "Work was exhausting today"}
+
+	args, summary, err := prepareCheckIn(ctx, agent.ToolArgs{"mood_score": float64(3)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary != "log your mood as 3/10 (sad)" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	if args.String("description") != ctx.Message {
+		t.Errorf("expected description to default to the message, got %q", args.String("description"))
+	}
+
+	if _, _, err := prepareCheckIn(ctx, agent.ToolArgs{"mood_score": float64(11)}); err == nil {
+		t.Error("expected out of range score to be rejected")
+	}
+}
+
+func TestPrepareReflection_RequiresEnoughWords(t *testing.T) {
+	if _, _, err := prepareReflection(&agent.ToolContext{Message: "too short"}, agent.ToolArgs{}); err == nil {
+		t.Error("expected a short reflection to be rejected")
+	}
+
+	message := strings.Repeat("today I noticed how much calmer I feel ", 3)
+	args, _, err := prepareReflection(&agent.ToolContext{Message: message}, agent.ToolArgs{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args.String("content") != strings.TrimSpace(message) {
+		t.Errorf("expected content to default to the message, got %q", args.String("content"))
+	}
+}
+
+func TestPrepareReminder(t *testing.T) {
+	args, summary, err := prepareReminder(&agent.ToolContext{}, agent.ToolArgs{"note": "take a walk", "in_minutes": float64(90)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	remindAt, err := time.Parse(time.RFC3339, args.String("remind_at"))
+	if err != nil {
+		t.Fatalf("expected an RFC 3339 time, got %q", args.String("remind_at"))
+	}
+	if until := time.Until(remindAt); until < 85*time.Minute || until > 95*time.Minute {
+		t.Errorf("expected reminder in about 90 minutes, got %v", until)
+	}
+	if !strings.HasPrefix(summary, "remind you to take a walk on ") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	if _, _, err := prepareReminder(&agent.ToolContext{}, agent.ToolArgs{"note": "x", "remind_at": past}); err == nil {
+		t.Error("expected a reminder in the past to be rejected")
+	}
+	if _, _, err := prepareReminder(&agent.ToolContext{}, agent.ToolArgs{"note": "x"}); err == nil {
+		t.Error("expected a reminder without a time to be rejected")
+	}
+}
+
+func TestHandleActionConfirmation_Yes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	userRepo := user.NewRepository(db)
+	service := &Service{
+		repo:           NewRepository(db),
+		checkInService: checkin.NewService(checkin.NewRepository(db), userRepo),
+	}
+	service.tools = service.newToolRegistry()
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM tool_invocations").
+		WithArgs("user-1", InvocationPending, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(toolInvocationColumns).
+			AddRow("inv-1", "user-1", "msg-1", "log_checkin",
+				`{"description":"rough day","mood_label":"low","mood_score":4}`, "log your mood as 4/10 (low)", InvocationPending, now))
+	mock.ExpectExec("UPDATE tool_invocations SET status").
+		WithArgs(InvocationExecuting, "inv-1", InvocationPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT (.+) FROM users").
+		WithArgs("platform-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "platform_user_id", "username", "created_at", "updated_at"}).
+			AddRow("user-1", "platform-1", "", now, now))
+	mock.ExpectExec("INSERT INTO emotional_checkins").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE tool_invocations").
+		WithArgs(InvocationExecuted, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "inv-1", InvocationExecuting).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	reply, result, handled := service.handleActionConfirmation("user-1", "platform-1", "msg-2", "yes")
+	if !handled {
+		t.Fatal("expected the confirmation to be handled")
+	}
+	if reply != "Done - I've logged your mood as 4/10 (low)." {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if result["check_in_id"] == nil || result["mood_score"] != float64(4) {
+		t.Errorf("expected the created check-in in the result, got %v", result)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestHandleActionConfirmation_AlreadyClaimed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := &Service{repo: NewRepository(db)}
+	service.tools = agent.NewToolRegistry()
+
+	called := false
+	service.tools.Register(&agent.Tool{
+		Name:                 "log_checkin",
+		RequiresConfirmation: true,
+		Handler: func(ctx *agent.ToolContext, args agent.ToolArgs) (any, error) {
+			called = true
+			return map[string]any{"message": "Done."}, nil
+		},
+	})
+
+	// a retried delivery of the same "yes" loaded the row before the first one claimed it
+	mock.ExpectQuery("SELECT (.+) FROM tool_invocations").
+		WithArgs("user-1", InvocationPending, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(toolInvocationColumns).
+			AddRow("inv-1", "user-1", "msg-1", "log_checkin", `{}`, "log your mood as 4/10 (low)", InvocationPending, time.Now()))
+	mock.ExpectExec("UPDATE tool_invocations SET status").
+		WithArgs(InvocationExecuting, "inv-1", InvocationPending).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reply, result, handled := service.handleActionConfirmation("user-1", "platform-1", "msg-2", "yes")
+	if !handled {
+		t.Fatal("expected the confirmation to be handled")
+	}
+	if called || result != nil {
+		t.Errorf("expected the tool not to run again, got result %v", result)
+	}
+	if reply != "That one's already in hand, so I'll only log your mood as 4/10 (low) once." {
+		t.Errorf("unexpected reply %q", reply)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestHandleActionConfirmation_No(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := &Service{repo: NewRepository(db)}
+	service.tools = service.newToolRegistry()
+
+	mock.ExpectQuery("SELECT (.+) FROM tool_invocations").
+		WithArgs("user-1", InvocationPending, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(toolInvocationColumns).
+			AddRow("inv-1", "user-1", "", "schedule_reminder", `{}`, "remind you to stretch on Mon, Jan 6 at 9:00 AM", InvocationPending, time.Now()))
+	mock.ExpectExec("UPDATE tool_invocations").
+		WithArgs(InvocationDeclined, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "inv-1", InvocationPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	reply, _, handled := service.handleActionConfirmation("user-1", "platform-1", "msg-2", "no thanks")
+	if !handled {
+		t.Fatal("expected the decline to be handled")
+	}
+	if reply != "Okay, I won't remind you to stretch on Mon, Jan 6 at 9:00 AM." {
+		t.Errorf("unexpected reply %q", reply)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestHandleActionConfirmation_OtherReplyExpiresAction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := &Service{repo: NewRepository(db)}
+	service.tools = service.newToolRegistry()
+
+	mock.ExpectQuery("SELECT (.+) FROM tool_invocations").
+		WillReturnRows(sqlmock.NewRows(toolInvocationColumns).
+			AddRow("inv-1", "user-1", "", "log_checkin", `{}`, "log your mood as 4/10 (low)", InvocationPending, time.Now()))
+	mock.ExpectExec("UPDATE tool_invocations").
+		WithArgs(InvocationExpired, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "inv-1", InvocationPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, _, handled := service.handleActionConfirmation("user-1", "platform-1", "msg-2", "Actually, can we talk about work?"); handled {
+		t.Error("expected an unrelated reply to fall through to the conversation")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestActionHints(t *testing.T) {
+	service := &Service{}
+
+	hints := service.actionHints("I'm feeling stressed about exams")
+	if len(hints) != 1 || !strings.Contains(hints[0], "log_checkin") {
+		t.Errorf("expected a check-in hint, got %v", hints)
+	}
+
+	reflective := "Looking back on this month I realized how much my evening walks have helped me sleep and feel calmer overall"
+	hints = service.actionHints(reflective)
+	if len(hints) != 1 || !strings.Contains(hints[0], "save_reflection") {
+		t.Errorf("expected a reflection hint, got %v", hints)
+	}
+
+	if hints := service.actionHints("What's a good breathing exercise?"); len(hints) != 0 {
+		t.Errorf("expected no hints, got %v", hints)
+	}
+}
@@ -0,0 +1,86 @@
+package conversation
+
+import (
+	"strings"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+const (
+	newUserContext = "New user - no previous history"
+	memoriesHeader = "Things the user shared before that may be relevant:"
+	// share of the budget a single pasted message may take before it is cut
+	maxMessageBudgetShare = 2
+)
+
+type preparedPrompt struct {
+	systemPrompt string
+	context      string
+	message      string
+	history      []string
+}
+
+// preparePrompt assembles the prompt, trimming lower-priority sections to fit the token budget.
+// Recalled memories go first, then the oldest history, then background context; search results
+// the user explicitly asked for are trimmed last.
+func (s *Service) preparePrompt(userID, message string, contextParts, memories []string, searchContext string, history []string) *preparedPrompt {
+	var searchItems []string
+	if searchContext != "" {
+		searchItems = []string{searchContext}
+	}
+
+	searchSection := &agent.PromptSection{Name: "search", Priority: 1, Items: searchItems}
+	contextSection := &agent.PromptSection{Name: "context", Priority: 2, Items: contextParts}
+	historySection := &agent.PromptSection{Name: "history", Priority: 3, Items: history, MinItems: 2, TrimOldest: true}
+	memorySection := &agent.PromptSection{Name: "memories", Priority: 4, Items: memories}
+
+	if s.promptBudget != nil {
+		maxMessageTokens := s.promptBudget.MaxTokens() / maxMessageBudgetShare
+		if truncated, ok := s.promptBudget.Truncate(message, maxMessageTokens); ok {
+			logger.Warn("truncated long message to fit prompt budget", logger.Fields{
+				"user_id":            userID,
+				"original_tokens":    s.promptBudget.EstimateTokens(message),
+				"max_message_tokens": maxMessageTokens,
+			})
+			message = truncated + "\n[message truncated]"
+		}
+
+		fixed := s.buildSystemPrompt(newUserContext) + message
+		allocation := s.promptBudget.Allocate(fixed, searchSection, contextSection, historySection, memorySection)
+
+		logger.Info("prompt budget allocated", logger.Fields{
+			"user_id":                 userID,
+			"estimated_prompt_tokens": allocation.EstimatedTokens,
+			"max_prompt_tokens":       s.promptBudget.MaxTokens(),
+			"trimmed":                 allocation.Trimmed,
+			"over_budget":             allocation.OverBudget,
+		})
+	}
+
+	context := renderContext(contextSection.Items, memorySection.Items, searchSection.Items)
+
+	return &preparedPrompt{
+		systemPrompt: s.buildSystemPrompt(context),
+		context:      context,
+		message:      message,
+		history:      historySection.Items,
+	}
+}
+
+func renderContext(contextParts, memories, searchItems []string) string {
+	context := newUserContext
+	if len(contextParts) > 0 {
+		context = strings.Join(contextParts, "\n")
+	}
+
+	if len(memories) > 0 {
+		context += "\n\n" + memoriesHeader + "\n" + strings.Join(memories, "\n")
+	}
+
+	for _, item := range searchItems {
+		context += "\n\n" + item
+	}
+
+	return context
+}
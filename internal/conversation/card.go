@@ -0,0 +1,106 @@
+package conversation
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/zjoart/eunoia/internal/middleware"
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
+const (
+	agentName        = "Eunoia"
+	agentVersion     = "1.0.0"
+	agentDescription = "A compassionate AI assistant for mental wellbeing that performs emotional check-ins, analyzes reflections, and provides supportive, context-aware responses. It is not a replacement for professional mental health care."
+	a2aPath          = "/a2a/agent/eunoia"
+)
+
+// AgentCardConfig holds the deployment details the agent card advertises
+type AgentCardConfig struct {
+	// PublicURL is the externally reachable base URL; when empty it is taken from the request
+	PublicURL string
+	// APIKeyRequired advertises the bearer and X-API-Key schemes the A2A endpoint checks
+	APIKeyRequired bool
+}
+
+var agentSkills = []a2a.AgentSkill{
+	{
+		ID:          "emotional-support",
+		Name:        "Emotional support",
+		Description: "Listens and responds with empathy, drawing on earlier conversations and what the user has shared before.",
+		Tags:        []string{"wellbeing", "conversation", "support"},
+		Examples:    []string{"I'm feeling anxious about tomorrow", "Today was a really good day"},
+	},
+	{
+		ID:          "mood-check-in",
+		Name:        "Mood check-in",
+		Description: "Logs a daily mood check-in on a 1-10 scale once the user confirms it.",
+		Tags:        []string{"mood-tracking", "check-in"},
+		Examples:    []string{"I'm feeling about a 6 today", "Log my mood as low"},
+	},
+	{
+		ID:          "reflection",
+		Name:        "Reflection journaling",
+		Description: "Saves journal reflections and analyzes their sentiment and key themes.",
+		Tags:        []string{"journaling", "reflection", "sentiment"},
+		Examples:    []string{"I want to reflect on my week"},
+	},
+	{
+		ID:          "mood-insights",
+		Name:        "Mood insights",
+		Description: "Answers questions about mood trends, past check-ins and earlier reflections.",
+		Tags:        []string{"insights", "history", "search"},
+		Examples:    []string{"How has my mood been this week?", "What did I write about work last month?"},
+	},
+}
+
+// AgentCard describes the agent at baseURL from the methods and auth it actually supports
+func (h *Handler) AgentCard(baseURL string, cfg AgentCardConfig) *a2a.AgentCard {
+	card := &a2a.AgentCard{
+		ProtocolVersion:    a2a.ProtocolVersion,
+		Name:               agentName,
+		Description:        agentDescription,
+		URL:                strings.TrimRight(baseURL, "/") + a2aPath,
+		PreferredTransport: a2a.TransportJSONRPC,
+		Version:            agentVersion,
+		Capabilities:       a2a.Capabilities(h.Methods()),
+		DefaultInputModes:  []string{a2a.MediaTypeText, a2a.MediaTypeJSON, "image/png", "image/jpeg", "image/webp"},
+		DefaultOutputModes: []string{a2a.MediaTypeText, a2a.MediaTypeJSON},
+		Skills:             agentSkills,
+	}
+
+	if cfg.APIKeyRequired {
+		card.SecuritySchemes = map[string]a2a.SecurityScheme{
+			"bearer": {Type: "http", Scheme: "bearer", Description: "API key sent as a bearer token"},
+			"apiKey": {Type: "apiKey", In: "header", Name: middleware.APIKeyHeader, Description: "API key sent in a header"},
+		}
+		card.Security = []map[string][]string{{"bearer": {}}, {"apiKey": {}}}
+	}
+
+	return card
+}
+
+// HandleAgentCard serves the agent card for discovery
+func (h *Handler) HandleAgentCard(cfg AgentCardConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		baseURL := cfg.PublicURL
+		if baseURL == "" {
+			baseURL = requestBaseURL(r)
+		}
+
+		h.sendJSON(w, http.StatusOK, h.AgentCard(baseURL, cfg))
+	}
+}
+
+// requestBaseURL rebuilds the public base URL, honouring the proxy's forwarded scheme
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := r.Header.Get("X-Forwarded-Proto"); forwarded != "" {
+		scheme = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,184 @@
+package conversation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zjoart/eunoia/internal/conversation/platforms"
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
+func TestAgentCard_MatchesSchema(t *testing.T) {
+	data, err := os.ReadFile("testdata/agent-card.schema.json")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	handlers := map[string]*Handler{
+		"minimal":   NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil),
+		"all":       NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), NewMockTaskService(), NewMockPushService()),
+		"protected": NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), NewMockTaskService(), nil),
+	}
+
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			cfg := AgentCardConfig{APIKeyRequired: name == "protected"}
+			card, err := json.Marshal(handler.AgentCard("https://eunoia.example.com", cfg))
+			if err != nil {
+				t.Fatalf("failed to encode card: %v", err)
+			}
+
+			var document any
+			json.Unmarshal(card, &document)
+
+			if errs := validateSchema(schema, schema, document, "card"); len(errs) > 0 {
+				t.Errorf("agent card does not match the A2A schema:\n%s", strings.Join(errs, "\n"))
+			}
+		})
+	}
+}
+
+func TestAgentCard_Capabilities(t *testing.T) {
+	minimal := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil).AgentCard("http://localhost", AgentCardConfig{})
+	if minimal.Capabilities.PushNotifications || minimal.Capabilities.Streaming {
+		t.Errorf("expected no optional capabilities, got %+v", minimal.Capabilities)
+	}
+	if minimal.SecuritySchemes != nil {
+		t.Errorf("expected no security schemes without an API key")
+	}
+
+	full := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), NewMockTaskService(), NewMockPushService()).
+		AgentCard("http://localhost", AgentCardConfig{APIKeyRequired: true})
+	if !full.Capabilities.PushNotifications {
+		t.Error("expected push notifications to be advertised")
+	}
+	if len(full.Security) != 2 || full.SecuritySchemes["bearer"].Scheme != "bearer" {
+		t.Errorf("expected bearer and API key schemes, got %+v", full.SecuritySchemes)
+	}
+}
+
+func TestHandleAgentCard_URLFromRequest(t *testing.T) {
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/agent-card.json", nil)
+	req.Host = "eunoia.example.com"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w := httptest.NewRecorder()
+
+	handler.HandleAgentCard(AgentCardConfig{})(w, req)
+
+	var card a2a.AgentCard
+	if err := json.NewDecoder(w.Body).Decode(&card); err != nil {
+		t.Fatalf("failed to decode card: %v", err)
+	}
+
+	if card.URL != "https://eunoia.example.com/a2a/agent/eunoia" {
+		t.Errorf("unexpected URL %s", card.URL)
+	}
+}
+
+// validateSchema checks a decoded JSON document against the JSON schema keywords used in testdata
+func validateSchema(root, schema map[string]any, value any, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/definitions/")
+		definition := root["definitions"].(map[string]any)[name].(map[string]any)
+		return validateSchema(root, definition, value, path)
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		for _, option := range anyOf {
+			if len(validateSchema(root, option.(map[string]any), value, path)) == 0 {
+				return nil
+			}
+		}
+		return []string{fmt.Sprintf("%s: matches none of the allowed schemas", path)}
+	}
+
+	var errs []string
+
+	if expected, ok := schema["type"].(string); ok && !hasJSONType(value, expected) {
+		return []string{fmt.Sprintf("%s: expected %s, got %T", path, expected, value)}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, allowed := range enum {
+			if allowed == value {
+				found = true
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+		}
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		for _, required := range asSlice(schema["required"]) {
+			if _, ok := typed[required.(string)]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required property %s", path, required))
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]any)
+		for key, child := range typed {
+			childPath := path + "." + key
+			if propertySchema, ok := properties[key].(map[string]any); ok {
+				errs = append(errs, validateSchema(root, propertySchema, child, childPath)...)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					errs = append(errs, fmt.Sprintf("%s: property is not allowed", childPath))
+				}
+			case map[string]any:
+				errs = append(errs, validateSchema(root, additional, child, childPath)...)
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range typed {
+				errs = append(errs, validateSchema(root, items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	return errs
+}
+
+func hasJSONType(value any, expected string) bool {
+	switch expected {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number", "integer":
+		_, ok := value.(float64)
+		return ok
+	}
+	return true
+}
+
+func asSlice(value any) []any {
+	slice, _ := value.([]any)
+	return slice
+}
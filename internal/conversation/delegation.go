@@ -0,0 +1,73 @@
+package conversation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// longest request text forwarded to another agent
+const maxDelegationRequestLength = 1000
+
+// registerDelegationTool lets the model hand requests outside Eunoia's scope to a trusted agent,
+// once the user agrees to the text being shared
+func (s *Service) registerDelegationTool(registry *agent.ToolRegistry) {
+	if s.delegationService == nil || len(s.delegationService.Names()) == 0 {
+		return
+	}
+
+	names := s.delegationService.Names()
+	tool := &agent.Tool{
+		Name: "delegate_to_agent",
+		Description: "Propose asking another trusted agent (" + strings.Join(names, ", ") + ") to handle a request outside your scope, " +
+			"such as a guided meditation or scheduling. Only the request text is shared, and the user is asked to confirm first.",
+		Parameters: map[string]*agent.ToolParameter{
+			"agent":   {Type: agent.ParamString, Description: "Which agent to ask.", Required: true, Enum: names},
+			"request": {Type: agent.ParamString, Description: "What to ask the agent, written without personal details.", Required: true},
+		},
+		RequiresConfirmation: true,
+		Prepare:              s.prepareDelegation,
+		Handler:              s.actionDelegate,
+	}
+
+	if err := registry.Register(tool); err != nil {
+		logger.Error("failed to register tool", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"tool": tool.Name},
+		))
+	}
+}
+
+func (s *Service) prepareDelegation(ctx *agent.ToolContext, args agent.ToolArgs) (agent.ToolArgs, string, error) {
+	name := strings.ToLower(args.String("agent"))
+	if !s.delegationService.IsTrusted(name) {
+		return nil, "", fmt.Errorf("%s is not a trusted agent", name)
+	}
+
+	request := args.String("request")
+	if request == "" {
+		return nil, "", fmt.Errorf("request cannot be empty")
+	}
+	if len([]rune(request)) > maxDelegationRequestLength {
+		return nil, "", fmt.Errorf("request is too long")
+	}
+
+	prepared := agent.ToolArgs{"agent": name, "request": request}
+	return prepared, fmt.Sprintf("ask the %s agent \"%s\"", name, truncateRunes(request, 80)), nil
+}
+
+func (s *Service) actionDelegate(ctx *agent.ToolContext, args agent.ToolArgs) (any, error) {
+	reply, err := s.delegationService.Delegate(context.Background(), args.String("agent"), args.String("request"))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"agent":   reply.Agent,
+		"task_id": reply.TaskID,
+		"message": fmt.Sprintf("Here's what the %s agent said:\n\n%s", reply.Agent, reply.Text),
+	}, nil
+}
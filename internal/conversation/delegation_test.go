@@ -0,0 +1,51 @@
+package conversation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/delegation"
+)
+
+func TestDelegationTool_OnlyWithTrustedAgents(t *testing.T) {
+	service := &Service{}
+	if _, ok := service.newToolRegistry().Get("delegate_to_agent"); ok {
+		t.Error("delegation tool should not be registered without trusted agents")
+	}
+
+	service.delegationService = delegation.NewService([]delegation.TrustedAgent{
+		{Name: "meditation", URL: "https://meditation.example"},
+	}, time.Second)
+
+	tool, ok := service.newToolRegistry().Get("delegate_to_agent")
+	if !ok {
+		t.Fatal("expected the delegation tool to be registered")
+	}
+	if enum := tool.Parameters["agent"].Enum; len(enum) != 1 || enum[0] != "meditation" {
+		t.Errorf("expected the trusted agents as the enum, got %v", enum)
+	}
+	if !tool.RequiresConfirmation {
+		t.Error("delegating shares the user's words and must be confirmed")
+	}
+}
+
+func TestPrepareDelegation(t *testing.T) {
+	service := &Service{
+		delegationService: delegation.NewService([]delegation.TrustedAgent{
+			{Name: "meditation", URL: "https://meditation.example"},
+		}, time.Second),
+	}
+
+	args, summary, err := service.prepareDelegation(&agent.ToolContext{}, agent.ToolArgs{"agent": "Meditation", "request": "a calming exercise"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args.String("agent") != "meditation" || summary != `ask the meditation agent "a calming exercise"` {
+		t.Errorf("unexpected prepared args %v and summary %q", args, summary)
+	}
+
+	if _, _, err := service.prepareDelegation(&agent.ToolContext{}, agent.ToolArgs{"agent": "scheduler", "request": "book"}); err == nil {
+		t.Error("expected an untrusted agent to be rejected")
+	}
+}
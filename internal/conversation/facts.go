@@ -0,0 +1,159 @@
+package conversation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/facts"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// shortest text a forget request may name; anything shorter is left to the model
+const minForgetTextLength = 4
+
+var (
+	forgetLatestPattern   = regexp.MustCompile(`^(?:please )?forget (?:that|what i (?:just )?said)$`)
+	forgetMatchingPattern = regexp.MustCompile(`^(?:please )?forget (?:about |that )?(.+)$`)
+	listFactsPattern      = regexp.MustCompile(`^what do you (?:know|remember) about me$`)
+)
+
+var factCategoryLabels = map[string]string{
+	facts.CategoryName:           "Name",
+	facts.CategoryPronouns:       "Pronouns",
+	facts.CategoryPerson:         "Important person",
+	facts.CategorySituation:      "Ongoing situation",
+	facts.CategoryCopingStrategy: "What has helped",
+	facts.CategoryOther:          "Note",
+}
+
+// forgetFactTool lets the model, and the "forget ..." shortcut, propose deleting one remembered fact
+func (s *Service) forgetFactTool() *agent.Tool {
+	return &agent.Tool{
+		Name:        "forget_fact",
+		Description: "Propose forgetting one thing you remember about the user, quoted exactly as it appears in your notes. The user is asked to confirm before it is deleted.",
+		Parameters: map[string]*agent.ToolParameter{
+			"fact": {Type: agent.ParamString, Description: "The remembered fact, word for word.", Required: true},
+		},
+		RequiresConfirmation: true,
+		Prepare:              s.prepareForgetFact,
+		Handler:              s.actionForgetFact,
+	}
+}
+
+func (s *Service) prepareForgetFact(ctx *agent.ToolContext, args agent.ToolArgs) (agent.ToolArgs, string, error) {
+	text := strings.TrimSpace(args.String("fact"))
+	if utf8.RuneCountInString(text) < minForgetTextLength {
+		return nil, "", fmt.Errorf("name the fact to forget")
+	}
+
+	fact, err := s.factService.FindExact(ctx.UserID, text)
+	if errors.Is(err, facts.ErrFactNotFound) {
+		return nil, "", fmt.Errorf("no remembered fact matches exactly")
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	prepared := agent.ToolArgs{"fact_id": fact.ID, "content": fact.Content}
+	return prepared, fmt.Sprintf("forget \"%s\"", fact.Content), nil
+}
+
+func (s *Service) actionForgetFact(ctx *agent.ToolContext, args agent.ToolArgs) (any, error) {
+	if err := s.factService.ForgetFact(ctx.UserID, args.String("fact_id")); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"fact_id": args.String("fact_id"),
+		"message": fmt.Sprintf("Done - I've forgotten \"%s\".", args.String("content")),
+	}, nil
+}
+
+// handleFactCommand answers "what do you know about me" directly. "forget that" and "forget <fact>"
+// naming a remembered fact exactly are proposed for confirmation; every other message is left to the model.
+func (s *Service) handleFactCommand(ctx *agent.ToolContext, message string) (string, bool) {
+	if s.factService == nil {
+		return "", false
+	}
+
+	command := strings.Trim(strings.ToLower(strings.TrimSpace(message)), "?.! ")
+
+	switch {
+	case listFactsPattern.MatchString(command):
+		known, err := s.factService.GetFactsForUser(ctx.UserID)
+		if err != nil {
+			logger.Warn("failed to list facts", logger.WithError(err))
+			return "I couldn't look that up just now - could you try again in a moment?", true
+		}
+		if len(known) == 0 {
+			return "I haven't saved anything about you yet. If there's something you'd like me to remember, just tell me.", true
+		}
+		return "Here's what I remember about you:\n" + formatFactList(known) +
+			"\n\nYou can say \"forget that\" or \"forget ...\" with any of these.", true
+
+	case forgetLatestPattern.MatchString(command):
+		known, err := s.factService.GetFactsForUser(ctx.UserID)
+		if err != nil || len(known) == 0 {
+			return "", false
+		}
+		return s.proposeForget(ctx, known[0].Content)
+
+	case forgetMatchingPattern.MatchString(command):
+		text := forgetMatchingPattern.FindStringSubmatch(command)[1]
+		if reply, handled := s.proposeForget(ctx, text); handled {
+			return reply, true
+		}
+		if trimmed := strings.TrimPrefix(text, "my "); trimmed != text {
+			return s.proposeForget(ctx, trimmed)
+		}
+	}
+
+	return "", false
+}
+
+// proposeForget asks the user to confirm forgetting the fact that text names exactly
+func (s *Service) proposeForget(ctx *agent.ToolContext, text string) (string, bool) {
+	if s.tools == nil {
+		return "", false
+	}
+
+	result := s.tools.Execute(ctx, "forget_fact", agent.ToolArgs{"fact": text})
+	if !ctx.AwaitingConfirmation {
+		return "", false
+	}
+
+	action, _ := result["action"].(string)
+	return fmt.Sprintf("Should I %s? (yes or no)", action), true
+}
+
+// extractFactsAsync learns durable facts from a user message without delaying the reply
+func (s *Service) extractFactsAsync(userID, messageID, message string) {
+	if s.factService == nil {
+		return
+	}
+
+	go func() {
+		if _, err := s.factService.ExtractFacts(userID, messageID, message); err != nil {
+			logger.Warn("failed to extract user facts", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{"message_id": messageID},
+			))
+		}
+	}()
+}
+
+func formatFactList(known []*facts.Fact) string {
+	var lines []string
+	for _, fact := range known {
+		label, ok := factCategoryLabels[fact.Category]
+		if !ok {
+			label = factCategoryLabels[facts.CategoryOther]
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", label, fact.Content))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,143 @@
+package conversation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/facts"
+	"github.com/zjoart/eunoia/internal/user"
+)
+
+var factColumns = []string{"id", "user_id", "category", "content", "source_message_id", "created_at", "updated_at"}
+
+func newFactTestService(t *testing.T) (*Service, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service := &Service{
+		repo:        NewRepository(db),
+		factService: facts.NewService(facts.NewRepository(db), user.NewRepository(db), nil),
+	}
+	service.tools = service.newToolRegistry()
+	return service, mock
+}
+
+func factRows() *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(factColumns).
+		AddRow("f2", "user-1", facts.CategoryPerson, "My sister is named Ada", nil, now, now).
+		AddRow("f1", "user-1", facts.CategorySituation, "Feels like nobody cares about them at work", nil, now, now)
+}
+
+func TestHandleFactCommand_LeavesOtherMessagesToTheModel(t *testing.T) {
+	service, mock := newFactTestService(t)
+
+	// "forget it" is too short to look up; the others name no fact exactly
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery("SELECT (.+) FROM user_facts").WithArgs("user-1").WillReturnRows(factRows())
+	}
+
+	for _, message := range []string{"Forget it, nobody cares about me", "forget it", "forget about work", "forget sister"} {
+		ctx := &agent.ToolContext{UserID: "user-1", MessageID: "msg-1"}
+		if reply, handled := service.handleFactCommand(ctx, message); handled {
+			t.Errorf("expected %q to reach the model, got %q", message, reply)
+		}
+		if ctx.AwaitingConfirmation {
+			t.Errorf("expected nothing proposed for %q", message)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestHandleFactCommand_ProposesExactMatch(t *testing.T) {
+	service, mock := newFactTestService(t)
+
+	mock.ExpectQuery("SELECT (.+) FROM user_facts").WithArgs("user-1").WillReturnRows(factRows())
+	mock.ExpectExec("UPDATE tool_invocations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO tool_invocations").
+		WithArgs(sqlmock.AnyArg(), "user-1", "msg-1", "forget_fact", `{"content":"My sister is named Ada","fact_id":"f2"}`,
+			`forget "My sister is named Ada"`, InvocationPending, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	ctx := &agent.ToolContext{UserID: "user-1", MessageID: "msg-1"}
+	reply, handled := service.handleFactCommand(ctx, "Please forget my sister is named Ada.")
+	if !handled {
+		t.Fatal("expected an exact match to be handled")
+	}
+	if reply != `Should I forget "My sister is named Ada"? (yes or no)` {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if !ctx.AwaitingConfirmation {
+		t.Error("expected the reply to wait for confirmation")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestHandleFactCommand_ForgetLatest(t *testing.T) {
+	service, mock := newFactTestService(t)
+
+	mock.ExpectQuery("SELECT (.+) FROM user_facts").WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows(factColumns))
+
+	if _, handled := service.handleFactCommand(&agent.ToolContext{UserID: "user-1"}, "forget that"); handled {
+		t.Error("expected \"forget that\" with nothing saved to reach the model")
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM user_facts").WithArgs("user-1").WillReturnRows(factRows())
+	mock.ExpectQuery("SELECT (.+) FROM user_facts").WithArgs("user-1").WillReturnRows(factRows())
+	mock.ExpectExec("UPDATE tool_invocations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO tool_invocations").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	reply, handled := service.handleFactCommand(&agent.ToolContext{UserID: "user-1"}, "Forget that!")
+	if !handled || reply != `Should I forget "My sister is named Ada"? (yes or no)` {
+		t.Errorf("expected the latest fact to be proposed, got %q", reply)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestHandleActionConfirmation_ForgetFact(t *testing.T) {
+	service, mock := newFactTestService(t)
+
+	mock.ExpectQuery("SELECT (.+) FROM tool_invocations").
+		WithArgs("user-1", InvocationPending, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(toolInvocationColumns).
+			AddRow("inv-1", "user-1", "msg-1", "forget_fact", `{"content":"My sister is named Ada","fact_id":"f2"}`,
+				`forget "My sister is named Ada"`, InvocationPending, time.Now()))
+	mock.ExpectExec("UPDATE tool_invocations SET status").
+		WithArgs(InvocationExecuting, "inv-1", InvocationPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM user_facts").
+		WithArgs("f2", "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE tool_invocations").
+		WithArgs(InvocationExecuted, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "inv-1", InvocationExecuting).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	reply, _, handled := service.handleActionConfirmation("user-1", "platform-1", "msg-2", "yes")
+	if !handled {
+		t.Fatal("expected the confirmation to be handled")
+	}
+	if reply != `Done - I've forgotten "My sister is named Ada".` {
+		t.Errorf("unexpected reply %q", reply)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -2,71 +2,128 @@ package conversation
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/zjoart/eunoia/internal/a2a"
+	"github.com/gorilla/mux"
+	"github.com/zjoart/eunoia/internal/chatauth"
 	"github.com/zjoart/eunoia/internal/conversation/platforms"
+	"github.com/zjoart/eunoia/internal/push"
+	"github.com/zjoart/eunoia/internal/task"
+	"github.com/zjoart/eunoia/internal/user"
+	"github.com/zjoart/eunoia/pkg/a2a"
+	"github.com/zjoart/eunoia/pkg/id"
 	"github.com/zjoart/eunoia/pkg/logger"
 )
 
+const dateLayout = "2006-01-02"
+
+// ChatAPIPlatform namespaces the users of the chat API, which talks to Eunoia's own apps
+const ChatAPIPlatform = "app"
+
+// chat history page sizes
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+// largest chat API request body accepted
+const maxChatBodyBytes = 1 << 20
+
+// name of the data artifact carrying Insights
+const insightsArtifactName = "eunoia_insights"
+
 type Handler struct {
-	service  ServiceInterface
-	platform platforms.Platform
+	service   ServiceInterface
+	platforms *platforms.Registry
+	tasks     TaskServiceInterface
+	push      PushServiceInterface
+	server    *a2a.Server
+	// background tracks messages still being processed after a non-blocking reply
+	background sync.WaitGroup
 }
 
-func NewHandler(service ServiceInterface, platform platforms.Platform) *Handler {
-	return &Handler{
-		service:  service,
-		platform: platform,
+// NewHandler creates the conversation handler. Without a task service, tasks are not persisted,
+// the tasks/* methods answer UnsupportedOperation and every message is processed blocking; without a push
+// service, push notification configs are rejected.
+func NewHandler(service ServiceInterface, registry *platforms.Registry, tasks TaskServiceInterface, push PushServiceInterface) *Handler {
+	h := &Handler{
+		service:   service,
+		platforms: registry,
+		tasks:     tasks,
+		push:      push,
+		server:    a2a.NewServer(),
+	}
+
+	h.server.OnMessageSend(h.handleSendMessage)
+	if tasks != nil {
+		h.server.OnGetTask(h.handleGetTask)
+		h.server.OnCancelTask(h.handleCancelTask)
+		if push != nil {
+			h.server.OnSetPushConfig(h.handleSetPushConfig)
+			h.server.OnGetPushConfig(h.handleGetPushConfig)
+		}
 	}
+
+	return h
+}
+
+// SetMaxBodyBytes sets the largest A2A request body accepted, which bounds the inline files a message can carry
+func (h *Handler) SetMaxBodyBytes(n int64) {
+	h.server.SetMaxBodyBytes(n)
 }
 
+// HandleA2AMessage serves the JSON-RPC endpoint, including batches and notifications
 func (h *Handler) HandleA2AMessage(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.sendA2AError(w, a2a.InvalidRequest, "Invalid Request", "method not allowed")
-		return
-	}
+	h.server.ServeHTTP(w, r)
+}
 
-	var req a2a.A2ARequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Error("failed to decode A2A request", logger.WithError(err))
-		h.sendA2AError(w, a2a.ParseError, "Parse error", "invalid JSON")
+// HandlePlatformA2AMessage serves the JSON-RPC endpoint for the platform named in the path
+func (h *Handler) HandlePlatformA2AMessage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.platforms.Get(mux.Vars(r)["platform"]); !ok {
+		h.sendJSONError(w, http.StatusNotFound, "platform not found")
 		return
 	}
+	h.server.ServeHTTP(w, r)
+}
 
-	// validate JSON-RPC 2.0 format
-	if req.JSONRPC != "2.0" {
-		h.sendA2AError(w, a2a.InvalidRequest, "Invalid Request", "jsonrpc version must be 2.0")
-		return
-	}
+// Methods lists the A2A methods the handler serves with the services it was given
+func (h *Handler) Methods() []string {
+	return h.server.Methods()
+}
 
-	// use the configured platform
-	platform := h.platform
+func (h *Handler) handleSendMessage(r *http.Request, params *a2a.MessageSendParams) (*a2a.A2AResult, *a2a.A2AError) {
+	platform, err := h.selectPlatform(r, params.Message.Metadata)
+	if err != nil {
+		return nil, a2a.NewError(a2a.InvalidParams, err.Error())
+	}
 	platformName := platform.Name()
 
-	// validate request with platform-specific logic
-	if err := platform.ValidateRequest(&req); err != nil {
-		h.sendA2AError(w, a2a.MethodNotFound, "Method not found", err.Error())
-		return
+	if !params.Configuration.Accepts(a2a.MediaTypeText) && !acceptsInsights(params.Configuration) {
+		return nil, a2a.NewError(a2a.ContentTypeNotSupported, "Eunoia replies with text/plain or application/json")
 	}
 
 	// extract user ID using platform-specific logic
-	userID, err := platform.ExtractUserID(req.Params.Message.Metadata)
+	platformUserID, err := platform.ExtractUserID(params.Message.Metadata)
 	if err != nil {
-		h.sendA2AError(w, a2a.InvalidParams, "Invalid params", err.Error())
-		return
+		return nil, a2a.NewError(a2a.InvalidParams, err.Error())
 	}
+	userID := platforms.NamespacedUserID(platformName, platformUserID)
 
 	// extract channel ID
-	channelID, _ := platform.ExtractChannelID(req.Params.Message.Metadata)
+	channelID, _ := platform.ExtractChannelID(params.Message.Metadata)
 
 	// extract message content from parts
-	messageText := platform.ExtractMessage(req.Params.Message.Parts)
+	messageText := platform.ExtractMessage(params.Message.Parts)
 
-	messageId := req.Params.Message.MessageID
+	messageId := params.Message.MessageID
 
 	// extract conversation history from parts
-	history := platform.ExtractHistory(req.Params.Message.Parts, messageId)
+	history := platform.ExtractHistory(params.Message.Parts, messageId)
 
 	logger.Info("processing A2A message", logger.Fields{
 		"platform":   platformName,
@@ -75,37 +132,397 @@ func (h *Handler) HandleA2AMessage(w http.ResponseWriter, r *http.Request) {
 		"message_id": messageId,
 	})
 
-	if messageText == "" {
-		h.sendA2AError(w, a2a.InvalidParams, "Invalid params", "message content is required")
-		return
+	attachments, err := messageAttachments(params.Message.Parts)
+	if err != nil {
+		return nil, a2a.NewError(a2a.InvalidParams, err.Error())
+	}
+	data := a2a.DataObjects(params.Message.Parts)
+
+	if messageText == "" && len(attachments) == 0 && len(data) == 0 {
+		return nil, a2a.NewError(a2a.InvalidParams, "message content is required")
+	}
+
+	// the context is settled before the task exists, so every reply about the task names the same one
+	contextID, err := h.service.ResolveContextID(userID, channelID, params.Message.ContextID)
+	if err != nil {
+		logger.Warn("failed to resolve context", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"message_id": messageId},
+		))
+		contextID = params.Message.ContextID
+		if contextID == "" {
+			contextID = id.Generate()
+		}
 	}
 
 	chatReq := &ChatRequest{
 		PlatformUserID: userID,
 		Message:        messageText,
 		MessageID:      messageId,
+		ChannelID:      channelID,
+		ContextID:      contextID,
+		Attachments:    attachments,
+		Data:           data,
 	}
 
-	chatResp, err := h.service.ProcessMessage(chatReq)
-	if err != nil {
-		logger.Error("failed to process message", logger.WithError(err))
-		h.sendA2AError(w, a2a.InternalError, "Internal error", "failed to process message")
-		return
+	pushConfig := params.Configuration.PushNotificationConfig
+	wantsPush := pushConfig != nil && pushConfig.URL != ""
+	if wantsPush && h.push == nil {
+		return nil, a2a.NewError(a2a.PushNotificationNotSupported, nil)
 	}
 
-	// build platform-specific response with history
-	response := platform.BuildResponse(req.ID, messageId, history, &a2a.ChatResponse{
-		Response: chatResp.Response,
-	})
+	taskID := h.startTask(userID, contextID)
+
+	if wantsPush {
+		if err := h.push.SetConfig(taskID, pushConfig); err != nil {
+			h.failTask(taskID, "invalid push notification config")
+			return nil, pushError(err)
+		}
+	}
+
+	// without stored tasks a background result could never be fetched, so process blocking
+	if params.Configuration.NonBlocking() && h.tasks != nil {
+		h.background.Add(1)
+		go func() {
+			defer h.background.Done()
+
+			result, err := h.processTask(platform, params, chatReq, taskID, contextID, history)
+			if err != nil {
+				h.notifyFailure(taskID)
+				return
+			}
+			h.notify(taskID, result)
+		}()
+
+		logger.Info("A2A message accepted for background processing", logger.Fields{
+			"platform":   platformName,
+			"message_id": messageId,
+			"task_id":    taskID,
+		})
+
+		return &a2a.A2AResult{
+			ID:        taskID,
+			ContextID: contextID,
+			Status: a2a.A2ATaskStatus{
+				State:     a2a.TaskStateWorking,
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+			},
+			Kind: "task",
+		}, nil
+	}
+
+	result, err := h.processTask(platform, params, chatReq, taskID, contextID, history)
+	if err != nil {
+		return nil, a2a.NewError(a2a.InternalError, "failed to process message")
+	}
 
 	logger.Info("A2A message processed successfully", logger.Fields{
 		"platform":   platformName,
 		"message_id": messageId,
 	})
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	return result, nil
+}
+
+// selectPlatform uses the platform named in the path, falling back to detection from the message metadata
+func (h *Handler) selectPlatform(r *http.Request, metadata map[string]interface{}) (platforms.Platform, error) {
+	if name := mux.Vars(r)["platform"]; name != "" {
+		platform, ok := h.platforms.Get(name)
+		if !ok {
+			return nil, errors.New("platform " + name + " is not enabled")
+		}
+		return platform, nil
+	}
+	return h.platforms.Select(metadata)
+}
+
+// messageAttachments decodes the file parts of a message
+func messageAttachments(parts []a2a.A2APart) ([]Attachment, error) {
+	var attachments []Attachment
+	for _, file := range a2a.FileParts(parts) {
+		attachment := Attachment{
+			Name:     file.Name,
+			MimeType: file.MimeType,
+			URI:      file.URI,
+		}
+
+		if file.Bytes != "" {
+			data, err := file.Decode()
+			if err != nil {
+				return nil, err
+			}
+			attachment.Data = data
+		} else if file.URI == "" {
+			return nil, errors.New("file parts need bytes or a uri")
+		}
+
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// processTask generates the reply for a message and saves the finished task
+func (h *Handler) processTask(platform platforms.Platform, params *a2a.MessageSendParams, chatReq *ChatRequest, taskID, contextID string, history []a2a.A2AMessageResult) (*a2a.A2AResult, error) {
+	chatResp, err := h.service.ProcessMessage(chatReq)
+	if err != nil {
+		logger.Error("failed to process message", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"task_id": taskID},
+		))
+		h.failTask(taskID, "failed to process message")
+		return nil, err
+	}
+
+	// build platform-specific result with history
+	result := platform.BuildResult(taskID, contextID, chatReq.MessageID, history, &a2a.ChatResponse{
+		Response:      chatResp.Response,
+		InputRequired: chatResp.InputRequired,
+	})
+
+	applyOutputModes(result, params.Configuration, chatResp.Insights)
+
+	if historyLength := params.Configuration.HistoryLength; historyLength > 0 && len(result.History) > historyLength {
+		result.History = result.History[len(result.History)-historyLength:]
+	}
+
+	h.finishTask(taskID, result)
+
+	return result, nil
+}
+
+// acceptsInsights reports whether the client asked for JSON; clients that list no output modes get text only
+func acceptsInsights(config a2a.A2AConfig) bool {
+	return len(config.AcceptedOutputModes) > 0 && config.Accepts(a2a.MediaTypeJSON)
+}
+
+// applyOutputModes drops the text artifact for clients that do not accept text and adds the
+// insights as a data artifact for clients that accept JSON
+func applyOutputModes(result *a2a.A2AResult, config a2a.A2AConfig, insights *Insights) {
+	if !config.Accepts(a2a.MediaTypeText) {
+		result.Artifacts = nil
+	}
+
+	if insights == nil || !acceptsInsights(config) {
+		return
+	}
+
+	encoded, err := json.Marshal(insights)
+	if err != nil {
+		logger.Warn("failed to encode insights", logger.WithError(err))
+		return
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(encoded, &object); err != nil {
+		logger.Warn("failed to encode insights", logger.WithError(err))
+		return
+	}
+
+	result.Artifacts = append(result.Artifacts, a2a.A2AArtifact{
+		ArtifactID: id.Generate(),
+		Name:       insightsArtifactName,
+		Parts:      []a2a.A2APart{{Kind: a2a.PartKindData, Object: object}},
+	})
+}
+
+// notify sends a task finished in the background to its push URL, if it has one
+func (h *Handler) notify(taskID string, result *a2a.A2AResult) {
+	if h.push == nil {
+		return
+	}
+
+	if err := h.push.Notify(taskID, result); err != nil {
+		logger.Warn("failed to send push notification", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"task_id": taskID},
+		))
+	}
+}
+
+func (h *Handler) notifyFailure(taskID string) {
+	if h.push == nil {
+		return
+	}
+
+	result, err := h.tasks.Get(taskID, nil)
+	if err != nil {
+		logger.Warn("failed to load failed task for push notification", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"task_id": taskID},
+		))
+		return
+	}
+
+	h.notify(taskID, result)
+}
+
+// startTask records a new task as working, falling back to unsaved IDs when tasks are unavailable
+func (h *Handler) startTask(platformUserID, contextID string) string {
+	if h.tasks == nil {
+		return id.Generate()
+	}
+
+	created, err := h.tasks.Create(platformUserID, contextID)
+	if err != nil {
+		logger.Warn("failed to create task", logger.WithError(err))
+		return id.Generate()
+	}
+
+	if err := h.tasks.SetState(created.ID, a2a.TaskStateWorking); err != nil {
+		logger.Warn("failed to mark task as working", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"task_id": created.ID},
+		))
+	}
+
+	return created.ID
+}
+
+func (h *Handler) finishTask(taskID string, result *a2a.A2AResult) {
+	if h.tasks == nil {
+		return
+	}
+
+	err := h.tasks.Finish(taskID, result)
+	if errors.Is(err, task.ErrTaskFinished) {
+		// canceled while we were working on it
+		result.Status.State = a2a.TaskStateCanceled
+		return
+	}
+	if err != nil {
+		logger.Warn("failed to save task", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"task_id": taskID},
+		))
+	}
+}
+
+func (h *Handler) failTask(taskID, reason string) {
+	if h.tasks == nil {
+		return
+	}
+
+	if err := h.tasks.Fail(taskID, reason); err != nil {
+		logger.Warn("failed to mark task as failed", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"task_id": taskID},
+		))
+	}
+}
+
+// callerUserID is the platform user a task request is made for, named in its metadata as on message/send
+func (h *Handler) callerUserID(r *http.Request, metadata map[string]interface{}) (string, *a2a.A2AError) {
+	platform, err := h.selectPlatform(r, metadata)
+	if err != nil {
+		return "", a2a.NewError(a2a.InvalidParams, err.Error())
+	}
+
+	userID, err := platform.ExtractUserID(metadata)
+	if err != nil {
+		return "", a2a.NewError(a2a.InvalidParams, err.Error())
+	}
+
+	return platforms.NamespacedUserID(platform.Name(), userID), nil
+}
+
+func (h *Handler) handleGetTask(r *http.Request, params *a2a.TaskQueryParams) (*a2a.A2AResult, *a2a.A2AError) {
+	userID, a2aErr := h.callerUserID(r, params.Metadata)
+	if a2aErr != nil {
+		return nil, a2aErr
+	}
+
+	result, err := h.tasks.GetForUser(params.ID, userID, params.HistoryLength)
+	if err != nil {
+		return nil, taskError(err)
+	}
+
+	return result, nil
+}
+
+func (h *Handler) handleCancelTask(r *http.Request, params *a2a.TaskIDParams) (*a2a.A2AResult, *a2a.A2AError) {
+	userID, a2aErr := h.callerUserID(r, params.Metadata)
+	if a2aErr != nil {
+		return nil, a2aErr
+	}
+
+	result, err := h.tasks.Cancel(params.ID, userID)
+	if err != nil {
+		return nil, taskError(err)
+	}
+
+	logger.Info("task canceled", logger.Fields{"task_id": result.ID})
+
+	return result, nil
+}
+
+func (h *Handler) handleSetPushConfig(r *http.Request, params *a2a.A2ATaskPushConfig) (*a2a.A2ATaskPushConfig, *a2a.A2AError) {
+	if a2aErr := h.checkTaskOwner(r, params.TaskID, params.Metadata); a2aErr != nil {
+		return nil, a2aErr
+	}
+
+	if err := h.push.SetConfig(params.TaskID, &params.PushNotificationConfig); err != nil {
+		return nil, pushError(err)
+	}
+
+	return h.taskPushConfig(params.TaskID)
+}
+
+func (h *Handler) handleGetPushConfig(r *http.Request, params *a2a.GetTaskPushConfigParams) (*a2a.A2ATaskPushConfig, *a2a.A2AError) {
+	if a2aErr := h.checkTaskOwner(r, params.ID, params.Metadata); a2aErr != nil {
+		return nil, a2aErr
+	}
+
+	return h.taskPushConfig(params.ID)
+}
+
+// checkTaskOwner answers TaskNotFound unless the task belongs to the caller, like tasks/get
+func (h *Handler) checkTaskOwner(r *http.Request, taskID string, metadata map[string]interface{}) *a2a.A2AError {
+	userID, a2aErr := h.callerUserID(r, metadata)
+	if a2aErr != nil {
+		return a2aErr
+	}
+
+	if _, err := h.tasks.GetForUser(taskID, userID, nil); err != nil {
+		return taskError(err)
+	}
+
+	return nil
+}
+
+// taskPushConfig returns the stored config for a task, without credentials
+func (h *Handler) taskPushConfig(taskID string) (*a2a.A2ATaskPushConfig, *a2a.A2AError) {
+	config, err := h.push.GetConfig(taskID)
+	if err != nil {
+		return nil, pushError(err)
+	}
+
+	return &a2a.A2ATaskPushConfig{
+		TaskID:                 taskID,
+		PushNotificationConfig: *config,
+	}, nil
+}
+
+func pushError(err error) *a2a.A2AError {
+	switch {
+	case errors.Is(err, push.ErrInvalidConfig):
+		return a2a.NewError(a2a.InvalidParams, err.Error())
+	case errors.Is(err, push.ErrConfigNotFound):
+		return a2a.NewError(a2a.InvalidParams, "no push notification config for task")
+	default:
+		logger.Error("push notification config request failed", logger.WithError(err))
+		return a2a.NewError(a2a.InternalError, "failed to save push notification config")
+	}
+}
+
+func taskError(err error) *a2a.A2AError {
+	switch {
+	case errors.Is(err, task.ErrTaskNotFound):
+		return a2a.NewError(a2a.TaskNotFound, nil)
+	case errors.Is(err, task.ErrTaskNotCancelable):
+		return a2a.NewError(a2a.TaskNotCancelable, nil)
+	default:
+		logger.Error("task request failed", logger.WithError(err))
+		return a2a.NewError(a2a.InternalError, "failed to load task")
+	}
 }
 
 func (h *Handler) HandleHealthCheck(w http.ResponseWriter, r *http.Request) {
@@ -120,23 +537,157 @@ func (h *Handler) HandleHealthCheck(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func (h *Handler) sendA2AError(w http.ResponseWriter, code int, message string, data interface{}) {
-	logger.Error("sending A2A error response", logger.Fields{
-		"code":    code,
-		"message": message,
-		"data":    data,
+func (h *Handler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	req := &SearchRequest{
+		PlatformUserID: query.Get("platform_user_id"),
+		Query:          query.Get("q"),
+	}
+
+	if req.PlatformUserID == "" || strings.TrimSpace(req.Query) == "" {
+		h.sendJSONError(w, http.StatusBadRequest, "platform_user_id and q are required")
+		return
+	}
+
+	if from := query.Get("from"); from != "" {
+		date, err := time.Parse(dateLayout, from)
+		if err != nil {
+			h.sendJSONError(w, http.StatusBadRequest, "from must be a date in YYYY-MM-DD format")
+			return
+		}
+		req.From = date
+	}
+
+	if to := query.Get("to"); to != "" {
+		date, err := time.Parse(dateLayout, to)
+		if err != nil {
+			h.sendJSONError(w, http.StatusBadRequest, "to must be a date in YYYY-MM-DD format")
+			return
+		}
+		// make the end date inclusive
+		req.To = date.AddDate(0, 0, 1)
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			h.sendJSONError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		req.Limit = n
+	}
+
+	results, err := h.service.Search(req)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			h.sendJSONError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		logger.Error("failed to search entries", logger.WithError(err))
+		h.sendJSONError(w, http.StatusInternalServerError, "failed to search entries")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{
+		"query":   req.Query,
+		"results": results,
 	})
+}
 
-	errResp := a2a.A2AResponse{
-		JSONRPC: "2.0",
-		Error: &a2a.A2AError{
-			Code:    code,
-			Message: message,
-			Data:    data,
-		},
+// HandleChat answers one message outside of A2A, returning the reply with its insights. The user is
+// the one the request's token was issued to, never one named in the body.
+func (h *Handler) HandleChat(w http.ResponseWriter, r *http.Request) {
+	platformUserID := chatauth.User(r.Context())
+	if platformUserID == "" {
+		h.sendJSONError(w, http.StatusUnauthorized, "a user token is required")
+		return
+	}
+
+	var req ChatRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxChatBodyBytes)).Decode(&req); err != nil {
+		h.sendJSONError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if strings.TrimSpace(req.Message) == "" && len(req.Data) == 0 {
+		h.sendJSONError(w, http.StatusBadRequest, "message or data is required")
+		return
 	}
 
+	req.PlatformUserID = platforms.NamespacedUserID(ChatAPIPlatform, platformUserID)
+	if req.MessageID == "" {
+		req.MessageID = id.Generate()
+	}
+
+	resp, err := h.service.ProcessMessage(&req)
+	if err != nil {
+		logger.Error("failed to process chat message", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"message_id": req.MessageID},
+		))
+		h.sendJSONError(w, http.StatusInternalServerError, "failed to process message")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, resp)
+}
+
+// HandleChatHistory returns the latest messages of the token's user, oldest first
+func (h *Handler) HandleChatHistory(w http.ResponseWriter, r *http.Request) {
+	platformUserID := chatauth.User(r.Context())
+	if platformUserID == "" {
+		h.sendJSONError(w, http.StatusUnauthorized, "a user token is required")
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := defaultHistoryLimit
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxHistoryLimit {
+			h.sendJSONError(w, http.StatusBadRequest, "limit must be an integer from 1 to 200")
+			return
+		}
+		limit = n
+	}
+
+	messages, err := h.service.GetConversationHistory(platforms.NamespacedUserID(ChatAPIPlatform, platformUserID), limit)
+	// a user who has not chatted yet simply has no history
+	if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+		logger.Error("failed to get chat history", logger.WithError(err))
+		h.sendJSONError(w, http.StatusInternalServerError, "failed to get chat history")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{
+		"messages": ToChatHistory(messages),
+	})
+}
+
+// ToChatHistory converts stored messages into the form chat clients receive, leaving out prompt context
+func ToChatHistory(messages []*ConversationMessage) []ChatHistoryMessage {
+	history := make([]ChatHistoryMessage, 0, len(messages))
+	for _, message := range messages {
+		history = append(history, ChatHistoryMessage{
+			ID:        message.ID,
+			Role:      message.MessageRole,
+			Content:   message.MessageContent,
+			CreatedAt: message.CreatedAt,
+		})
+	}
+	return history
+}
+
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(errResp)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+func (h *Handler) sendJSONError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, map[string]string{
+		"error": message,
+	})
 }
@@ -2,19 +2,34 @@ package conversation
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
-	"github.com/zjoart/eunoia/internal/a2a"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/chatauth"
 	"github.com/zjoart/eunoia/internal/conversation/platforms"
+	"github.com/zjoart/eunoia/internal/push"
+	"github.com/zjoart/eunoia/internal/task"
+	"github.com/zjoart/eunoia/internal/user"
+	"github.com/zjoart/eunoia/pkg/a2a"
 )
 
 func TestHandleA2AMessage_EmptyBody(t *testing.T) {
 	mockService := &MockService{}
-	platform := platforms.NewPlatform("telex")
-	handler := NewHandler(mockService, platform)
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform())
+	handler := NewHandler(mockService, registry, nil, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/a2a/agent/eunoia", bytes.NewReader([]byte("")))
 	req.Header.Set("Content-Type", "application/json")
@@ -42,8 +57,8 @@ func TestHandleA2AMessage_EmptyBody(t *testing.T) {
 
 func TestHandleA2AMessage_EmptyJSON(t *testing.T) {
 	mockService := &MockService{}
-	platform := platforms.NewPlatform("telex")
-	handler := NewHandler(mockService, platform)
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform())
+	handler := NewHandler(mockService, registry, nil, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/a2a/agent/eunoia", bytes.NewReader([]byte("{}")))
 	req.Header.Set("Content-Type", "application/json")
@@ -75,8 +90,8 @@ func TestHandleA2AMessage_EmptyJSON(t *testing.T) {
 
 func TestHandleA2AMessage_InvalidJSONRPCVersion(t *testing.T) {
 	mockService := &MockService{}
-	platform := platforms.NewPlatform("telex")
-	handler := NewHandler(mockService, platform)
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform())
+	handler := NewHandler(mockService, registry, nil, nil)
 
 	payload := map[string]interface{}{
 		"jsonrpc": "1.0",
@@ -105,8 +120,8 @@ func TestHandleA2AMessage_InvalidJSONRPCVersion(t *testing.T) {
 
 func TestHandleA2AMessage_MissingMessageContent(t *testing.T) {
 	mockService := &MockService{}
-	platform := platforms.NewPlatform("telex")
-	handler := NewHandler(mockService, platform)
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform())
+	handler := NewHandler(mockService, registry, nil, nil)
 
 	payload := a2a.A2ARequest{
 		JSONRPC: "2.0",
@@ -148,8 +163,8 @@ func TestHandleA2AMessage_ValidRequest(t *testing.T) {
 			return &ChatResponse{Response: "Test response"}, nil
 		},
 	}
-	platform := platforms.NewPlatform("telex")
-	handler := NewHandler(mockService, platform)
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform())
+	handler := NewHandler(mockService, registry, nil, nil)
 
 	payload := a2a.A2ARequest{
 		JSONRPC: "2.0",
@@ -211,8 +226,8 @@ func TestHandleA2AMessage_ValidRequest(t *testing.T) {
 
 func TestHandleA2AMessage_WrongHTTPMethod(t *testing.T) {
 	mockService := &MockService{}
-	platform := platforms.NewPlatform("telex")
-	handler := NewHandler(mockService, platform)
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform())
+	handler := NewHandler(mockService, registry, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/a2a/agent/eunoia", nil)
 	w := httptest.NewRecorder()
@@ -233,8 +248,8 @@ func TestHandleA2AMessage_WrongHTTPMethod(t *testing.T) {
 
 func TestHandleHealthCheck(t *testing.T) {
 	mockService := &MockService{}
-	platform := platforms.NewPlatform("telex")
-	handler := NewHandler(mockService, platform)
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform())
+	handler := NewHandler(mockService, registry, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/agent/health", nil)
 	w := httptest.NewRecorder()
@@ -262,6 +277,9 @@ func TestHandleHealthCheck(t *testing.T) {
 // MockService implements the service interface for testing
 type MockService struct {
 	ProcessMessageFunc func(*ChatRequest) (*ChatResponse, error)
+	HistoryFunc        func(platformUserID string, limit int) ([]*ConversationMessage, error)
+	SearchFunc         func(*SearchRequest) ([]*SearchResult, error)
+	ContextFunc        func(platformUserID, channelID, contextID string) (string, error)
 }
 
 func (m *MockService) ProcessMessage(req *ChatRequest) (*ChatResponse, error) {
@@ -271,6 +289,1157 @@ func (m *MockService) ProcessMessage(req *ChatRequest) (*ChatResponse, error) {
 	return &ChatResponse{Response: "mock response"}, nil
 }
 
+func (m *MockService) ResolveContextID(platformUserID, channelID, contextID string) (string, error) {
+	if m.ContextFunc != nil {
+		return m.ContextFunc(platformUserID, channelID, contextID)
+	}
+	if contextID != "" {
+		return contextID, nil
+	}
+	return "ctx-new", nil
+}
+
 func (m *MockService) GetConversationHistory(platformUserID string, limit int) ([]*ConversationMessage, error) {
+	if m.HistoryFunc != nil {
+		return m.HistoryFunc(platformUserID, limit)
+	}
 	return nil, nil
 }
+
+func (m *MockService) Search(req *SearchRequest) ([]*SearchResult, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(req)
+	}
+	return []*SearchResult{}, nil
+}
+
+func TestHandleSearch_MissingQuery(t *testing.T) {
+	mockService := &MockService{}
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform())
+	handler := NewHandler(mockService, registry, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?platform_user_id=user-123", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleSearch(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestHandleSearch_ValidRequest(t *testing.T) {
+	var captured *SearchRequest
+	mockService := &MockService{
+		SearchFunc: func(req *SearchRequest) ([]*SearchResult, error) {
+			captured = req
+			return []*SearchResult{
+				{Source: "reflection", ID: "ref-1", Snippet: "called my sister", Score: 1.5},
+			}, nil
+		},
+	}
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform())
+	handler := NewHandler(mockService, registry, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?platform_user_id=user-123&q=sister&from=2025-10-01&to=2025-10-31&limit=5", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleSearch(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	if captured.Limit != 5 {
+		t.Errorf("expected limit 5, got %d", captured.Limit)
+	}
+
+	if captured.To.Format("2006-01-02") != "2025-11-01" {
+		t.Errorf("expected inclusive end date, got %v", captured.To)
+	}
+
+	var resp struct {
+		Results []*SearchResult `json:"results"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(resp.Results) != 1 || resp.Results[0].ID != "ref-1" {
+		t.Errorf("expected one result for ref-1, got %v", resp.Results)
+	}
+}
+
+// MockTaskService keeps tasks in memory for testing
+type MockTaskService struct {
+	mu    sync.Mutex
+	tasks map[string]*task.Task
+}
+
+func NewMockTaskService() *MockTaskService {
+	return &MockTaskService{tasks: make(map[string]*task.Task)}
+}
+
+func (m *MockTaskService) Create(platformUserID, contextID string) (*task.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	created := &task.Task{
+		ID:             fmt.Sprintf("task-%d", len(m.tasks)+1),
+		ContextID:      contextID,
+		PlatformUserID: platformUserID,
+		State:          a2a.TaskStateSubmitted,
+		UpdatedAt:      time.Now(),
+	}
+	m.tasks[created.ID] = created
+	return created, nil
+}
+
+func (m *MockTaskService) SetState(taskID, state string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tasks[taskID].State = state
+	return nil
+}
+
+func (m *MockTaskService) Finish(taskID string, result *a2a.A2AResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := m.tasks[taskID]
+	if task.IsTerminal(stored.State) {
+		return task.ErrTaskFinished
+	}
+	stored.ContextID = result.ContextID
+	stored.State = result.Status.State
+	stored.StatusMessage = &result.Status.Message
+	stored.History = result.History
+	stored.Artifacts = result.Artifacts
+	return nil
+}
+
+func (m *MockTaskService) Fail(taskID, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tasks[taskID].State = a2a.TaskStateFailed
+	return nil
+}
+
+func (m *MockTaskService) Get(taskID string, historyLength *int) (*a2a.A2AResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.tasks[taskID]
+	if !ok {
+		return nil, task.ErrTaskNotFound
+	}
+	return task.ToResult(stored, historyLength), nil
+}
+
+func (m *MockTaskService) GetForUser(taskID, platformUserID string, historyLength *int) (*a2a.A2AResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.tasks[taskID]
+	if !ok || stored.PlatformUserID != platformUserID {
+		return nil, task.ErrTaskNotFound
+	}
+	return task.ToResult(stored, historyLength), nil
+}
+
+func (m *MockTaskService) Cancel(taskID, platformUserID string) (*a2a.A2AResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.tasks[taskID]
+	if !ok || stored.PlatformUserID != platformUserID {
+		return nil, task.ErrTaskNotFound
+	}
+	if task.IsTerminal(stored.State) {
+		return nil, task.ErrTaskNotCancelable
+	}
+	stored.State = a2a.TaskStateCanceled
+	return task.ToResult(stored, nil), nil
+}
+
+func sendA2A(t *testing.T, handler *Handler, payload a2a.A2ARequest) a2a.A2AResponse {
+	body, _ := json.Marshal(payload)
+
+	req := httptest.NewRequest(http.MethodPost, "/a2a/agent/eunoia", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.HandleA2AMessage(w, req)
+
+	var resp a2a.A2AResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp
+}
+
+func newMessageRequest(text string) a2a.A2ARequest {
+	return a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-1",
+		Method:  "message/send",
+		Params: a2a.A2AParams{
+			Message: a2a.A2AMessage{
+				Kind:      "message",
+				Role:      "user",
+				Parts:     []a2a.A2APart{{Kind: "text", Text: text}},
+				Metadata:  map[string]interface{}{"telex_user_id": "user-123"},
+				MessageID: "msg-1",
+			},
+		},
+	}
+}
+
+func TestHandleA2AMessage_PersistsTask(t *testing.T) {
+	tasks := NewMockTaskService()
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), tasks, nil)
+
+	resp := sendA2A(t, handler, newMessageRequest("Hello"))
+	if resp.Error != nil {
+		t.Fatalf("expected no error, got: %v", resp.Error)
+	}
+
+	stored, ok := tasks.tasks[resp.Result.ID]
+	if !ok {
+		t.Fatalf("expected task %s to be stored", resp.Result.ID)
+	}
+	if stored.State != a2a.TaskStateCompleted {
+		t.Errorf("expected stored state 'completed', got '%s'", stored.State)
+	}
+	if resp.Result.ContextID != stored.ContextID {
+		t.Errorf("expected context ID %s, got %s", stored.ContextID, resp.Result.ContextID)
+	}
+}
+
+func TestHandleA2AMessage_InputRequired(t *testing.T) {
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			return &ChatResponse{Response: "Shall I log your mood as 3/10?", InputRequired: true}, nil
+		},
+	}
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), NewMockTaskService(), nil)
+
+	resp := sendA2A(t, handler, newMessageRequest("I'm feeling stressed"))
+
+	if resp.Result.Status.State != a2a.TaskStateInputRequired {
+		t.Errorf("expected state 'input-required', got '%s'", resp.Result.Status.State)
+	}
+}
+
+func TestHandleA2AMessage_FailedTask(t *testing.T) {
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			return nil, errors.New("model unavailable")
+		},
+	}
+	tasks := NewMockTaskService()
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), tasks, nil)
+
+	resp := sendA2A(t, handler, newMessageRequest("Hello"))
+	if resp.Error == nil || resp.Error.Code != a2a.InternalError {
+		t.Fatalf("expected internal error, got %v", resp.Error)
+	}
+
+	if tasks.tasks["task-1"].State != a2a.TaskStateFailed {
+		t.Errorf("expected task to be failed, got '%s'", tasks.tasks["task-1"].State)
+	}
+}
+
+func TestHandleA2AMessage_TasksGet(t *testing.T) {
+	tasks := NewMockTaskService()
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), tasks, nil)
+
+	sent := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-1",
+		Method:  "message/send",
+		Params: a2a.A2AParams{
+			Message: a2a.A2AMessage{
+				Parts: []a2a.A2APart{
+					{Kind: "text", Text: "How are you?"},
+					{Kind: "data", Data: []a2a.A2APart{{Kind: "text", Text: "hi"}, {Kind: "text", Text: "hello!"}}},
+				},
+				Metadata:  map[string]interface{}{"telex_user_id": "user-123"},
+				MessageID: "msg-1",
+			},
+		},
+	})
+
+	historyLength := 1
+	resp := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-2",
+		Method:  "tasks/get",
+		Params:  a2a.A2AParams{ID: sent.Result.ID, HistoryLength: &historyLength, Metadata: telexUser("user-123")},
+	})
+
+	if resp.Error != nil {
+		t.Fatalf("expected no error, got: %v", resp.Error)
+	}
+	if resp.ID != "req-2" || resp.Result.ID != sent.Result.ID {
+		t.Errorf("unexpected response ids: request %s, task %s", resp.ID, resp.Result.ID)
+	}
+	if len(resp.Result.History) != 1 || resp.Result.History[0].Role != "agent" {
+		t.Errorf("expected only the latest history message, got %+v", resp.Result.History)
+	}
+
+	missing := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-3",
+		Method:  "tasks/get",
+		Params:  a2a.A2AParams{ID: "unknown", Metadata: telexUser("user-123")},
+	})
+	if missing.Error == nil || missing.Error.Code != a2a.TaskNotFound {
+		t.Errorf("expected TaskNotFound, got %v", missing.Error)
+	}
+
+	otherUser := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-4",
+		Method:  "tasks/get",
+		Params:  a2a.A2AParams{ID: sent.Result.ID, Metadata: telexUser("user-456")},
+	})
+	if otherUser.Error == nil || otherUser.Error.Code != a2a.TaskNotFound {
+		t.Errorf("expected another user's task to be hidden, got %v", otherUser.Error)
+	}
+
+	anonymous := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-5",
+		Method:  "tasks/get",
+		Params:  a2a.A2AParams{ID: sent.Result.ID},
+	})
+	if anonymous.Error == nil || anonymous.Error.Code != a2a.InvalidParams {
+		t.Errorf("expected InvalidParams without a user, got %v", anonymous.Error)
+	}
+}
+
+// telexUser is the metadata naming the Telex user a request is made for
+func telexUser(userID string) map[string]interface{} {
+	return map[string]interface{}{"telex_user_id": userID}
+}
+
+func TestHandleA2AMessage_TasksCancel(t *testing.T) {
+	tasks := NewMockTaskService()
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), tasks, nil)
+
+	pending, _ := tasks.Create("telex:user-123", "")
+	tasks.SetState(pending.ID, a2a.TaskStateInputRequired)
+
+	otherUser := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-0",
+		Method:  "tasks/cancel",
+		Params:  a2a.A2AParams{ID: pending.ID, Metadata: telexUser("user-456")},
+	})
+	if otherUser.Error == nil || otherUser.Error.Code != a2a.TaskNotFound {
+		t.Errorf("expected another user's task not to be canceled, got %v", otherUser.Error)
+	}
+
+	resp := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-1",
+		Method:  "tasks/cancel",
+		Params:  a2a.A2AParams{ID: pending.ID, Metadata: telexUser("user-123")},
+	})
+	if resp.Error != nil {
+		t.Fatalf("expected no error, got: %v", resp.Error)
+	}
+	if resp.Result.Status.State != a2a.TaskStateCanceled {
+		t.Errorf("expected state 'canceled', got '%s'", resp.Result.Status.State)
+	}
+
+	again := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-2",
+		Method:  "tasks/cancel",
+		Params:  a2a.A2AParams{ID: pending.ID, Metadata: telexUser("user-123")},
+	})
+	if again.Error == nil || again.Error.Code != a2a.TaskNotCancelable {
+		t.Errorf("expected TaskNotCancelable, got %v", again.Error)
+	}
+}
+
+func TestHandleA2AMessage_TasksUnavailable(t *testing.T) {
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil)
+
+	resp := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-1",
+		Method:  "tasks/get",
+		Params:  a2a.A2AParams{ID: "task-1"},
+	})
+	if resp.Error == nil || resp.Error.Code != a2a.UnsupportedOperation {
+		t.Errorf("expected UnsupportedOperation, got %v", resp.Error)
+	}
+}
+
+func TestHandleA2AMessage_ContextID(t *testing.T) {
+	var received *ChatRequest
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			received = req
+			return &ChatResponse{Response: "Welcome back", ContextID: req.ContextID}, nil
+		},
+	}
+	tasks := NewMockTaskService()
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), tasks, nil)
+
+	payload := newMessageRequest("Hello again")
+	payload.Params.Message.ContextID = "ctx-123"
+	payload.Params.Message.Metadata["telex_channel_id"] = "channel-456"
+
+	resp := sendA2A(t, handler, payload)
+
+	if received.ContextID != "ctx-123" || received.ChannelID != "channel-456" {
+		t.Errorf("expected context and channel to be passed on, got %+v", received)
+	}
+	if resp.Result.ContextID != "ctx-123" || resp.Result.Status.Message.ContextID != "ctx-123" {
+		t.Errorf("expected context ID to be echoed, got %s", resp.Result.ContextID)
+	}
+	if tasks.tasks[resp.Result.ID].ContextID != "ctx-123" {
+		t.Errorf("expected task to be stored under the context")
+	}
+}
+
+func TestHandleA2AMessage_SessionContextID(t *testing.T) {
+	var processed string
+	mockService := &MockService{
+		ContextFunc: func(platformUserID, channelID, contextID string) (string, error) {
+			return "ctx-session", nil
+		},
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			processed = req.ContextID
+			return &ChatResponse{Response: "Hi", ContextID: req.ContextID}, nil
+		},
+	}
+	tasks := NewMockTaskService()
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), tasks, nil)
+
+	resp := sendA2A(t, handler, newMessageRequest("Hello"))
+
+	if processed != "ctx-session" {
+		t.Errorf("expected the message to be processed in the session's context, got %s", processed)
+	}
+
+	if resp.Result.ContextID != "ctx-session" {
+		t.Errorf("expected the session's context ID, got %s", resp.Result.ContextID)
+	}
+	if tasks.tasks[resp.Result.ID].ContextID != "ctx-session" {
+		t.Errorf("expected task context to follow the session")
+	}
+}
+
+// MockPushService records push configs and notifications for testing
+type MockPushService struct {
+	configs  map[string]*a2a.A2APushNotification
+	notified []*a2a.A2AResult
+}
+
+func NewMockPushService() *MockPushService {
+	return &MockPushService{configs: make(map[string]*a2a.A2APushNotification)}
+}
+
+func (m *MockPushService) SetConfig(taskID string, config *a2a.A2APushNotification) error {
+	if config.URL == "" {
+		return push.ErrInvalidConfig
+	}
+	m.configs[taskID] = config
+	return nil
+}
+
+func (m *MockPushService) GetConfig(taskID string) (*a2a.A2APushNotification, error) {
+	config, ok := m.configs[taskID]
+	if !ok {
+		return nil, push.ErrConfigNotFound
+	}
+	return config, nil
+}
+
+func (m *MockPushService) Notify(taskID string, result *a2a.A2AResult) error {
+	m.notified = append(m.notified, result)
+	return nil
+}
+
+func TestHandleA2AMessage_NonBlocking(t *testing.T) {
+	tasks := NewMockTaskService()
+	pushService := NewMockPushService()
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), tasks, pushService)
+
+	blocking := false
+	payload := newMessageRequest("Hello")
+	payload.Params.Configuration.Blocking = &blocking
+	payload.Params.Configuration.PushNotificationConfig = &a2a.A2APushNotification{URL: "https://client.example/hook", Token: "tok"}
+
+	resp := sendA2A(t, handler, payload)
+	if resp.Error != nil {
+		t.Fatalf("expected no error, got: %v", resp.Error)
+	}
+	if resp.Result.Status.State != a2a.TaskStateWorking {
+		t.Errorf("expected state 'working', got '%s'", resp.Result.Status.State)
+	}
+
+	handler.background.Wait()
+
+	if pushService.configs[resp.Result.ID] == nil {
+		t.Error("expected the push config to be stored for the task")
+	}
+	if len(pushService.notified) != 1 || pushService.notified[0].Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("expected one completed task notification, got %+v", pushService.notified)
+	}
+	if tasks.tasks[resp.Result.ID].State != a2a.TaskStateCompleted {
+		t.Errorf("expected stored task to be completed")
+	}
+}
+
+func TestHandleA2AMessage_NonBlockingContextID(t *testing.T) {
+	tasks := NewMockTaskService()
+	pushService := NewMockPushService()
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), tasks, pushService)
+
+	blocking := false
+	payload := newMessageRequest("Hello")
+	payload.Params.Configuration.Blocking = &blocking
+	payload.Params.Configuration.PushNotificationConfig = &a2a.A2APushNotification{URL: "https://client.example/hook"}
+
+	resp := sendA2A(t, handler, payload)
+	if resp.Error != nil {
+		t.Fatalf("expected no error, got: %v", resp.Error)
+	}
+	if resp.Result.ContextID != "ctx-new" {
+		t.Errorf("expected the resolved context ID, got %s", resp.Result.ContextID)
+	}
+	if tasks.tasks[resp.Result.ID].ContextID != resp.Result.ContextID {
+		t.Errorf("expected the reply to name the task's context, got %s and %s",
+			resp.Result.ContextID, tasks.tasks[resp.Result.ID].ContextID)
+	}
+
+	handler.background.Wait()
+
+	if tasks.tasks[resp.Result.ID].ContextID != resp.Result.ContextID {
+		t.Errorf("expected the finished task to keep the reply's context, got %s", tasks.tasks[resp.Result.ID].ContextID)
+	}
+	if len(pushService.notified) != 1 || pushService.notified[0].ContextID != resp.Result.ContextID {
+		t.Errorf("expected the notification to name the reply's context, got %+v", pushService.notified)
+	}
+}
+
+func TestHandleA2AMessage_NonBlockingFailure(t *testing.T) {
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			return nil, errors.New("model unavailable")
+		},
+	}
+	pushService := NewMockPushService()
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), NewMockTaskService(), pushService)
+
+	blocking := false
+	payload := newMessageRequest("Hello")
+	payload.Params.Configuration.Blocking = &blocking
+	payload.Params.Configuration.PushNotificationConfig = &a2a.A2APushNotification{URL: "https://client.example/hook"}
+
+	sendA2A(t, handler, payload)
+	handler.background.Wait()
+
+	if len(pushService.notified) != 1 || pushService.notified[0].Status.State != a2a.TaskStateFailed {
+		t.Errorf("expected a failed task notification, got %+v", pushService.notified)
+	}
+}
+
+// TestHandleA2AMessage_TelexFixture runs a captured Telex message/send through the real push service,
+// with its push URL pointed at a local server
+func TestHandleA2AMessage_TelexFixture(t *testing.T) {
+	var delivered *http.Request
+	var deliveredTask a2a.A2AResult
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered = r
+		json.NewDecoder(r.Body).Decode(&deliveredTask)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	raw, err := os.ReadFile(filepath.Join("platforms", "testdata", "telex_message_send.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	const telexPushURL = "https://ping.telex.im/v1/a2a/webhooks/a8c1e3f0b2d44f6c8e0a1b3c5d7e9f12"
+	pushURL := server.URL + strings.TrimPrefix(telexPushURL, "https://ping.telex.im")
+	raw = bytes.Replace(raw, []byte(telexPushURL), []byte(pushURL), 1)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec("INSERT INTO push_notification_configs").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), pushURL, "telex-push-token", []byte(`["TelexApiKey"]`),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT (.+) FROM push_notification_configs").
+		WillReturnRows(sqlmock.NewRows([]string{"task_id", "config_id", "url", "token", "auth_schemes", "credentials", "created_at", "updated_at"}).
+			AddRow("task-1", nil, pushURL, "telex-push-token", []byte(`["TelexApiKey"]`), nil, now, now))
+	mock.ExpectExec("INSERT INTO push_deliveries").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	var received *ChatRequest
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			received = req
+			return &ChatResponse{Response: "That sounds like a lot to carry into tomorrow."}, nil
+		},
+	}
+	pushService := push.NewService(push.NewRepository(db), "", 1, time.Second)
+	// the push URL now points to the loopback test server
+	pushService.AllowPrivateNetworks()
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), NewMockTaskService(), pushService)
+
+	req := httptest.NewRequest(http.MethodPost, "/a2a/agent/eunoia", bytes.NewReader(raw))
+	w := httptest.NewRecorder()
+	handler.HandleA2AMessage(w, req)
+
+	var resp a2a.A2AResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("expected the Telex message to be accepted, got %+v", resp.Error)
+	}
+	if resp.Result.Status.State != a2a.TaskStateWorking {
+		t.Errorf("expected a working task, got %s", resp.Result.Status.State)
+	}
+
+	handler.background.Wait()
+
+	if received == nil || received.PlatformUserID != "telex:01990f5e-8c42-7b3a-9d1e-2f4a6b8c0d1e" {
+		t.Fatalf("unexpected chat request %+v", received)
+	}
+	if delivered == nil {
+		t.Fatal("expected the finished task to be pushed")
+	}
+	if delivered.Header.Get(push.TokenHeader) != "telex-push-token" || delivered.Header.Get("Authorization") != "" {
+		t.Errorf("expected only the token header, got %v", delivered.Header)
+	}
+	if deliveredTask.ID != resp.Result.ID || deliveredTask.Status.State != a2a.TaskStateCompleted {
+		t.Errorf("unexpected pushed task %+v", deliveredTask)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestHandleA2AMessage_PushNotSupported(t *testing.T) {
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), NewMockTaskService(), nil)
+
+	payload := newMessageRequest("Hello")
+	payload.Params.Configuration.PushNotificationConfig = &a2a.A2APushNotification{URL: "https://client.example/hook"}
+
+	resp := sendA2A(t, handler, payload)
+	if resp.Error == nil || resp.Error.Code != a2a.PushNotificationNotSupported {
+		t.Errorf("expected PushNotificationNotSupported, got %v", resp.Error)
+	}
+}
+
+func TestHandleA2AMessage_PushNotificationConfig(t *testing.T) {
+	tasks := NewMockTaskService()
+	pushService := NewMockPushService()
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), tasks, pushService)
+
+	created, _ := tasks.Create("telex:user-123", "ctx-1")
+
+	body, _ := json.Marshal(a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-1",
+		Method:  "tasks/pushNotificationConfig/set",
+		Params: a2a.A2AParams{
+			TaskID:                 created.ID,
+			PushNotificationConfig: &a2a.A2APushNotification{URL: "https://client.example/hook"},
+			Metadata:               telexUser("user-123"),
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/a2a/agent/eunoia", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.HandleA2AMessage(w, req)
+
+	var setResp a2a.A2APushConfigResponse
+	if err := json.NewDecoder(w.Body).Decode(&setResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if setResp.Result.TaskID != created.ID || setResp.Result.PushNotificationConfig.URL != "https://client.example/hook" {
+		t.Errorf("unexpected set result %+v", setResp.Result)
+	}
+
+	body, _ = json.Marshal(a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-2",
+		Method:  "tasks/pushNotificationConfig/get",
+		Params:  a2a.A2AParams{ID: created.ID, Metadata: telexUser("user-123")},
+	})
+	req = httptest.NewRequest(http.MethodPost, "/a2a/agent/eunoia", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	handler.HandleA2AMessage(w, req)
+
+	var getResp a2a.A2APushConfigResponse
+	if err := json.NewDecoder(w.Body).Decode(&getResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if getResp.ID != "req-2" || getResp.Result.PushNotificationConfig.URL != "https://client.example/hook" {
+		t.Errorf("unexpected get result %+v", getResp)
+	}
+
+	missing := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-3",
+		Method:  "tasks/pushNotificationConfig/set",
+		Params: a2a.A2AParams{
+			TaskID:                 "unknown",
+			PushNotificationConfig: &a2a.A2APushNotification{URL: "https://client.example/hook"},
+			Metadata:               telexUser("user-123"),
+		},
+	})
+	if missing.Error == nil || missing.Error.Code != a2a.TaskNotFound {
+		t.Errorf("expected TaskNotFound, got %v", missing.Error)
+	}
+
+	// another user can neither redirect the task's notifications nor read where they go
+	hijack := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-4",
+		Method:  "tasks/pushNotificationConfig/set",
+		Params: a2a.A2AParams{
+			TaskID:                 created.ID,
+			PushNotificationConfig: &a2a.A2APushNotification{URL: "https://attacker.example/hook"},
+			Metadata:               telexUser("user-456"),
+		},
+	})
+	if hijack.Error == nil || hijack.Error.Code != a2a.TaskNotFound {
+		t.Errorf("expected TaskNotFound for another user's task, got %v", hijack.Error)
+	}
+	if pushService.configs[created.ID].URL != "https://client.example/hook" {
+		t.Errorf("expected the owner's config to stay, got %+v", pushService.configs[created.ID])
+	}
+
+	peek := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-5",
+		Method:  "tasks/pushNotificationConfig/get",
+		Params:  a2a.A2AParams{ID: created.ID, Metadata: telexUser("user-456")},
+	})
+	if peek.Error == nil || peek.Error.Code != a2a.TaskNotFound {
+		t.Errorf("expected TaskNotFound for another user's task, got %v", peek.Error)
+	}
+
+	anonymous := sendA2A(t, handler, a2a.A2ARequest{
+		JSONRPC: "2.0",
+		ID:      "req-6",
+		Method:  "tasks/pushNotificationConfig/get",
+		Params:  a2a.A2AParams{ID: created.ID},
+	})
+	if anonymous.Error == nil || anonymous.Error.Code != a2a.InvalidParams {
+		t.Errorf("expected InvalidParams without caller metadata, got %v", anonymous.Error)
+	}
+}
+
+func TestHandleA2AMessage_NumericIDAndBatch(t *testing.T) {
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), NewMockTaskService(), nil)
+
+	body := `[
+		{"jsonrpc":"2.0","id":7,"method":"message/send","params":{"message":{"role":"user","parts":[{"kind":"text","text":"Hello"}],"metadata":{"telex_user_id":"user-123"},"messageId":"msg-1"}}},
+		{"jsonrpc":"2.0","id":8,"method":"tasks/get","params":{"id":"unknown","metadata":{"telex_user_id":"user-123"}}}
+	]`
+
+	req := httptest.NewRequest(http.MethodPost, "/a2a/agent/eunoia", bytes.NewReader([]byte(body)))
+	w := httptest.NewRecorder()
+	handler.HandleA2AMessage(w, req)
+
+	var responses []struct {
+		ID     int            `json:"id"`
+		Result *a2a.A2AResult `json:"result"`
+		Error  *a2a.A2AError  `json:"error"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&responses); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+	if responses[0].ID != 7 || responses[0].Result == nil || responses[0].Result.Status.State != a2a.TaskStateCompleted {
+		t.Errorf("unexpected message/send response %+v", responses[0])
+	}
+	if responses[1].ID != 8 || responses[1].Error == nil || responses[1].Error.Code != a2a.TaskNotFound {
+		t.Errorf("unexpected tasks/get response %+v", responses[1])
+	}
+}
+
+func TestHandleA2AMessage_FileAndDataParts(t *testing.T) {
+	var received *ChatRequest
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			received = req
+			return &ChatResponse{Response: "Logged"}, nil
+		},
+	}
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil)
+
+	payload := newMessageRequest("")
+	payload.Params.Message.Parts = []a2a.A2APart{
+		{Kind: "file", File: &a2a.A2AFile{Name: "sky.png", MimeType: "image/png", Bytes: "aGVsbG8="}},
+		{Kind: "data", Object: map[string]interface{}{"mood_score": 7}},
+	}
+
+	resp := sendA2A(t, handler, payload)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error %+v", resp.Error)
+	}
+
+	if received.Message != "" || len(received.Attachments) != 1 || string(received.Attachments[0].Data) != "hello" {
+		t.Errorf("expected the decoded image to be passed on, got %+v", received)
+	}
+	if len(received.Data) != 1 || received.Data[0]["mood_score"] != float64(7) {
+		t.Errorf("expected the data part to be passed on, got %+v", received.Data)
+	}
+}
+
+func TestHandleA2AMessage_LargeImage(t *testing.T) {
+	var received *ChatRequest
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			received = req
+			return &ChatResponse{Response: "What a view."}, nil
+		},
+	}
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil)
+	handler.SetMaxBodyBytes(agent.MaxInlineImagesBytes + a2a.DefaultMaxBodyBytes)
+
+	// a 3 MB image is over 1 MiB twice over once base64 encoded
+	image := bytes.Repeat([]byte{0xff}, 3<<20)
+	payload := newMessageRequest("look at this")
+	payload.Params.Message.Parts = append(payload.Params.Message.Parts, a2a.A2APart{Kind: "file",
+		File: &a2a.A2AFile{Name: "sky.jpg", MimeType: "image/jpeg", Bytes: base64.StdEncoding.EncodeToString(image)}})
+
+	resp := sendA2A(t, handler, payload)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error %+v", resp.Error)
+	}
+	if len(received.Attachments) != 1 || len(received.Attachments[0].Data) != len(image) {
+		t.Errorf("expected the image to be passed on, got %d attachments", len(received.Attachments))
+	}
+}
+
+func TestHandleA2AMessage_InvalidFileBytes(t *testing.T) {
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil)
+
+	payload := newMessageRequest("look at this")
+	payload.Params.Message.Parts = append(payload.Params.Message.Parts,
+		a2a.A2APart{Kind: "file", File: &a2a.A2AFile{MimeType: "image/png", Bytes: "not base64!"}})
+
+	resp := sendA2A(t, handler, payload)
+	if resp.Error == nil || resp.Error.Code != a2a.InvalidParams {
+		t.Errorf("expected InvalidParams, got %+v", resp.Error)
+	}
+}
+
+func TestHandleA2AMessage_OutputModes(t *testing.T) {
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			return &ChatResponse{
+				Response: "I've logged that",
+				Insights: &Insights{
+					Mood:      &DetectedMood{Score: 4, Label: "low", Source: MoodSourceCheckIn},
+					CheckInID: "checkin-1",
+					RiskFlags: []string{},
+				},
+			}, nil
+		},
+	}
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil)
+
+	tests := []struct {
+		name      string
+		modes     []string
+		wantNames []string
+	}{
+		{"unspecified", nil, []string{"eunoia_response"}},
+		{"text only", []string{"text/plain"}, []string{"eunoia_response"}},
+		{"text and json", []string{"text/plain", "application/json"}, []string{"eunoia_response", insightsArtifactName}},
+		{"json only", []string{"application/json"}, []string{insightsArtifactName}},
+		{"wildcard", []string{"*/*"}, []string{"eunoia_response", insightsArtifactName}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := newMessageRequest("I feel low")
+			payload.Params.Configuration.AcceptedOutputModes = tt.modes
+
+			resp := sendA2A(t, handler, payload)
+			if resp.Error != nil {
+				t.Fatalf("unexpected error %+v", resp.Error)
+			}
+
+			var names []string
+			for _, artifact := range resp.Result.Artifacts {
+				names = append(names, artifact.Name)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantNames) {
+				t.Fatalf("expected artifacts %v, got %v", tt.wantNames, names)
+			}
+
+			last := resp.Result.Artifacts[len(resp.Result.Artifacts)-1]
+			if last.Name == insightsArtifactName {
+				data := last.Parts[0].Object
+				if last.Parts[0].Kind != "data" || data["check_in_id"] != "checkin-1" {
+					t.Errorf("unexpected insights artifact %+v", last)
+				}
+				if flags, ok := data["risk_flags"].([]interface{}); !ok || len(flags) != 0 {
+					t.Errorf("expected an empty risk_flags list, got %v", data["risk_flags"])
+				}
+			}
+		})
+	}
+}
+
+func TestHandleA2AMessage_UnsupportedOutputModes(t *testing.T) {
+	handler := NewHandler(&MockService{}, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil)
+
+	payload := newMessageRequest("Hello")
+	payload.Params.Configuration.AcceptedOutputModes = []string{"image/png"}
+
+	resp := sendA2A(t, handler, payload)
+	if resp.Error == nil || resp.Error.Code != a2a.ContentTypeNotSupported {
+		t.Errorf("expected ContentTypeNotSupported, got %+v", resp.Error)
+	}
+}
+
+func TestHandleA2AMessage_NamespacesPlatformUsers(t *testing.T) {
+	var received *ChatRequest
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			received = req
+			return &ChatResponse{Response: "Hi"}, nil
+		},
+	}
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform(), platforms.NewPlatform("generic"))
+	handler := NewHandler(mockService, registry, nil, nil)
+
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		want     string
+	}{
+		{"detected from telex keys", map[string]interface{}{"telex_user_id": "user-123"}, "telex:user-123"},
+		{"named in metadata", map[string]interface{}{"platform": "generic", "user_id": "user-123"}, "generic:user-123"},
+		{"default platform", map[string]interface{}{"user_id": "user-123"}, "telex:user-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := newMessageRequest("Hello")
+			payload.Params.Message.Metadata = tt.metadata
+
+			resp := sendA2A(t, handler, payload)
+			if resp.Error != nil {
+				t.Fatalf("unexpected error %+v", resp.Error)
+			}
+			if received.PlatformUserID != tt.want {
+				t.Errorf("expected user %s, got %s", tt.want, received.PlatformUserID)
+			}
+		})
+	}
+
+	payload := newMessageRequest("Hello")
+	payload.Params.Message.Metadata = map[string]interface{}{"platform": "slack", "user_id": "user-123"}
+	resp := sendA2A(t, handler, payload)
+	if resp.Error == nil || resp.Error.Code != a2a.InvalidParams {
+		t.Errorf("expected InvalidParams for a disabled platform, got %+v", resp.Error)
+	}
+}
+
+func TestHandlePlatformA2AMessage(t *testing.T) {
+	var received *ChatRequest
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			received = req
+			return &ChatResponse{Response: "Hi"}, nil
+		},
+	}
+	registry := platforms.NewRegistry(platforms.NewTelexPlatform(), platforms.NewPlatform("generic"))
+	handler := NewHandler(mockService, registry, nil, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/a2a/agent/eunoia/{platform}", handler.HandlePlatformA2AMessage).Methods("POST")
+
+	// the path wins over telex keys in the metadata
+	body, _ := json.Marshal(newMessageRequest("Hello"))
+	req := httptest.NewRequest(http.MethodPost, "/a2a/agent/eunoia/generic", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp a2a.A2AResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error %+v", resp.Error)
+	}
+	if received.PlatformUserID != "generic:user-123" {
+		t.Errorf("expected generic:user-123, got %s", received.PlatformUserID)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/a2a/agent/eunoia/slack", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a disabled platform, got %d", w.Code)
+	}
+}
+
+func TestHandleChat(t *testing.T) {
+	var received *ChatRequest
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			received = req
+			return &ChatResponse{
+				Response:  "I've logged that for you.",
+				ContextID: "ctx-1",
+				Insights: &Insights{
+					Mood:      &DetectedMood{Score: 3, Label: "sad", Source: MoodSourceCheckIn},
+					CheckInID: "checkin-1",
+					RiskFlags: []string{},
+				},
+			}, nil
+		},
+	}
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil)
+
+	// the user comes from the token, whatever the body says
+	body := `{"platform_user_id":"someone-else","message":"I feel sad","channel_id":"phone"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/chat", bytes.NewReader([]byte(body)))
+	req = req.WithContext(chatauth.WithUser(req.Context(), "user-123"))
+	w := httptest.NewRecorder()
+
+	handler.HandleChat(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if received.PlatformUserID != "app:user-123" || received.ChannelID != "phone" || received.MessageID == "" {
+		t.Errorf("unexpected chat request %+v", received)
+	}
+
+	var resp ChatResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Response != "I've logged that for you." || resp.ContextID != "ctx-1" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if resp.Insights == nil || resp.Insights.CheckInID != "checkin-1" || resp.Insights.Mood.Score != 3 {
+		t.Errorf("expected the insights in the response, got %+v", resp.Insights)
+	}
+}
+
+func TestHandleChat_InvalidRequests(t *testing.T) {
+	mockService := &MockService{
+		ProcessMessageFunc: func(req *ChatRequest) (*ChatResponse, error) {
+			return nil, errors.New("model unavailable")
+		},
+	}
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil)
+
+	tests := []struct {
+		name   string
+		user   string
+		body   string
+		status int
+	}{
+		{"invalid JSON", "user-123", `{"message":`, http.StatusBadRequest},
+		{"no token user", "", `{"message":"hi"}`, http.StatusUnauthorized},
+		{"missing message", "user-123", `{"message":"  "}`, http.StatusBadRequest},
+		{"processing failure", "user-123", `{"message":"hi"}`, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/chat", bytes.NewReader([]byte(tt.body)))
+			req = req.WithContext(chatauth.WithUser(req.Context(), tt.user))
+			w := httptest.NewRecorder()
+
+			handler.HandleChat(w, req)
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandleChatHistory(t *testing.T) {
+	created := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	var requestedUser string
+	var requestedLimit int
+	mockService := &MockService{
+		HistoryFunc: func(platformUserID string, limit int) ([]*ConversationMessage, error) {
+			requestedUser, requestedLimit = platformUserID, limit
+			if platformUserID == "app:new-user" {
+				return nil, fmt.Errorf("user not found: %w", user.ErrUserNotFound)
+			}
+			return []*ConversationMessage{
+				{ID: "m1", MessageRole: "user", MessageContent: "hi", ContextData: "internal prompt context", CreatedAt: created},
+				{ID: "m2", MessageRole: "assistant", MessageContent: "Hello!", CreatedAt: created.Add(time.Second)},
+			}, nil
+		},
+	}
+	handler := NewHandler(mockService, platforms.NewRegistry(platforms.NewTelexPlatform()), nil, nil)
+
+	get := func(platformUserID, url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		handler.HandleChatHistory(w, req.WithContext(chatauth.WithUser(req.Context(), platformUserID)))
+		return w
+	}
+
+	// the user comes from the token, not the query
+	w := get("user-123", "/api/v1/chat/history?platform_user_id=someone-else&limit=20")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if requestedUser != "app:user-123" || requestedLimit != 20 {
+		t.Errorf("unexpected lookup %s %d", requestedUser, requestedLimit)
+	}
+	if strings.Contains(w.Body.String(), "internal prompt context") {
+		t.Error("expected the prompt context to stay private")
+	}
+
+	var resp struct {
+		Messages []ChatHistoryMessage `json:"messages"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Messages) != 2 || resp.Messages[0].ID != "m1" || resp.Messages[1].Role != "assistant" {
+		t.Errorf("unexpected messages %+v", resp.Messages)
+	}
+
+	w = get("new-user", "/api/v1/chat/history")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"messages":[]`) {
+		t.Errorf("expected an empty history for a new user, got %d %s", w.Code, w.Body.String())
+	}
+	if requestedLimit != defaultHistoryLimit {
+		t.Errorf("expected the default limit, got %d", requestedLimit)
+	}
+
+	if w := get("", "/api/v1/chat/history"); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token user, got %d", w.Code)
+	}
+	if w := get("u", "/api/v1/chat/history?limit=500"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a limit over 200, got %d", w.Code)
+	}
+}
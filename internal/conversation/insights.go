@@ -0,0 +1,86 @@
+package conversation
+
+import (
+	"sort"
+	"strings"
+)
+
+// sources of a detected mood
+const (
+	MoodSourceMessage = "message"
+	MoodSourceCheckIn = "check_in"
+)
+
+// risk flags raised by phrases in the user's message. They are deliberately broad, only reported to
+// clients that accept structured output and never change what the user is told.
+const (
+	RiskSuicidalIdeation = "suicidal_ideation"
+	RiskSelfHarm         = "self_harm"
+	RiskHarmToOthers     = "harm_to_others"
+	RiskAbuse            = "abuse"
+)
+
+var riskPhrases = map[string][]string{
+	RiskSuicidalIdeation: {
+		"kill myself", "end my life", "suicide", "suicidal", "want to die", "better off dead",
+		"no reason to live", "end it all", "don't want to be alive", "dont want to be alive",
+	},
+	RiskSelfHarm: {
+		"hurt myself", "harm myself", "cut myself", "cutting myself", "self harm", "self-harm", "burn myself",
+	},
+	RiskHarmToOthers: {
+		"hurt someone", "kill someone", "hurt them", "kill him", "kill her", "kill them",
+	},
+	RiskAbuse: {
+		"abusing me", "abuses me", "abused me", "being abused", "hits me", "beats me", "afraid to go home",
+	},
+}
+
+// detectRiskFlags returns the risk flags whose phrases appear in the message, in alphabetical order
+func detectRiskFlags(messageLower string) []string {
+	flags := []string{}
+	for flag, phrases := range riskPhrases {
+		for _, phrase := range phrases {
+			if strings.Contains(messageLower, phrase) {
+				flags = append(flags, flag)
+				break
+			}
+		}
+	}
+	sort.Strings(flags)
+	return flags
+}
+
+// newInsights starts the insights for a message with what its text shows
+func (s *Service) newInsights(message string) *Insights {
+	messageLower := strings.ToLower(message)
+
+	insights := &Insights{RiskFlags: detectRiskFlags(messageLower)}
+	if score, label := s.detectMoodIntent(messageLower); score > 0 {
+		insights.Mood = &DetectedMood{Score: score, Label: label, Source: MoodSourceMessage}
+	}
+	return insights
+}
+
+// addActionResult records what a confirmed action created
+func (i *Insights) addActionResult(result map[string]any) {
+	if checkInID, ok := result["check_in_id"].(string); ok {
+		i.CheckInID = checkInID
+		if score, ok := result["mood_score"].(float64); ok {
+			label, _ := result["mood_label"].(string)
+			i.Mood = &DetectedMood{Score: int(score), Label: label, Source: MoodSourceCheckIn}
+		}
+	}
+
+	if reflectionID, ok := result["reflection_id"].(string); ok {
+		reflection := &ReflectionInsight{ID: reflectionID}
+		reflection.Sentiment, _ = result["sentiment"].(string)
+		reflection.KeyThemes, _ = result["key_themes"].(string)
+		reflection.Analysis, _ = result["analysis"].(string)
+		i.Reflection = reflection
+	}
+
+	if reminderID, ok := result["reminder_id"].(string); ok {
+		i.ReminderID = reminderID
+	}
+}
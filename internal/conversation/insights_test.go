@@ -0,0 +1,59 @@
+package conversation
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDetectRiskFlags(t *testing.T) {
+	tests := []struct {
+		message string
+		want    []string
+	}{
+		{"i had a lovely walk today", []string{}},
+		{"some days i want to die", []string{RiskSuicidalIdeation}},
+		{"i keep wanting to hurt myself and i think about suicide", []string{RiskSelfHarm, RiskSuicidalIdeation}},
+		{"he hits me when he drinks", []string{RiskAbuse}},
+	}
+
+	for _, tt := range tests {
+		got := detectRiskFlags(tt.message)
+		if got == nil || fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%q: expected %v, got %#v", tt.message, tt.want, got)
+		}
+	}
+}
+
+func TestNewInsights_DetectsMood(t *testing.T) {
+	service := &Service{}
+
+	insights := service.newInsights("I feel anxious about work")
+	if insights.Mood == nil || insights.Mood.Source != MoodSourceMessage {
+		t.Errorf("expected a mood detected from the message, got %+v", insights.Mood)
+	}
+}
+
+func TestInsights_AddActionResult(t *testing.T) {
+	insights := &Insights{RiskFlags: []string{}}
+
+	insights.addActionResult(map[string]any{
+		"check_in_id": "checkin-1",
+		"mood_score":  float64(6),
+		"mood_label":  "okay",
+	})
+	insights.addActionResult(map[string]any{
+		"reflection_id": "reflection-1",
+		"sentiment":     "positive",
+		"analysis":      "You sound proud of yourself.",
+	})
+
+	if insights.CheckInID != "checkin-1" || insights.Mood.Score != 6 || insights.Mood.Source != MoodSourceCheckIn {
+		t.Errorf("unexpected check-in insights %+v", insights)
+	}
+	if insights.Reflection == nil || insights.Reflection.Sentiment != "positive" {
+		t.Errorf("unexpected reflection insights %+v", insights.Reflection)
+	}
+
+	// a declined action has no result
+	insights.addActionResult(nil)
+}
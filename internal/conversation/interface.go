@@ -1,7 +1,32 @@
 package conversation
 
+import (
+	"github.com/zjoart/eunoia/internal/task"
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
 // ServiceInterface defines the methods needed by the handler
 type ServiceInterface interface {
 	ProcessMessage(req *ChatRequest) (*ChatResponse, error)
+	ResolveContextID(platformUserID, channelID, contextID string) (string, error)
 	GetConversationHistory(platformUserID string, limit int) ([]*ConversationMessage, error)
+	Search(req *SearchRequest) ([]*SearchResult, error)
+}
+
+// TaskServiceInterface defines the A2A task storage needed by the handler
+type TaskServiceInterface interface {
+	Create(platformUserID, contextID string) (*task.Task, error)
+	SetState(taskID, state string) error
+	Finish(taskID string, result *a2a.A2AResult) error
+	Fail(taskID, reason string) error
+	Get(taskID string, historyLength *int) (*a2a.A2AResult, error)
+	GetForUser(taskID, platformUserID string, historyLength *int) (*a2a.A2AResult, error)
+	Cancel(taskID, platformUserID string) (*a2a.A2AResult, error)
+}
+
+// PushServiceInterface defines the push notification delivery needed by the handler
+type PushServiceInterface interface {
+	SetConfig(taskID string, config *a2a.A2APushNotification) error
+	GetConfig(taskID string) (*a2a.A2APushNotification, error)
+	Notify(taskID string, result *a2a.A2AResult) error
 }
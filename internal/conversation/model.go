@@ -1,6 +1,10 @@
 package conversation
 
-import "time"
+import (
+	"time"
+
+	"github.com/zjoart/eunoia/internal/agent"
+)
 
 type ConversationMessage struct {
 	ID             string    `json:"id"`
@@ -8,16 +12,149 @@ type ConversationMessage struct {
 	MessageRole    string    `json:"message_role"`
 	MessageContent string    `json:"message_content"`
 	MessageID      string    `json:"message_id,omitempty"`
+	SessionID      string    `json:"session_id,omitempty"`
 	ContextData    string    `json:"context_data,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
+	// Seq is assigned by the database in insertion order, which created_at cannot resolve within a second
+	Seq int64 `json:"-"`
+}
+
+// ChatHistoryMessage is a stored message as the chat API returns it; ID orders and resumes the history
+type ChatHistoryMessage struct {
+	ID        string    `json:"id"`
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ConversationSession groups a user's messages in one channel under a stable A2A context ID
+type ConversationSession struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	ChannelID    string    `json:"channel_id,omitempty"`
+	ContextID    string    `json:"context_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastActiveAt time.Time `json:"last_active_at"`
 }
 
 type ChatRequest struct {
 	PlatformUserID string `json:"platform_user_id"`
 	Message        string `json:"message"`
 	MessageID      string `json:"message_id"`
+	ChannelID      string `json:"channel_id,omitempty"`
+	// ContextID continues an existing session; when empty the user's latest session in the channel is used
+	ContextID string `json:"context_id,omitempty"`
+	// Attachments and Data arrive as A2A file and data parts; either may stand in for Message
+	Attachments []Attachment             `json:"-"`
+	Data        []map[string]interface{} `json:"data,omitempty"`
+	// OnToken, when set, receives the model's reply while it is written
+	OnToken agent.TokenHandler `json:"-"`
+}
+
+// Attachment is a file sent with a message, inline as Data or by URI
+type Attachment struct {
+	Name     string
+	MimeType string
+	Data     []byte
+	URI      string
 }
 
 type ChatResponse struct {
 	Response string `json:"response"`
+	// InputRequired is set when the reply asks the user to confirm a proposed action
+	InputRequired bool `json:"input_required,omitempty"`
+	// ContextID identifies the session the message was handled in
+	ContextID string `json:"context_id,omitempty"`
+	// HistoryID is the stored reply's conversation history ID, which history pages and resumption refer to
+	HistoryID string `json:"history_id,omitempty"`
+	// Insights are the structured facts behind the reply, for clients that accept JSON
+	Insights *Insights `json:"insights,omitempty"`
+}
+
+// Insights describe what Eunoia noticed or did while handling a message
+type Insights struct {
+	Mood       *DetectedMood      `json:"mood,omitempty"`
+	CheckInID  string             `json:"check_in_id,omitempty"`
+	Reflection *ReflectionInsight `json:"reflection,omitempty"`
+	ReminderID string             `json:"reminder_id,omitempty"`
+	RiskFlags  []string           `json:"risk_flags"`
+}
+
+// DetectedMood is a mood read from the message text or taken from a check-in
+type DetectedMood struct {
+	Score  int    `json:"score"`
+	Label  string `json:"label"`
+	Source string `json:"source"`
+}
+
+// ReflectionInsight is the analysis of a reflection saved while handling the message
+type ReflectionInsight struct {
+	ID        string `json:"id"`
+	Sentiment string `json:"sentiment,omitempty"`
+	KeyThemes string `json:"key_themes,omitempty"`
+	Analysis  string `json:"analysis,omitempty"`
+}
+
+type MessageSearchResult struct {
+	Message *ConversationMessage `json:"message"`
+	Score   float64              `json:"score"`
+}
+
+type SearchRequest struct {
+	PlatformUserID string    `json:"platform_user_id"`
+	Query          string    `json:"query"`
+	From           time.Time `json:"from,omitempty"`
+	To             time.Time `json:"to,omitempty"`
+	Limit          int       `json:"limit"`
+}
+
+// SearchResult is a single ranked hit from either reflections or conversation history
+type SearchResult struct {
+	Source    string    `json:"source"`
+	ID        string    `json:"id"`
+	Role      string    `json:"role,omitempty"`
+	Snippet   string    `json:"snippet"`
+	Score     float64   `json:"score"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ConversationSummary condenses a range of older messages; each summary also folds in the one before it
+type ConversationSummary struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"user_id"`
+	Summary        string    `json:"summary"`
+	FirstMessageID string    `json:"first_message_id"`
+	LastMessageID  string    `json:"last_message_id"`
+	FirstMessageAt time.Time `json:"first_message_at"`
+	LastMessageAt  time.Time `json:"last_message_at"`
+	// LastMessageSeq is the Seq of the last message covered, where the next summary starts
+	LastMessageSeq int64     `json:"last_message_seq"`
+	MessageCount   int       `json:"message_count"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Tool invocation statuses; only pending invocations can still be confirmed, and a confirmed one is
+// executing from the moment it is claimed until it is resolved
+const (
+	InvocationPending   = "pending"
+	InvocationExecuting = "executing"
+	InvocationExecuted  = "executed"
+	InvocationDeclined  = "declined"
+	InvocationExpired   = "expired"
+	InvocationFailed    = "failed"
+)
+
+// ToolInvocation records an action the model proposed through a write tool and its outcome
+type ToolInvocation struct {
+	ID           string     `json:"id"`
+	UserID       string     `json:"user_id"`
+	MessageID    string     `json:"message_id,omitempty"`
+	ToolName     string     `json:"tool_name"`
+	Arguments    string     `json:"arguments"`
+	Summary      string     `json:"summary"`
+	Status       string     `json:"status"`
+	Result       string     `json:"result,omitempty"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty"`
 }
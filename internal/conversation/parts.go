@@ -0,0 +1,134 @@
+package conversation
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/checkin"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// stored in place of the text of a message that only carried attachments
+const attachmentOnlyMessage = "(shared an attachment)"
+
+// keys a client may use for a mood check-in sent as structured data, e.g. from a mood slider
+var (
+	moodScoreKeys       = []string{"mood_score", "moodScore", "mood"}
+	moodLabelKeys       = []string{"mood_label", "moodLabel", "label"}
+	moodDescriptionKeys = []string{"description", "note"}
+)
+
+// dataCheckIn finds a mood check-in in structured data, returning nil when there is none or it is invalid
+func dataCheckIn(platformUserID, message string, objects []map[string]interface{}) *checkin.CreateCheckInRequest {
+	for _, object := range objects {
+		value, ok := firstValue(object, moodScoreKeys)
+		if !ok {
+			continue
+		}
+
+		score, ok := value.(float64)
+		if !ok || score != math.Trunc(score) || score < 1 || score > 10 {
+			logger.Warn("ignoring invalid mood score in data part", logger.Fields{"value": fmt.Sprint(value)})
+			continue
+		}
+
+		label := strings.ToLower(firstString(object, moodLabelKeys))
+		if label == "" {
+			label = checkin.LabelForScore(int(score))
+		}
+
+		description := firstString(object, moodDescriptionKeys)
+		if description == "" {
+			description = message
+		}
+
+		return &checkin.CreateCheckInRequest{
+			PlatformUserID: platformUserID,
+			MoodScore:      int(score),
+			MoodLabel:      label,
+			Description:    description,
+		}
+	}
+
+	return nil
+}
+
+// logDataCheckIn saves a check-in sent as data, returning the reply for a check-in sent on its own
+// and the context to give the model when the user also wrote something
+func (s *Service) logDataCheckIn(userID string, req *checkin.CreateCheckInRequest, insights *Insights) (string, []string) {
+	created, err := s.checkInService.CreateCheckIn(req)
+	if err != nil {
+		logger.Warn("failed to log check-in from data part", logger.Merge(
+			logger.WithError(err),
+			logger.WithUserID(userID),
+		))
+		return "Sorry, I couldn't log your check-in just now. Could you try again in a little while?", nil
+	}
+
+	logger.Info("logged check-in from data part", logger.Fields{
+		"user_id":     userID,
+		"check_in_id": created.ID,
+	})
+
+	insights.CheckInID = created.ID
+	insights.Mood = &DetectedMood{Score: created.MoodScore, Label: created.MoodLabel, Source: MoodSourceCheckIn}
+
+	reply := fmt.Sprintf("Thanks for checking in - I've logged your mood as %d/10 (%s).", created.MoodScore, created.MoodLabel)
+	context := fmt.Sprintf("The user just logged a mood check-in of %d/10 (%s) from the app. It is already saved, so do not offer to log it again.",
+		created.MoodScore, created.MoodLabel)
+
+	return reply, []string{context}
+}
+
+func firstValue(object map[string]interface{}, keys []string) (interface{}, bool) {
+	for _, key := range keys {
+		if value, ok := object[key]; ok && value != nil {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func firstString(object map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		if value, ok := object[key].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// splitAttachments picks the images the model can look at and describes the rest for the prompt
+func splitAttachments(attachments []Attachment) ([]agent.Image, []string) {
+	var images []agent.Image
+	var others []string
+
+	for _, attachment := range attachments {
+		if len(attachment.Data) > 0 && agent.IsSupportedImage(attachment.MimeType) &&
+			len(attachment.Data) <= agent.MaxImageBytes && len(images) < agent.MaxImages {
+			images = append(images, agent.Image{MimeType: attachment.MimeType, Data: attachment.Data})
+			continue
+		}
+
+		name := attachment.Name
+		if name == "" {
+			name = "unnamed file"
+		}
+		if attachment.MimeType != "" {
+			name += " (" + attachment.MimeType + ")"
+		}
+		others = append(others, name)
+	}
+
+	return images, others
+}
+
+// attachmentContext tells the model about attachments it was not given
+func attachmentContext(others []string) []string {
+	if len(others) == 0 {
+		return nil
+	}
+	return []string{"The user attached files you cannot view: " + strings.Join(others, ", ") + ". Say so if they ask about them."}
+}
@@ -0,0 +1,61 @@
+package conversation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDataCheckIn(t *testing.T) {
+	tests := []struct {
+		name      string
+		objects   []map[string]interface{}
+		wantScore int
+		wantLabel string
+	}{
+		{"slider", []map[string]interface{}{{"mood_score": 7.0}}, 7, "content"},
+		{"camel case with label", []map[string]interface{}{{"moodScore": 3.0, "moodLabel": "Tired"}}, 3, "tired"},
+		{"first valid object wins", []map[string]interface{}{{"theme": "dark"}, {"mood": 9.0}}, 9, "joyful"},
+		{"out of range", []map[string]interface{}{{"mood_score": 11.0}}, 0, ""},
+		{"fractional", []map[string]interface{}{{"mood_score": 6.5}}, 0, ""},
+		{"not a number", []map[string]interface{}{{"mood_score": "7"}}, 0, ""},
+		{"no mood", []map[string]interface{}{{"theme": "dark"}}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := dataCheckIn("platform-1", "long day", tt.objects)
+			if tt.wantScore == 0 {
+				if req != nil {
+					t.Errorf("expected no check-in, got %+v", req)
+				}
+				return
+			}
+			if req == nil {
+				t.Fatal("expected a check-in")
+			}
+			if req.MoodScore != tt.wantScore || req.MoodLabel != tt.wantLabel || req.Description != "long day" {
+				t.Errorf("unexpected check-in %+v", req)
+			}
+		})
+	}
+}
+
+func TestSplitAttachments(t *testing.T) {
+	attachments := []Attachment{
+		{Name: "sky.png", MimeType: "image/png", Data: []byte("png")},
+		{Name: "notes.pdf", MimeType: "application/pdf", Data: []byte("pdf")},
+		{Name: "linked.jpg", MimeType: "image/jpeg", URI: "https://example.com/linked.jpg"},
+	}
+
+	images, others := splitAttachments(attachments)
+
+	if len(images) != 1 || images[0].MimeType != "image/png" {
+		t.Errorf("expected only the inline png to reach the model, got %+v", images)
+	}
+	if len(others) != 2 || others[0] != "notes.pdf (application/pdf)" {
+		t.Errorf("unexpected other attachments %v", others)
+	}
+	if context := attachmentContext(others); len(context) != 1 || !strings.Contains(context[0], "linked.jpg") {
+		t.Errorf("expected the model to be told about unviewable files, got %v", context)
+	}
+}
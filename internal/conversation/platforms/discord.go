@@ -0,0 +1,32 @@
+package platforms
+
+import (
+	"errors"
+)
+
+// metadata keys the Discord adapter fills from an interaction
+const (
+	DiscordUserKey    = "discord_user_id"
+	DiscordChannelKey = "discord_channel_id"
+)
+
+// DiscordPlatform maps Discord users and channels onto platform IDs; user IDs are global snowflakes
+type DiscordPlatform struct {
+	*PlatformImpl
+}
+
+func NewDiscordPlatform() *DiscordPlatform {
+	return &DiscordPlatform{PlatformImpl: NewPlatform("discord")}
+}
+
+func (p *DiscordPlatform) ExtractUserID(metadata map[string]interface{}) (string, error) {
+	if userID, ok := metadata[DiscordUserKey].(string); ok && userID != "" {
+		return userID, nil
+	}
+	return "", errors.New("discord_user_id is required in metadata")
+}
+
+func (p *DiscordPlatform) ExtractChannelID(metadata map[string]interface{}) (string, error) {
+	channelID, _ := metadata[DiscordChannelKey].(string)
+	return channelID, nil
+}
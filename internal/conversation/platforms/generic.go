@@ -0,0 +1,140 @@
+package platforms
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/a2a"
+	"github.com/zjoart/eunoia/pkg/id"
+)
+
+type PlatformImpl struct {
+	name string
+}
+
+func NewPlatform(name string) *PlatformImpl {
+	return &PlatformImpl{name: name}
+}
+
+func (p *PlatformImpl) Name() string {
+	return p.name
+}
+
+// Matches recognises metadata carrying the platform's own user or channel key, e.g. telex_user_id
+func (p *PlatformImpl) Matches(metadata map[string]interface{}) bool {
+	for _, key := range []string{p.name + "_user_id", p.name + "_channel_id"} {
+		if value, ok := metadata[key].(string); ok && value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PlatformImpl) ExtractMessage(parts []a2a.A2APart) string {
+	var lastText string
+
+	// Iterate through all parts to find the last text message
+	for _, part := range parts {
+		if part.Kind == "text" && part.Text != "" {
+			lastText = part.Text
+		} else if part.Kind == "data" && len(part.Data) > 0 {
+			// Check data parts for the last message
+			for _, dataPart := range part.Data {
+				if dataPart.Kind == "text" && dataPart.Text != "" {
+					if text := stripHTML(dataPart.Text); text != "" {
+						lastText = text
+					}
+				}
+			}
+		}
+	}
+
+	return strings.TrimSpace(lastText)
+}
+
+// ExtractHistory extracts conversation history from the text items in the data parts
+func (p *PlatformImpl) ExtractHistory(parts []a2a.A2APart, currentMessageID string) []a2a.A2AMessageResult {
+	return historyFromData(parts, stripHTML)
+}
+
+func (p *PlatformImpl) ExtractUserID(metadata map[string]interface{}) (string, error) {
+	userIDKeys := []string{"platform_user_id", "telex_user_id", "user_id"}
+
+	for _, key := range userIDKeys {
+		if userID, ok := metadata[key].(string); ok && userID != "" {
+			return userID, nil
+		}
+	}
+
+	return "", errors.New("user_id is required in metadata")
+}
+
+func (p *PlatformImpl) ExtractChannelID(metadata map[string]interface{}) (string, error) {
+	channelIDKeys := []string{"platform_channel_id", "telex_channel_id", "channel_id"}
+
+	for _, key := range channelIDKeys {
+		if channelID, ok := metadata[key].(string); ok && channelID != "" {
+			return channelID, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (p *PlatformImpl) BuildResult(taskID, contextID, messageID string, history []a2a.A2AMessageResult, response *a2a.ChatResponse) *a2a.A2AResult {
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+
+	// Create the new agent response message (using messageID from request)
+	newMessage := a2a.A2AMessageResult{
+		MessageID: messageID,
+		Role:      "agent",
+		Parts: []a2a.A2APart{
+			{
+				Kind: "text",
+				Text: response.Response,
+			},
+		},
+		Kind:      "message",
+		TaskID:    taskID,
+		ContextID: contextID,
+		Metadata: map[string]interface{}{
+			"agent": "eunoia",
+		},
+	}
+
+	state := a2a.TaskStateCompleted
+	if response.InputRequired {
+		state = a2a.TaskStateInputRequired
+	}
+
+	// Append the new agent response to history
+	updatedHistory := append(history, newMessage)
+
+	// Create artifact with the agent's response
+	artifacts := []a2a.A2AArtifact{
+		{
+			ArtifactID: id.Generate(),
+			Name:       "eunoia_response",
+			Parts: []a2a.A2APart{
+				{
+					Kind: "text",
+					Text: response.Response,
+				},
+			},
+		},
+	}
+
+	return &a2a.A2AResult{
+		ID:        taskID,
+		ContextID: contextID,
+		Status: a2a.A2ATaskStatus{
+			State:     state,
+			Timestamp: timestamp,
+			Message:   newMessage,
+		},
+		Artifacts: artifacts,
+		History:   updatedHistory,
+		Kind:      "task",
+	}
+}
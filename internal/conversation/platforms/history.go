@@ -0,0 +1,39 @@
+package platforms
+
+import (
+	"github.com/zjoart/eunoia/pkg/a2a"
+	"github.com/zjoart/eunoia/pkg/id"
+)
+
+// historyFromData builds history from the text items nested in data parts. The items Telex sends
+// carry only their text, with no author or ID, so who wrote each one is unknown: they keep the
+// user role of the message that carried them and get new IDs.
+func historyFromData(parts []a2a.A2APart, clean func(string) string) []a2a.A2AMessageResult {
+	var history []a2a.A2AMessageResult
+
+	for _, part := range parts {
+		if part.Kind != a2a.PartKindData {
+			continue
+		}
+
+		for _, item := range part.Data {
+			if item.Kind != a2a.PartKindText {
+				continue
+			}
+
+			text := clean(item.Text)
+			if text == "" {
+				continue
+			}
+
+			history = append(history, a2a.A2AMessageResult{
+				MessageID: id.Generate(),
+				Role:      "user",
+				Parts:     []a2a.A2APart{{Kind: a2a.PartKindText, Text: text}},
+				Kind:      "message",
+			})
+		}
+	}
+
+	return history
+}
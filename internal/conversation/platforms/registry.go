@@ -0,0 +1,101 @@
+package platforms
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetadataKey names a platform explicitly in message metadata, overriding detection
+const MetadataKey = "platform"
+
+// builtin holds the platforms that can be enabled in config, by name
+var builtin = map[string]func() Platform{
+	"telex":    func() Platform { return NewTelexPlatform() },
+	"generic":  func() Platform { return NewPlatform("generic") },
+	"slack":    func() Platform { return NewSlackPlatform() },
+	"telegram": func() Platform { return NewTelegramPlatform() },
+	"discord":  func() Platform { return NewDiscordPlatform() },
+}
+
+// Detector is implemented by platforms that can recognise their own message metadata
+type Detector interface {
+	Matches(metadata map[string]interface{}) bool
+}
+
+// Registry holds the enabled platforms; the first one registered is the default
+type Registry struct {
+	platforms map[string]Platform
+	names     []string
+}
+
+func NewRegistry(platforms ...Platform) *Registry {
+	r := &Registry{platforms: make(map[string]Platform)}
+	for _, platform := range platforms {
+		r.Register(platform)
+	}
+	return r
+}
+
+// NewRegistryFromNames enables the named built-in platforms, in order
+func NewRegistryFromNames(names []string) (*Registry, error) {
+	r := NewRegistry()
+	for _, name := range names {
+		create, ok := builtin[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown platform %q", name)
+		}
+		r.Register(create())
+	}
+
+	if len(r.names) == 0 {
+		return nil, fmt.Errorf("at least one platform must be enabled")
+	}
+	return r, nil
+}
+
+// Register adds a platform, replacing any platform already registered under its name
+func (r *Registry) Register(platform Platform) {
+	if _, exists := r.platforms[platform.Name()]; !exists {
+		r.names = append(r.names, platform.Name())
+	}
+	r.platforms[platform.Name()] = platform
+}
+
+func (r *Registry) Get(name string) (Platform, bool) {
+	platform, ok := r.platforms[name]
+	return platform, ok
+}
+
+// Names returns the enabled platforms in registration order
+func (r *Registry) Names() []string {
+	return append([]string(nil), r.names...)
+}
+
+// Select picks the platform for a message: the one named in its metadata, then the first that
+// recognises the metadata, then the default
+func (r *Registry) Select(metadata map[string]interface{}) (Platform, error) {
+	if name, ok := metadata[MetadataKey].(string); ok && name != "" {
+		platform, ok := r.platforms[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("platform %s is not enabled", name)
+		}
+		return platform, nil
+	}
+
+	for _, name := range r.names {
+		if detector, ok := r.platforms[name].(Detector); ok && detector.Matches(metadata) {
+			return r.platforms[name], nil
+		}
+	}
+
+	if len(r.names) == 0 {
+		return nil, fmt.Errorf("no platforms are enabled")
+	}
+	return r.platforms[r.names[0]], nil
+}
+
+// NamespacedUserID prefixes a platform's user ID with the platform name, so IDs from different
+// platforms never collide in users.platform_user_id
+func NamespacedUserID(platformName, userID string) string {
+	return platformName + ":" + userID
+}
@@ -0,0 +1,64 @@
+package platforms
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewRegistryFromNames(t *testing.T) {
+	registry, err := NewRegistryFromNames([]string{"generic", "telex"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := registry.Names(); !reflect.DeepEqual(names, []string{"generic", "telex"}) {
+		t.Errorf("expected registration order, got %v", names)
+	}
+
+	if _, err := NewRegistryFromNames([]string{"telex", "myspace"}); err == nil {
+		t.Error("expected an error for an unknown platform")
+	}
+	if _, err := NewRegistryFromNames(nil); err == nil {
+		t.Error("expected an error when no platform is enabled")
+	}
+}
+
+func TestRegistry_Select(t *testing.T) {
+	registry := NewRegistry(NewPlatform("generic"), NewTelexPlatform())
+
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		want     string
+		wantErr  bool
+	}{
+		{"explicit", map[string]interface{}{"platform": "Telex", "user_id": "u1"}, "telex", false},
+		{"detected", map[string]interface{}{"telex_channel_id": "c1"}, "telex", false},
+		{"default", map[string]interface{}{"user_id": "u1"}, "generic", false},
+		{"no metadata", nil, "generic", false},
+		{"not enabled", map[string]interface{}{"platform": "discord"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platform, err := registry.Select(tt.metadata)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if platform.Name() != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, platform.Name())
+			}
+		})
+	}
+}
+
+func TestNamespacedUserID(t *testing.T) {
+	if got := NamespacedUserID("telex", "user-123"); got != "telex:user-123" {
+		t.Errorf("expected telex:user-123, got %s", got)
+	}
+}
@@ -0,0 +1,87 @@
+package platforms
+
+import (
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
+// metadata keys the Slack adapter fills from an event
+const (
+	SlackUserKey    = "slack_user_id"
+	SlackTeamKey    = "slack_team_id"
+	SlackChannelKey = "slack_channel_id"
+	SlackThreadKey  = "slack_thread_ts"
+)
+
+var (
+	// <@U123> and <@U123|name> user mentions
+	slackMentionPattern = regexp.MustCompile(`<@([A-Z0-9]+)(?:\|[^>]*)?>`)
+	// <#C123|general> channel references
+	slackChannelPattern = regexp.MustCompile(`<#[A-Z0-9]+\|([^>]*)>`)
+	// <https://example.com|label> and <https://example.com> links
+	slackLinkPattern = regexp.MustCompile(`<((?:https?|mailto):[^|>]+)(?:\|([^>]*))?>`)
+)
+
+// SlackPlatform maps Slack users, channels and threads onto platform IDs. User IDs include the
+// workspace when known, and each thread is its own channel so it gets its own conversation session.
+type SlackPlatform struct {
+	*PlatformImpl
+}
+
+func NewSlackPlatform() *SlackPlatform {
+	return &SlackPlatform{PlatformImpl: NewPlatform("slack")}
+}
+
+func (p *SlackPlatform) ExtractUserID(metadata map[string]interface{}) (string, error) {
+	userID, _ := metadata[SlackUserKey].(string)
+	if userID == "" {
+		return "", errors.New("slack_user_id is required in metadata")
+	}
+
+	if teamID, _ := metadata[SlackTeamKey].(string); teamID != "" {
+		return teamID + ":" + userID, nil
+	}
+	return userID, nil
+}
+
+func (p *SlackPlatform) ExtractChannelID(metadata map[string]interface{}) (string, error) {
+	channelID, _ := metadata[SlackChannelKey].(string)
+	if channelID == "" {
+		return "", nil
+	}
+
+	if threadTS, _ := metadata[SlackThreadKey].(string); threadTS != "" {
+		return channelID + ":" + threadTS, nil
+	}
+	return channelID, nil
+}
+
+// ExtractMessage returns the text parts with Slack's markup turned into plain text
+func (p *SlackPlatform) ExtractMessage(parts []a2a.A2APart) string {
+	var texts []string
+	for _, part := range parts {
+		if part.Kind == a2a.PartKindText && strings.TrimSpace(part.Text) != "" {
+			texts = append(texts, SlackPlainText(part.Text))
+		}
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n"))
+}
+
+// SlackPlainText turns mentions, channel references and links into readable text and decodes
+// the &amp;, &lt; and &gt; escapes
+func SlackPlainText(text string) string {
+	text = slackMentionPattern.ReplaceAllString(text, "@$1")
+	text = slackChannelPattern.ReplaceAllString(text, "#$1")
+	text = slackLinkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		match := slackLinkPattern.FindStringSubmatch(link)
+		if match[2] != "" {
+			return match[2] + " (" + match[1] + ")"
+		}
+		return match[1]
+	})
+	return strings.TrimSpace(html.UnescapeString(text))
+}
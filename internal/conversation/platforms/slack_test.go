@@ -0,0 +1,39 @@
+package platforms
+
+import "testing"
+
+func TestSlackPlatform_IDs(t *testing.T) {
+	platform := NewSlackPlatform()
+
+	userID, err := platform.ExtractUserID(map[string]interface{}{SlackUserKey: "U1", SlackTeamKey: "T1"})
+	if err != nil || userID != "T1:U1" {
+		t.Errorf("expected T1:U1, got %q (%v)", userID, err)
+	}
+	if _, err := platform.ExtractUserID(map[string]interface{}{SlackTeamKey: "T1"}); err == nil {
+		t.Error("expected an error without a user")
+	}
+
+	channelID, _ := platform.ExtractChannelID(map[string]interface{}{SlackChannelKey: "C1", SlackThreadKey: "17.5"})
+	if channelID != "C1:17.5" {
+		t.Errorf("expected the thread as its own channel, got %q", channelID)
+	}
+}
+
+func TestSlackPlainText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"hi <@U123>", "hi @U123"},
+		{"in <#C42|general>", "in #general"},
+		{"read <https://example.com|this>", "read this (https://example.com)"},
+		{"<https://example.com>", "https://example.com"},
+		{"fish &amp; chips &lt;3", "fish & chips <3"},
+	}
+
+	for _, tt := range tests {
+		if got := SlackPlainText(tt.in); got != tt.want {
+			t.Errorf("SlackPlainText(%q): expected %q, got %q", tt.in, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package platforms
+
+import (
+	"errors"
+)
+
+// metadata keys the Telegram adapter fills from an update
+const (
+	TelegramUserKey = "telegram_user_id"
+	TelegramChatKey = "telegram_chat_id"
+)
+
+// TelegramPlatform maps Telegram users and chats onto platform IDs
+type TelegramPlatform struct {
+	*PlatformImpl
+}
+
+func NewTelegramPlatform() *TelegramPlatform {
+	return &TelegramPlatform{PlatformImpl: NewPlatform("telegram")}
+}
+
+func (p *TelegramPlatform) ExtractUserID(metadata map[string]interface{}) (string, error) {
+	if userID, ok := metadata[TelegramUserKey].(string); ok && userID != "" {
+		return userID, nil
+	}
+	return "", errors.New("telegram_user_id is required in metadata")
+}
+
+func (p *TelegramPlatform) ExtractChannelID(metadata map[string]interface{}) (string, error) {
+	chatID, _ := metadata[TelegramChatKey].(string)
+	return chatID, nil
+}
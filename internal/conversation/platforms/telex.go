@@ -1,178 +1,48 @@
 package platforms
 
 import (
-	"errors"
-	"strings"
-	"time"
-
-	"github.com/zjoart/eunoia/internal/a2a"
-	"github.com/zjoart/eunoia/pkg/id"
+	"github.com/zjoart/eunoia/pkg/a2a"
 )
 
-type PlatformImpl struct {
-	name string
-}
-
-func NewPlatform(name string) *PlatformImpl {
-	return &PlatformImpl{name: name}
+// TelexPlatform reads Telex payloads: the message is the top level text part, and a data part
+// carries the channel's recent messages as HTML, ending with a copy of the message being answered
+type TelexPlatform struct {
+	*PlatformImpl
 }
 
-func (p *PlatformImpl) Name() string {
-	return p.name
+func NewTelexPlatform() *TelexPlatform {
+	return &TelexPlatform{PlatformImpl: NewPlatform("telex")}
 }
 
-func (p *PlatformImpl) ExtractMessage(parts []a2a.A2APart) string {
-	var lastText string
-
-	// Iterate through all parts to find the last text message
-	for _, part := range parts {
-		if part.Kind == "text" && part.Text != "" {
-			lastText = part.Text
-		} else if part.Kind == "data" && len(part.Data) > 0 {
-			// Check data parts for the last message
-			for _, dataPart := range part.Data {
-				if dataPart.Kind == "text" && dataPart.Text != "" {
-					text := strings.ReplaceAll(dataPart.Text, "<p>", "")
-					text = strings.ReplaceAll(text, "</p>", "")
-					text = strings.TrimSpace(text)
-					if text != "" {
-						lastText = text
-					}
-				}
-			}
+// ExtractMessage returns the last top level text part, or the last history item when Telex sent none
+func (p *TelexPlatform) ExtractMessage(parts []a2a.A2APart) string {
+	for i := len(parts) - 1; i >= 0; i-- {
+		if parts[i].Kind != a2a.PartKindText {
+			continue
 		}
-	}
-
-	return strings.TrimSpace(lastText)
-}
-
-// ExtractHistory extracts conversation history from the data parts
-func (p *PlatformImpl) ExtractHistory(parts []a2a.A2APart, currentMessageID string) []a2a.A2AMessageResult {
-	var history []a2a.A2AMessageResult
-
-	for _, part := range parts {
-		if part.Kind == "data" && len(part.Data) > 0 {
-			// Each pair of texts in data represents a conversation turn
-			// Odd indices are typically user messages, even are agent responses
-			for i, dataPart := range part.Data {
-				if dataPart.Kind == "text" && dataPart.Text != "" {
-					text := strings.ReplaceAll(dataPart.Text, "<p>", "")
-					text = strings.ReplaceAll(text, "</p>", "")
-					text = strings.TrimSpace(text)
-
-					if text == "" {
-						continue
-					}
-
-					// Determine role based on pattern (this is a simple heuristic)
-					role := "user"
-					if i%2 == 1 {
-						role = "agent"
-					}
-
-					history = append(history, a2a.A2AMessageResult{
-						MessageID: currentMessageID,
-						Role:      role,
-						TaskID:    id.Generate(),
-						Parts: []a2a.A2APart{
-							{
-								Kind: "text",
-								Text: text,
-							},
-						},
-						Kind: "message",
-					})
-				}
-			}
-		}
-	}
-
-	return history
-}
-
-func (p *PlatformImpl) ExtractUserID(metadata map[string]interface{}) (string, error) {
-	userIDKeys := []string{"platform_user_id", "telex_user_id", "user_id"}
-
-	for _, key := range userIDKeys {
-		if userID, ok := metadata[key].(string); ok && userID != "" {
-			return userID, nil
+		if text := stripHTML(parts[i].Text); text != "" {
+			return text
 		}
 	}
 
-	return "", errors.New("user_id is required in metadata")
-}
-
-func (p *PlatformImpl) ExtractChannelID(metadata map[string]interface{}) (string, error) {
-	channelIDKeys := []string{"platform_channel_id", "telex_channel_id", "channel_id"}
-
-	for _, key := range channelIDKeys {
-		if channelID, ok := metadata[key].(string); ok && channelID != "" {
-			return channelID, nil
-		}
+	if history := historyFromData(parts, stripHTML); len(history) > 0 {
+		return history[len(history)-1].Parts[0].Text
 	}
 
-	return "", nil
+	return ""
 }
 
-func (p *PlatformImpl) ValidateRequest(req *a2a.A2ARequest) error {
-	if req.Method != "message/send" {
-		return errors.New("method not supported")
+// ExtractHistory returns the earlier messages of the channel, without the copy of the current one.
+// Telex does not say who wrote them, so they are not told apart from Eunoia's own replies.
+func (p *TelexPlatform) ExtractHistory(parts []a2a.A2APart, currentMessageID string) []a2a.A2AMessageResult {
+	history := historyFromData(parts, stripHTML)
+	if len(history) == 0 {
+		return history
 	}
-	return nil
-}
-
-func (p *PlatformImpl) BuildResponse(requestId, messageID string, history []a2a.A2AMessageResult, response *a2a.ChatResponse) *a2a.A2AResponse {
-	taskID := id.Generate()
-	timestamp := time.Now().UTC().Format(time.RFC3339)
 
-	// Create the new agent response message (using messageID from request)
-	newMessage := a2a.A2AMessageResult{
-		MessageID: messageID,
-		Role:      "agent",
-		Parts: []a2a.A2APart{
-			{
-				Kind: "text",
-				Text: response.Response,
-			},
-		},
-		Kind:   "message",
-		TaskID: taskID,
-		Metadata: map[string]interface{}{
-			"agent": "eunoia",
-		},
+	if history[len(history)-1].Parts[0].Text == p.ExtractMessage(parts) {
+		history = history[:len(history)-1]
 	}
 
-	// Append the new agent response to history
-	updatedHistory := append(history, newMessage)
-
-	// Create artifact with the agent's response
-	artifacts := []a2a.A2AArtifact{
-		{
-			ArtifactID: id.Generate(),
-			Name:       "eunoia_response",
-			Parts: []a2a.A2APart{
-				{
-					Kind: "text",
-					Text: response.Response,
-				},
-			},
-		},
-	}
-
-	return &a2a.A2AResponse{
-		JSONRPC: "2.0",
-		ID:      requestId,
-		Result: a2a.A2AResult{
-			ID:        taskID,
-			ContextID: id.Generate(),
-			Status: a2a.A2ATaskStatus{
-				State:     "completed",
-				Timestamp: timestamp,
-				Message:   newMessage,
-			},
-			Artifacts: artifacts,
-			History:   updatedHistory,
-			Kind:      "task",
-		},
-	}
+	return history
 }
@@ -0,0 +1,110 @@
+package platforms
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
+func loadTelexFixture(t *testing.T, name string) a2a.MessageSendParams {
+	t.Helper()
+
+	raw, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	var req struct {
+		Params a2a.MessageSendParams `json:"params"`
+	}
+	if err := json.Unmarshal(raw, &req); err != nil {
+		t.Fatalf("failed to decode fixture: %v", err)
+	}
+	return req.Params
+}
+
+type historyItem struct {
+	role, text string
+}
+
+func checkHistory(t *testing.T, history []a2a.A2AMessageResult, want []historyItem) {
+	t.Helper()
+
+	if len(history) != len(want) {
+		t.Fatalf("expected %d history items, got %d: %+v", len(want), len(history), history)
+	}
+	for i, item := range want {
+		got := history[i]
+		if got.MessageID == "" {
+			t.Errorf("item %d: expected a message ID", i)
+		}
+		if got.Role != item.role {
+			t.Errorf("item %d: expected role %s, got %s", i, item.role, got.Role)
+		}
+		if got.Parts[0].Text != item.text {
+			t.Errorf("item %d: expected text %q, got %q", i, item.text, got.Parts[0].Text)
+		}
+	}
+}
+
+func TestTelexPlatform_MessageSend(t *testing.T) {
+	params := loadTelexFixture(t, "telex_message_send.json")
+	platform := NewTelexPlatform()
+
+	if got := platform.ExtractMessage(params.Message.Parts); got != "I can't stop worrying about tomorrow's review" {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	userID, err := platform.ExtractUserID(params.Message.Metadata)
+	if err != nil || userID != "01990f5e-8c42-7b3a-9d1e-2f4a6b8c0d1e" {
+		t.Errorf("unexpected user ID %q (%v)", userID, err)
+	}
+
+	// the trailing copy of the current message is dropped; Telex sends no authors, so every item keeps the user role
+	checkHistory(t, platform.ExtractHistory(params.Message.Parts, params.Message.MessageID), []historyItem{
+		{role: "user", text: "hey eunoia"},
+		{role: "user", text: "work has been a lot this week\nmy manager moved the deadline & I'm behind"},
+		{role: "user", text: "steps so far:\n1. made a list\n2. told my team\nsleep more\neat properly"},
+	})
+}
+
+func TestTelexPlatform_DataOnly(t *testing.T) {
+	params := loadTelexFixture(t, "telex_data_only.json")
+	platform := NewTelexPlatform()
+
+	if got := platform.ExtractMessage(params.Message.Parts); got != "honestly pretty tired" {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	checkHistory(t, platform.ExtractHistory(params.Message.Parts, params.Message.MessageID), []historyItem{
+		// Eunoia's own greeting, which Telex does not mark as such
+		{role: "user", text: "Good morning! How are you feeling today?"},
+	})
+}
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "  hello  ", "hello"},
+		{"paragraphs", "<p>one</p><p>two</p>", "one\ntwo"},
+		{"entities", "<p>salt &amp; pepper &lt;3</p>", "salt & pepper <3"},
+		{"inline", "<p>a <b>bold</b> <a href=\"x\">link</a></p>", "a bold link"},
+		{"line breaks", "one<br>two<br/>three", "one\ntwo\nthree"},
+		{"scripts", "<p>hi</p><script>alert(1)</script><style>p{}</style>", "hi"},
+		{"unclosed", "<div>open <span>tags", "open tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripHTML(tt.in); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -2,9 +2,13 @@ package conversation
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 )
 
+// ErrMessageNotFound is returned for a conversation history ID that does not belong to the user
+var ErrMessageNotFound = errors.New("message not found")
+
 type Repository struct {
 	db *sql.DB
 }
@@ -14,11 +18,12 @@ func NewRepository(db *sql.DB) *Repository {
 }
 
 func (r *Repository) SaveMessage(message *ConversationMessage) error {
-	query := `INSERT INTO conversation_history (id, user_id, message_role, message_content, context_data, created_at)
-			  VALUES (?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO conversation_history (id, user_id, session_id, message_role, message_content, context_data, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
 
-	_, err := r.db.Exec(query, message.ID, message.UserID, message.MessageRole,
-		message.MessageContent, message.ContextData, message.CreatedAt)
+	_, err := r.db.Exec(query, message.ID, message.UserID,
+		sql.NullString{String: message.SessionID, Valid: message.SessionID != ""},
+		message.MessageRole, message.MessageContent, message.ContextData, message.CreatedAt)
 
 	if err != nil {
 		return err
@@ -89,3 +94,328 @@ func (r *Repository) GetRecentMessages(userID string, minutes int) ([]*Conversat
 
 	return messages, nil
 }
+
+// SearchMessages runs a full-text search over the user's conversation history, ranked by relevance.
+// An empty role matches messages from any role; zero from/to values leave that side of the date range open.
+func (r *Repository) SearchMessages(userID, searchQuery, role string, from, to time.Time, limit int) ([]*MessageSearchResult, error) {
+	query := `SELECT id, user_id, message_role, message_content, context_data, created_at,
+			  MATCH(message_content) AGAINST (? IN NATURAL LANGUAGE MODE) AS score
+			  FROM conversation_history
+			  WHERE user_id = ? AND MATCH(message_content) AGAINST (? IN NATURAL LANGUAGE MODE)`
+	args := []interface{}{searchQuery, userID, searchQuery}
+
+	if role != "" {
+		query += ` AND message_role = ?`
+		args = append(args, role)
+	}
+	if !from.IsZero() {
+		query += ` AND created_at >= ?`
+		args = append(args, from)
+	}
+	if !to.IsZero() {
+		query += ` AND created_at < ?`
+		args = append(args, to)
+	}
+
+	query += ` ORDER BY score DESC, created_at DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*MessageSearchResult
+	for rows.Next() {
+		message := &ConversationMessage{}
+		result := &MessageSearchResult{Message: message}
+		var contextData sql.NullString
+		err := rows.Scan(&message.ID, &message.UserID, &message.MessageRole,
+			&message.MessageContent, &contextData, &message.CreatedAt, &result.Score)
+		if err != nil {
+			return nil, err
+		}
+		if contextData.Valid {
+			message.ContextData = contextData.String
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// GetMessagesAfter returns the user's messages stored after the given sequence number, oldest first
+func (r *Repository) GetMessagesAfter(userID string, afterSeq int64, limit int) ([]*ConversationMessage, error) {
+	query := `SELECT id, user_id, message_role, message_content, context_data, created_at, seq
+			  FROM conversation_history
+			  WHERE user_id = ? AND seq > ?
+			  ORDER BY seq ASC
+			  LIMIT ?`
+
+	rows, err := r.db.Query(query, userID, afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*ConversationMessage
+	for rows.Next() {
+		message := &ConversationMessage{}
+		var contextData sql.NullString
+		err := rows.Scan(&message.ID, &message.UserID, &message.MessageRole,
+			&message.MessageContent, &contextData, &message.CreatedAt, &message.Seq)
+		if err != nil {
+			return nil, err
+		}
+		if contextData.Valid {
+			message.ContextData = contextData.String
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// GetMessagesAfterID returns the user's messages stored after the given one, oldest first. Paging on seq
+// rather than created_at keeps messages saved in the same second as the anchor from being skipped.
+func (r *Repository) GetMessagesAfterID(userID, messageID string, limit int) ([]*ConversationMessage, error) {
+	var afterSeq int64
+	err := r.db.QueryRow(`SELECT seq FROM conversation_history WHERE id = ? AND user_id = ?`,
+		messageID, userID).Scan(&afterSeq)
+	if err == sql.ErrNoRows {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetMessagesAfter(userID, afterSeq, limit)
+}
+
+// GetSessionMessages returns the latest messages of a session, oldest first
+func (r *Repository) GetSessionMessages(sessionID string, limit int) ([]*ConversationMessage, error) {
+	query := `SELECT id, user_id, session_id, message_role, message_content, context_data, created_at
+			  FROM (
+				  SELECT id, user_id, session_id, message_role, message_content, context_data, created_at
+				  FROM conversation_history
+				  WHERE session_id = ?
+				  ORDER BY created_at DESC, id DESC
+				  LIMIT ?
+			  ) latest
+			  ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(query, sessionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*ConversationMessage
+	for rows.Next() {
+		message := &ConversationMessage{}
+		var sessionIDValue, contextData sql.NullString
+		err := rows.Scan(&message.ID, &message.UserID, &sessionIDValue, &message.MessageRole,
+			&message.MessageContent, &contextData, &message.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		message.SessionID = sessionIDValue.String
+		message.ContextData = contextData.String
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (r *Repository) CreateSession(session *ConversationSession) error {
+	query := `INSERT INTO conversation_sessions (id, user_id, channel_id, context_id, created_at, last_active_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, session.ID, session.UserID, session.ChannelID, session.ContextID,
+		session.CreatedAt, session.LastActiveAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetSessionByContextID returns nil when no session uses the context ID
+func (r *Repository) GetSessionByContextID(contextID string) (*ConversationSession, error) {
+	query := `SELECT id, user_id, channel_id, context_id, created_at, last_active_at
+			  FROM conversation_sessions
+			  WHERE context_id = ?`
+
+	return r.scanSession(r.db.QueryRow(query, contextID))
+}
+
+// GetLatestSession returns the user's most recently active session in a channel, or nil if there is none
+func (r *Repository) GetLatestSession(userID, channelID string) (*ConversationSession, error) {
+	query := `SELECT id, user_id, channel_id, context_id, created_at, last_active_at
+			  FROM conversation_sessions
+			  WHERE user_id = ? AND channel_id = ?
+			  ORDER BY last_active_at DESC
+			  LIMIT 1`
+
+	return r.scanSession(r.db.QueryRow(query, userID, channelID))
+}
+
+func (r *Repository) TouchSession(sessionID string, lastActiveAt time.Time) error {
+	_, err := r.db.Exec(`UPDATE conversation_sessions SET last_active_at = ? WHERE id = ?`, lastActiveAt, sessionID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) scanSession(row *sql.Row) (*ConversationSession, error) {
+	session := &ConversationSession{}
+	err := row.Scan(&session.ID, &session.UserID, &session.ChannelID, &session.ContextID,
+		&session.CreatedAt, &session.LastActiveAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *Repository) SaveSummary(summary *ConversationSummary) error {
+	query := `INSERT INTO conversation_summaries (id, user_id, summary, first_message_id, last_message_id,
+			  first_message_at, last_message_at, last_message_seq, message_count, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, summary.ID, summary.UserID, summary.Summary, summary.FirstMessageID,
+		summary.LastMessageID, summary.FirstMessageAt, summary.LastMessageAt, summary.LastMessageSeq,
+		summary.MessageCount, summary.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetLatestSummary returns the most recent summary for the user, or nil if none exists
+func (r *Repository) GetLatestSummary(userID string) (*ConversationSummary, error) {
+	query := `SELECT id, user_id, summary, first_message_id, last_message_id,
+			  first_message_at, last_message_at, last_message_seq, message_count, created_at
+			  FROM conversation_summaries
+			  WHERE user_id = ?
+			  ORDER BY last_message_seq DESC, created_at DESC
+			  LIMIT 1`
+
+	summary := &ConversationSummary{}
+	err := r.db.QueryRow(query, userID).Scan(
+		&summary.ID, &summary.UserID, &summary.Summary, &summary.FirstMessageID, &summary.LastMessageID,
+		&summary.FirstMessageAt, &summary.LastMessageAt, &summary.LastMessageSeq, &summary.MessageCount,
+		&summary.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func (r *Repository) CreateToolInvocation(invocation *ToolInvocation) error {
+	query := `INSERT INTO tool_invocations (id, user_id, message_id, tool_name, arguments, summary, status, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, invocation.ID, invocation.UserID,
+		sql.NullString{String: invocation.MessageID, Valid: invocation.MessageID != ""},
+		invocation.ToolName, invocation.Arguments, invocation.Summary, invocation.Status, invocation.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetPendingToolInvocation returns the user's latest pending invocation created after since, or nil
+func (r *Repository) GetPendingToolInvocation(userID string, since time.Time) (*ToolInvocation, error) {
+	query := `SELECT id, user_id, COALESCE(message_id, ''), tool_name, arguments, summary, status, created_at
+			  FROM tool_invocations
+			  WHERE user_id = ? AND status = ? AND created_at >= ?
+			  ORDER BY created_at DESC
+			  LIMIT 1`
+
+	invocation := &ToolInvocation{}
+	err := r.db.QueryRow(query, userID, InvocationPending, since).Scan(
+		&invocation.ID, &invocation.UserID, &invocation.MessageID, &invocation.ToolName,
+		&invocation.Arguments, &invocation.Summary, &invocation.Status, &invocation.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invocation, nil
+}
+
+// ClaimToolInvocation moves a pending invocation to executing, reporting false when it is no longer
+// pending, such as when a repeated confirmation already claimed it
+func (r *Repository) ClaimToolInvocation(invocationID string) (bool, error) {
+	query := `UPDATE tool_invocations SET status = ? WHERE id = ? AND status = ?`
+
+	result, err := r.db.Exec(query, InvocationExecuting, invocationID, InvocationPending)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// ResolveToolInvocation moves an invocation from the from status to its final one, reporting false
+// when it had already left the from status
+func (r *Repository) ResolveToolInvocation(invocationID, from, status, result, errorMessage string, resolvedAt time.Time) (bool, error) {
+	query := `UPDATE tool_invocations
+			  SET status = ?, result = ?, error_message = ?, resolved_at = ?
+			  WHERE id = ? AND status = ?`
+
+	res, err := r.db.Exec(query, status,
+		sql.NullString{String: result, Valid: result != ""},
+		sql.NullString{String: errorMessage, Valid: errorMessage != ""},
+		resolvedAt, invocationID, from)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// ExpirePendingToolInvocations closes every pending invocation for the user
+func (r *Repository) ExpirePendingToolInvocations(userID string, resolvedAt time.Time) error {
+	query := `UPDATE tool_invocations SET status = ?, resolved_at = ? WHERE user_id = ? AND status = ?`
+
+	_, err := r.db.Exec(query, InvocationExpired, resolvedAt, userID, InvocationPending)
+
+	return err
+}
@@ -0,0 +1,211 @@
+package conversation
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSaveSummary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	now := time.Now()
+	summary := &ConversationSummary{
+		ID:             "summary-123",
+		UserID:         "user-456",
+		Summary:        "The user has been stressed about their internship.",
+		FirstMessageID: "msg-1",
+		LastMessageID:  "msg-20",
+		FirstMessageAt: now.Add(-48 * time.Hour),
+		LastMessageAt:  now.Add(-24 * time.Hour),
+		LastMessageSeq: 20,
+		MessageCount:   20,
+		CreatedAt:      now,
+	}
+
+	mock.ExpectExec("INSERT INTO conversation_summaries").
+		WithArgs(summary.ID, summary.UserID, summary.Summary, summary.FirstMessageID, summary.LastMessageID,
+			summary.FirstMessageAt, summary.LastMessageAt, summary.LastMessageSeq, summary.MessageCount, summary.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.SaveSummary(summary)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetLatestSummary_Found(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	userID := "user-456"
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "summary", "first_message_id", "last_message_id",
+		"first_message_at", "last_message_at", "last_message_seq", "message_count", "created_at"}).
+		AddRow("summary-1", userID, "The user started a new job.", "msg-1", "msg-40", now, now, 140, 40, now)
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_summaries WHERE user_id = \\?").
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	summary, err := repo.GetLatestSummary(userID)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if summary == nil {
+		t.Fatal("expected summary, got nil")
+	}
+
+	if summary.MessageCount != 40 || summary.LastMessageSeq != 140 {
+		t.Errorf("expected message count 40 up to seq 140, got %+v", summary)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetLatestSummary_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_summaries").
+		WithArgs("user-456").
+		WillReturnError(sql.ErrNoRows)
+
+	summary, err := repo.GetLatestSummary("user-456")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if summary != nil {
+		t.Errorf("expected nil summary, got %v", summary)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetMessagesAfter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	userID := "user-456"
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "message_role", "message_content", "context_data", "created_at", "seq"}).
+		AddRow("msg-1", userID, "user", "Work was hard today", nil, now, 41).
+		AddRow("msg-2", userID, "assistant", "That sounds draining.", "context", now, 42)
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_history WHERE user_id = \\? AND seq > \\? ORDER BY seq ASC").
+		WithArgs(userID, int64(40), 110).
+		WillReturnRows(rows)
+
+	messages, err := repo.GetMessagesAfter(userID, 40, 110)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(messages))
+	}
+
+	if messages[1].ContextData != "context" || messages[1].Seq != 42 {
+		t.Errorf("unexpected message %+v", messages[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetMessagesAfterID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	userID := "user-456"
+	saved := time.Now().Truncate(time.Second)
+
+	mock.ExpectQuery("SELECT seq FROM conversation_history WHERE id = \\? AND user_id = \\?").
+		WithArgs("f0e1d2c3", userID).
+		WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(41))
+
+	// the reply was saved in the same second as the anchor and has a lower ID, so only seq orders it after
+	rows := sqlmock.NewRows([]string{"id", "user_id", "message_role", "message_content", "context_data", "created_at", "seq"}).
+		AddRow("0a1b2c3d", userID, "assistant", "I'm here.", nil, saved, 42)
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_history WHERE user_id = \\? AND seq > \\? ORDER BY seq ASC").
+		WithArgs(userID, int64(41), 50).
+		WillReturnRows(rows)
+
+	messages, err := repo.GetMessagesAfterID(userID, "f0e1d2c3", 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(messages) != 1 || messages[0].ID != "0a1b2c3d" || messages[0].Seq != 42 {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetMessagesAfterID_UnknownMessage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	mock.ExpectQuery("SELECT seq FROM conversation_history").
+		WithArgs("someone-elses-msg", "user-456").
+		WillReturnError(sql.ErrNoRows)
+
+	if _, err := repo.GetMessagesAfterID("user-456", "someone-elses-msg", 50); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("expected ErrMessageNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package conversation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	snippetRadius      = 80
+)
+
+var searchIntentPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^/search\s+(.+)$`),
+	regexp.MustCompile(`what (?:did|have) i (?:write|written|say|said|share|shared|mention|mentioned|journal|journaled|note|noted) about (.+)`),
+	regexp.MustCompile(`when did i (?:write|talk|say|mention|journal) about (.+)`),
+	regexp.MustCompile(`(?:find|search|look up|show me)(?: for)? (?:my )?(?:entries|reflections|messages|notes|journal entries) (?:about|on|mentioning) (.+)`),
+	regexp.MustCompile(`^search(?: for)? (.+)$`),
+}
+
+var searchTimePhrases = []string{
+	"last month", "this month", "last week", "this week", "yesterday", "today", "last year", "this year",
+}
+
+var searchQueryFillers = []string{"my ", "the ", "a ", "an "}
+
+// Search runs a full-text search across the user's reflections and messages
+func (s *Service) Search(req *SearchRequest) ([]*SearchResult, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+
+	userRecord, err := s.userRepo.GetUserByPlatformID(req.PlatformUserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	return s.searchUserEntries(userRecord.ID, query, req.From, req.To, limit)
+}
+
+func (s *Service) searchUserEntries(userID, query string, from, to time.Time, limit int) ([]*SearchResult, error) {
+	var results []*SearchResult
+
+	reflections, err := s.reflectionRepo.SearchReflections(userID, query, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search reflections: %w", err)
+	}
+	for _, r := range reflections {
+		results = append(results, &SearchResult{
+			Source:    "reflection",
+			ID:        r.Reflection.ID,
+			Snippet:   buildSnippet(r.Reflection.Content, query),
+			Score:     r.Score,
+			CreatedAt: r.Reflection.CreatedAt,
+		})
+	}
+
+	messages, err := s.repo.SearchMessages(userID, query, "user", from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search conversation history: %w", err)
+	}
+	for _, m := range messages {
+		results = append(results, &SearchResult{
+			Source:    "conversation",
+			ID:        m.Message.ID,
+			Role:      m.Message.MessageRole,
+			Snippet:   buildSnippet(m.Message.MessageContent, query),
+			Score:     m.Score,
+			CreatedAt: m.Message.CreatedAt,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].CreatedAt.After(results[j].CreatedAt)
+		}
+		return results[i].Score > results[j].Score
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// buildSearchContext answers a conversational search request with matching past entries for the prompt
+func (s *Service) buildSearchContext(userID, message string) string {
+	query, from, to, ok := detectSearchIntent(message, time.Now())
+	if !ok {
+		return ""
+	}
+
+	results, err := s.searchUserEntries(userID, query, from, to, 5)
+	if err != nil {
+		logger.Warn("failed to search past entries", logger.Merge(
+			logger.WithError(err),
+			logger.WithUserID(userID),
+		))
+		return ""
+	}
+
+	logger.Info("answered conversational search", logger.Fields{
+		"user_id": userID,
+		"results": len(results),
+	})
+
+	return formatSearchContext(query, results)
+}
+
+func formatSearchContext(query string, results []*SearchResult) string {
+	if len(results) == 0 {
+		return fmt.Sprintf("The user asked about past entries on %q. No matching reflections or messages were found - say so honestly.", query)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("The user asked about past entries on %q. Matching entries (most relevant first):\n", query))
+	for _, r := range results {
+		b.WriteString(fmt.Sprintf("- [%s, %s] %s\n", r.CreatedAt.Format("Jan 2, 2006"), r.Source, r.Snippet))
+	}
+	b.WriteString("Answer using only these entries.")
+
+	return b.String()
+}
+
+// detectSearchIntent recognises questions about past entries and returns the search terms and date range
+func detectSearchIntent(message string, now time.Time) (string, time.Time, time.Time, bool) {
+	messageLower := strings.ToLower(strings.TrimSpace(message))
+
+	var query string
+	for _, pattern := range searchIntentPatterns {
+		if match := pattern.FindStringSubmatch(messageLower); match != nil {
+			query = match[1]
+			break
+		}
+	}
+
+	if query == "" {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	var from, to time.Time
+	for _, phrase := range searchTimePhrases {
+		if strings.Contains(query, phrase) {
+			from, to = searchDateRange(phrase, now)
+			query = strings.ReplaceAll(query, phrase, "")
+			break
+		}
+	}
+
+	query = strings.Trim(strings.TrimSpace(query), "?.!,")
+	for _, filler := range searchQueryFillers {
+		query = strings.TrimPrefix(query, filler)
+	}
+	query = strings.TrimSpace(query)
+
+	if query == "" {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	return query, from, to, true
+}
+
+func searchDateRange(phrase string, now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	yearStart := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+
+	switch phrase {
+	case "today":
+		return today, time.Time{}
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today
+	case "this week":
+		return weekStart, time.Time{}
+	case "last week":
+		return weekStart.AddDate(0, 0, -7), weekStart
+	case "this month":
+		return monthStart, time.Time{}
+	case "last month":
+		return monthStart.AddDate(0, -1, 0), monthStart
+	case "this year":
+		return yearStart, time.Time{}
+	case "last year":
+		return yearStart.AddDate(-1, 0, 0), yearStart
+	}
+
+	return time.Time{}, time.Time{}
+}
+
+// buildSnippet returns a short excerpt of content centred on the first matching query term
+func buildSnippet(content, query string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	contentLower := strings.ToLower(content)
+
+	matchIndex := -1
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if len(term) < 3 {
+			continue
+		}
+		if idx := strings.Index(contentLower, term); idx >= 0 && (matchIndex < 0 || idx < matchIndex) {
+			matchIndex = idx
+		}
+	}
+
+	// lowercasing can change byte offsets for some scripts
+	if matchIndex < 0 || len(contentLower) != len(content) {
+		matchIndex = 0
+	}
+
+	start := matchIndex - snippetRadius
+	if start < 0 {
+		start = 0
+	}
+	end := matchIndex + snippetRadius
+	if end > len(content) {
+		end = len(content)
+	}
+
+	// avoid cutting through multi-byte characters
+	for start > 0 && !isRuneStart(content[start]) {
+		start--
+	}
+	for end < len(content) && !isRuneStart(content[end]) {
+		end++
+	}
+
+	snippet := strings.TrimSpace(content[start:end])
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(content) {
+		snippet = snippet + "..."
+	}
+
+	return snippet
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
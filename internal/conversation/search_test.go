@@ -0,0 +1,91 @@
+package conversation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectSearchIntent(t *testing.T) {
+	now := time.Date(2025, time.November, 12, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		message       string
+		expectedQuery string
+		expectedFrom  time.Time
+		expectedTo    time.Time
+	}{
+		{
+			"What did I write about my sister last month?",
+			"sister",
+			time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{"/search job interview", "job interview", time.Time{}, time.Time{}},
+		{
+			"when did I talk about the move yesterday",
+			"move",
+			time.Date(2025, time.November, 11, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, time.November, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{"find my reflections about sleep", "sleep", time.Time{}, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			query, from, to, ok := detectSearchIntent(tt.message, now)
+			if !ok {
+				t.Fatal("expected search intent to be detected")
+			}
+
+			if query != tt.expectedQuery {
+				t.Errorf("expected query '%s', got '%s'", tt.expectedQuery, query)
+			}
+
+			if !from.Equal(tt.expectedFrom) {
+				t.Errorf("expected from %v, got %v", tt.expectedFrom, from)
+			}
+
+			if !to.Equal(tt.expectedTo) {
+				t.Errorf("expected to %v, got %v", tt.expectedTo, to)
+			}
+		})
+	}
+}
+
+func TestDetectSearchIntent_NoIntent(t *testing.T) {
+	messages := []string{
+		"I'm feeling great today",
+		"I find it hard to talk about my sister",
+		"Today I realized I need more rest",
+	}
+
+	for _, msg := range messages {
+		t.Run(msg, func(t *testing.T) {
+			if _, _, _, ok := detectSearchIntent(msg, time.Now()); ok {
+				t.Errorf("expected no search intent for '%s'", msg)
+			}
+		})
+	}
+}
+
+func TestBuildSnippet(t *testing.T) {
+	content := strings.Repeat("Work was busy and I felt drained. ", 10) +
+		"In the evening I called my sister and we laughed for an hour. " +
+		strings.Repeat("Then I went to bed early. ", 10)
+
+	snippet := buildSnippet(content, "sister")
+
+	if !strings.Contains(snippet, "sister") {
+		t.Errorf("expected snippet to contain the match, got '%s'", snippet)
+	}
+
+	if !strings.HasPrefix(snippet, "...") || !strings.HasSuffix(snippet, "...") {
+		t.Errorf("expected snippet to be elided on both sides, got '%s'", snippet)
+	}
+
+	short := buildSnippet("Called my sister", "sister")
+	if short != "Called my sister" {
+		t.Errorf("expected short content unchanged, got '%s'", short)
+	}
+}
@@ -1,18 +1,34 @@
 package conversation
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/zjoart/eunoia/internal/agent"
 	"github.com/zjoart/eunoia/internal/checkin"
+	"github.com/zjoart/eunoia/internal/delegation"
+	"github.com/zjoart/eunoia/internal/facts"
+	"github.com/zjoart/eunoia/internal/memory"
 	"github.com/zjoart/eunoia/internal/reflection"
+	"github.com/zjoart/eunoia/internal/reminder"
+	"github.com/zjoart/eunoia/internal/usage"
 	"github.com/zjoart/eunoia/internal/user"
 	"github.com/zjoart/eunoia/pkg/id"
 	"github.com/zjoart/eunoia/pkg/logger"
 )
 
+const quotaExceededReply = "I've reached my limit for conversations today, so I can't reply properly right now. " +
+	"Your message is saved - let's pick this up tomorrow. If you're struggling right now, please reach out to someone you trust or a local support line."
+
+const (
+	// history window used when a message cannot be tied to a session
+	recentHistoryMinutes = 30
+	memoryRecallLimit    = 3
+)
+
 type Service struct {
 	repo              *Repository
 	userRepo          *user.Repository
@@ -21,6 +37,18 @@ type Service struct {
 	checkInService    *checkin.Service
 	reflectionService *reflection.Service
 	geminiService     *agent.GeminiService
+	memoryService     *memory.Service
+	factService       *facts.Service
+	promptBudget      *agent.PromptBudget
+	usageService      *usage.Service
+	reminderService   *reminder.Service
+	delegationService *delegation.Service
+	tools             *agent.ToolRegistry
+	// reminderPlatforms can deliver reminders; schedule_reminder is only offered to their users
+	reminderPlatforms map[string]bool
+	summarizing       sync.Map
+	// writeSummary condenses messages into a summary, generateSummary outside of tests
+	writeSummary func(userID string, previous *ConversationSummary, messages []*ConversationMessage) (string, error)
 }
 
 func NewService(
@@ -29,11 +57,17 @@ func NewService(
 	checkInRepo *checkin.Repository,
 	reflectionRepo *reflection.Repository,
 	geminiService *agent.GeminiService,
+	memoryService *memory.Service,
+	factService *facts.Service,
+	promptBudget *agent.PromptBudget,
+	usageService *usage.Service,
+	reminderService *reminder.Service,
+	delegationService *delegation.Service,
 ) *Service {
 	checkInService := checkin.NewService(checkInRepo, userRepo)
 	reflectionService := reflection.NewService(reflectionRepo, userRepo, geminiService)
 
-	return &Service{
+	s := &Service{
 		repo:              repo,
 		userRepo:          userRepo,
 		checkInRepo:       checkInRepo,
@@ -41,12 +75,52 @@ func NewService(
 		checkInService:    checkInService,
 		reflectionService: reflectionService,
 		geminiService:     geminiService,
+		memoryService:     memoryService,
+		factService:       factService,
+		promptBudget:      promptBudget,
+		usageService:      usageService,
+		reminderService:   reminderService,
+		delegationService: delegationService,
+	}
+	s.tools = s.newToolRegistry()
+	s.writeSummary = s.generateSummary
+
+	return s
+}
+
+// SetReminderPlatforms names the platforms whose users receive due reminders
+func (s *Service) SetReminderPlatforms(platforms ...string) {
+	s.reminderPlatforms = make(map[string]bool, len(platforms))
+	for _, platform := range platforms {
+		s.reminderPlatforms[platform] = true
+	}
+}
+
+// toolsFor leaves out the tools the user's platform cannot follow through on
+func (s *Service) toolsFor(platformUserID string) *agent.ToolRegistry {
+	platform, _, _ := strings.Cut(platformUserID, ":")
+	if s.tools == nil || s.reminderPlatforms[platform] {
+		return s.tools
 	}
+	return s.tools.Without("schedule_reminder")
 }
 
 func (s *Service) ProcessMessage(req *ChatRequest) (*ChatResponse, error) {
+	dataCheckInReq := dataCheckIn(req.PlatformUserID, strings.TrimSpace(req.Message), req.Data)
+	insights := s.newInsights(req.Message)
+	// a check-in without any text is answered directly instead of by the model
+	checkInOnly := false
+
 	if strings.TrimSpace(req.Message) == "" {
-		return nil, fmt.Errorf("message cannot be empty")
+		switch {
+		case dataCheckInReq != nil:
+			req.Message = fmt.Sprintf("(checked in with a mood of %d/10, %s)", dataCheckInReq.MoodScore, dataCheckInReq.MoodLabel)
+			checkInOnly = true
+		case len(req.Attachments) > 0:
+			req.Message = attachmentOnlyMessage
+		default:
+			return nil, fmt.Errorf("message cannot be empty")
+		}
 	}
 
 	userRecord, err := s.userRepo.GetOrCreateUser(req.PlatformUserID)
@@ -55,6 +129,21 @@ func (s *Service) ProcessMessage(req *ChatRequest) (*ChatResponse, error) {
 		return nil, fmt.Errorf("failed to process user: %w", err)
 	}
 
+	session, err := s.resolveSession(userRecord.ID, req.ChannelID, req.ContextID)
+	if err != nil {
+		logger.Warn("failed to resolve conversation session", logger.Merge(
+			logger.WithError(err),
+			logger.WithUserID(userRecord.ID),
+		))
+	}
+	contextID := req.ContextID
+	if session != nil {
+		contextID = session.ContextID
+	}
+
+	// search before saving so the question itself is not returned as a match
+	searchContext := s.buildSearchContext(userRecord.ID, req.Message)
+
 	userMessage := &ConversationMessage{
 		ID:             id.Generate(),
 		UserID:         userRecord.ID,
@@ -63,52 +152,134 @@ func (s *Service) ProcessMessage(req *ChatRequest) (*ChatResponse, error) {
 		MessageID:      req.MessageID,
 		CreatedAt:      time.Now(),
 	}
+	if session != nil {
+		userMessage.SessionID = session.ID
+	}
 
+	userMessageSaved := true
 	if err := s.repo.SaveMessage(userMessage); err != nil {
 		logger.Warn("failed to save user message", logger.WithError(err))
+		userMessageSaved = false
+	}
+
+	// a check-in sent as structured data was chosen explicitly by the user, so it needs no confirmation
+	var dataCheckInContext []string
+	if dataCheckInReq != nil {
+		reply, context := s.logDataCheckIn(userRecord.ID, dataCheckInReq, insights)
+		if checkInOnly {
+			return &ChatResponse{
+				Response:  reply,
+				ContextID: contextID,
+				HistoryID: s.saveAssistantMessage(userMessage, reply, ""),
+				Insights:  insights,
+			}, nil
+		}
+		dataCheckInContext = context
 	}
 
-	s.detectAndHandleIntents(req.PlatformUserID, req.Message)
+	// checked before anything else that may call the model, including confirmed actions and background work
+	if s.usageService != nil {
+		if err := s.usageService.CheckQuota(userRecord.ID); errors.Is(err, usage.ErrQuotaExceeded) {
+			return &ChatResponse{
+				Response:  quotaExceededReply,
+				ContextID: contextID,
+				HistoryID: s.saveAssistantMessage(userMessage, quotaExceededReply, ""),
+				Insights:  insights,
+			}, nil
+		}
+	}
 
-	context, err := s.buildUserContext(userRecord.ID)
-	if err != nil {
-		logger.Warn("failed to build user context", logger.WithError(err))
-		context = ""
+	factCtx := &agent.ToolContext{UserID: userRecord.ID, PlatformUserID: req.PlatformUserID, MessageID: req.MessageID, Message: req.Message}
+	if reply, handled := s.handleFactCommand(factCtx, req.Message); handled {
+		return &ChatResponse{
+			Response:      reply,
+			ContextID:     contextID,
+			HistoryID:     s.saveAssistantMessage(userMessage, reply, ""),
+			Insights:      insights,
+			InputRequired: factCtx.AwaitingConfirmation,
+		}, nil
+	}
+
+	if reply, result, handled := s.handleActionConfirmation(userRecord.ID, req.PlatformUserID, req.MessageID, req.Message); handled {
+		insights.addActionResult(result)
+		return &ChatResponse{
+			Response:  reply,
+			ContextID: contextID,
+			HistoryID: s.saveAssistantMessage(userMessage, reply, ""),
+			Insights:  insights,
+		}, nil
+	}
+
+	if userMessageSaved {
+		s.rememberAsync(userRecord.ID, memory.SourceMessage, userMessage.ID, userMessage.MessageContent, userMessage.CreatedAt)
+		s.extractFactsAsync(userRecord.ID, userMessage.ID, userMessage.MessageContent)
 	}
 
-	conversationHistory, err := s.repo.GetRecentMessages(userRecord.ID, 30)
+	images, otherAttachments := splitAttachments(req.Attachments)
+
+	contextParts := append(s.buildUserContext(userRecord.ID), s.actionHints(req.Message)...)
+	contextParts = append(contextParts, dataCheckInContext...)
+	contextParts = append(contextParts, attachmentContext(otherAttachments)...)
+
+	conversationHistory, err := s.loadHistory(userRecord.ID, session)
 	if err != nil {
 		logger.Warn("failed to get conversation history", logger.WithError(err))
 		conversationHistory = []*ConversationMessage{}
 	}
 
+	memories := s.recallMemories(userRecord.ID, req.Message, historyStart(conversationHistory))
+
 	geminiHistory := s.convertToGeminiHistory(conversationHistory)
 
-	systemPrompt := s.buildSystemPrompt(context)
+	prompt := s.preparePrompt(userRecord.ID, req.Message, contextParts, memories, searchContext, geminiHistory)
+
+	toolCtx := &agent.ToolContext{
+		UserID:         userRecord.ID,
+		PlatformUserID: req.PlatformUserID,
+		MessageID:      req.MessageID,
+		Message:        req.Message,
+	}
 
-	response, err := s.geminiService.GenerateContent(systemPrompt, req.Message, geminiHistory)
+	response, err := s.geminiService.For(agent.PurposeChat, userRecord.ID).
+		StreamWithTools(prompt.systemPrompt, prompt.message, prompt.history, images, s.toolsFor(req.PlatformUserID), toolCtx, req.OnToken)
 	if err != nil {
 		logger.Error("failed to generate response", logger.WithError(err))
 		return nil, fmt.Errorf("failed to generate response: %w", err)
 	}
 
+	historyID := s.saveAssistantMessage(userMessage, response, prompt.context)
+
+	s.maybeSummarizeAsync(userRecord.ID)
+
+	return &ChatResponse{
+		Response:      response,
+		InputRequired: toolCtx.AwaitingConfirmation,
+		ContextID:     contextID,
+		HistoryID:     historyID,
+		Insights:      insights,
+	}, nil
+}
+
+// saveAssistantMessage stores a reply in the same session as the message it answers, returning its
+// history ID, or an empty string when it could not be saved
+func (s *Service) saveAssistantMessage(userMessage *ConversationMessage, content, context string) string {
 	assistantMessage := &ConversationMessage{
 		ID:             id.Generate(),
-		UserID:         userRecord.ID,
+		UserID:         userMessage.UserID,
 		MessageRole:    "assistant",
-		MessageContent: response,
-		MessageID:      req.MessageID,
+		MessageContent: content,
+		MessageID:      userMessage.MessageID,
+		SessionID:      userMessage.SessionID,
 		ContextData:    context,
 		CreatedAt:      time.Now(),
 	}
 
 	if err := s.repo.SaveMessage(assistantMessage); err != nil {
 		logger.Warn("failed to save assistant message", logger.WithError(err))
+		return ""
 	}
 
-	return &ChatResponse{
-		Response: response,
-	}, nil
+	return assistantMessage.ID
 }
 
 func (s *Service) buildSystemPrompt(userContext string) string {
@@ -138,6 +309,10 @@ Important:
 
 You're a companion on their journey, not a script following a checklist.
 `
+	if s.tools != nil && s.tools.Len() > 0 {
+		prompt += "\n" + toolsPrompt + "\n" + currentTimePrompt(time.Now()) + "\n"
+	}
+
 	if userContext != "" {
 		prompt += "\n\nBackground context:\n" + userContext + "\n\nUse this to inform your responses, but stay focused on the current conversation."
 	}
@@ -145,9 +320,22 @@ You're a companion on their journey, not a script following a checklist.
 	return prompt
 }
 
-func (s *Service) buildUserContext(userID string) (string, error) {
+func (s *Service) buildUserContext(userID string) []string {
 	var contextParts []string
 
+	summary, err := s.repo.GetLatestSummary(userID)
+	if err == nil && summary != nil {
+		contextParts = append(contextParts, fmt.Sprintf("Summary of earlier conversations (up to %s):\n%s",
+			summary.LastMessageAt.Format("Jan 2, 2006"), summary.Summary))
+	}
+
+	if s.factService != nil {
+		known, err := s.factService.GetFactsForUser(userID)
+		if err == nil && len(known) > 0 {
+			contextParts = append(contextParts, "What the user has shared about themselves:\n"+formatFactList(known))
+		}
+	}
+
 	checkIns, err := s.checkInRepo.GetCheckInsByUserID(userID, 5)
 	if err == nil && len(checkIns) > 0 {
 		contextParts = append(contextParts, fmt.Sprintf("Recent check-ins: %d entries", len(checkIns)))
@@ -173,24 +361,70 @@ func (s *Service) buildUserContext(userID string) (string, error) {
 		}
 	}
 
-	if len(contextParts) == 0 {
-		return "New user - no previous history", nil
+	return contextParts
+}
+
+// recallMemories finds older messages and reflections that relate to the current message,
+// skipping anything from before that is already in the prompt as conversation history
+func (s *Service) recallMemories(userID, message string, before time.Time) []string {
+	if s.memoryService == nil {
+		return nil
+	}
+
+	memories, err := s.memoryService.Recall(userID, message, before, memoryRecallLimit)
+	if err != nil {
+		logger.Warn("failed to recall memories", logger.Merge(
+			logger.WithError(err),
+			logger.WithUserID(userID),
+		))
+		return nil
 	}
 
-	return strings.Join(contextParts, "\n"), nil
+	return formatMemories(memories)
 }
 
-func (s *Service) convertToGeminiHistory(messages []*ConversationMessage) []string {
-	var history []string
+func formatMemories(memories []*memory.RetrievedMemory) []string {
+	var lines []string
+	for _, m := range memories {
+		lines = append(lines, fmt.Sprintf("- (%s, %s) %s", m.Memory.CreatedAt.Format("Jan 2"), m.Memory.SourceType, m.Memory.Content))
+	}
 
+	return lines
+}
+
+// rememberAsync indexes content for semantic recall without delaying the reply
+func (s *Service) rememberAsync(userID, sourceType, sourceID, content string, createdAt time.Time) {
+	if s.memoryService == nil {
+		return
+	}
+
+	go func() {
+		if err := s.memoryService.Remember(userID, sourceType, sourceID, content, createdAt); err != nil {
+			logger.Warn("failed to store memory", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{
+					"source_type": sourceType,
+					"source_id":   sourceID,
+				},
+			))
+		}
+	}()
+}
+
+func (s *Service) convertToGeminiHistory(messages []*ConversationMessage) []string {
 	// get last 5 message pairs (10 messages total) for better context
 	startIndex := 0
-	if len(messages) > 10 {
-		startIndex = len(messages) - 10
+	if len(messages) > historyWindowSize {
+		startIndex = len(messages) - historyWindowSize
 	}
 
-	for i := startIndex; i < len(messages); i++ {
-		msg := messages[i]
+	return formatHistory(messages[startIndex:])
+}
+
+func formatHistory(messages []*ConversationMessage) []string {
+	var history []string
+
+	for _, msg := range messages {
 		// format with role prefix for clearer context
 		rolePrefix := "User"
 		if msg.MessageRole == "assistant" {
@@ -221,36 +455,15 @@ func (s *Service) GetConversationHistory(platformUserID string, limit int) ([]*C
 	return reversedMessages, nil
 }
 
-func (s *Service) detectAndHandleIntents(platformUserID, message string) {
-	messageLower := strings.ToLower(message)
-	messageLen := len(strings.Fields(message))
-
-	moodScore, moodLabel := s.detectMoodIntent(messageLower)
-	if moodScore > 0 {
-		checkInReq := &checkin.CreateCheckInRequest{
-			PlatformUserID: platformUserID,
-			MoodScore:      moodScore,
-			MoodLabel:      moodLabel,
-			Description:    message,
-		}
-		if _, err := s.checkInService.CreateCheckIn(checkInReq); err != nil {
-			logger.Warn("failed to auto-create check-in", logger.WithError(err))
-		} else {
-			logger.Info("auto-created check-in from conversation", logger.Fields{"mood": moodLabel})
-		}
+// GetMessagesSince returns a user's messages stored after lastMessageID, oldest first, so a client
+// can catch up after reconnecting; ErrMessageNotFound means the ID is not one of the user's messages
+func (s *Service) GetMessagesSince(platformUserID, lastMessageID string, limit int) ([]*ConversationMessage, error) {
+	userRecord, err := s.userRepo.GetUserByPlatformID(platformUserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	if messageLen >= 15 && s.isReflectionIntent(messageLower) {
-		reflectionReq := &reflection.CreateReflectionRequest{
-			PlatformUserID: platformUserID,
-			Content:        message,
-		}
-		if _, err := s.reflectionService.CreateReflection(reflectionReq); err != nil {
-			logger.Warn("failed to auto-create reflection", logger.WithError(err))
-		} else {
-			logger.Info("auto-created reflection from conversation")
-		}
-	}
+	return s.repo.GetMessagesAfterID(userRecord.ID, lastMessageID, limit)
 }
 
 func (s *Service) detectMoodIntent(messageLower string) (int, string) {
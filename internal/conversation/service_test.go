@@ -3,6 +3,8 @@ package conversation
 import (
 	"strings"
 	"testing"
+
+	"github.com/zjoart/eunoia/internal/agent"
 )
 
 func TestDetectMoodIntent_HappyMoods(t *testing.T) {
@@ -254,3 +256,46 @@ func TestBuildUserContext(t *testing.T) {
 
 	t.Skip("TODO: implement mocking for repos to test various context scenarios")
 }
+
+func TestPreparePrompt_TrimsToBudget(t *testing.T) {
+	service := &Service{
+		promptBudget: agent.NewPromptBudget(700, agent.CharTokenEstimator{CharsPerToken: 4}),
+	}
+
+	history := make([]string, 10)
+	for i := range history {
+		history[i] = "User: " + strings.Repeat("long message about work ", 10)
+	}
+	memories := []string{"- (Oct 2, reflection) " + strings.Repeat("an older memory ", 20)}
+
+	prompt := service.preparePrompt("user-1", "How do I handle this?", []string{"Latest mood: 4/10 (low)"}, memories, "", history)
+
+	if len(prompt.history) >= 10 {
+		t.Errorf("expected history to be trimmed, got %d items", len(prompt.history))
+	}
+
+	if strings.Contains(prompt.context, memoriesHeader) {
+		t.Error("expected memories to be trimmed before history")
+	}
+
+	if !strings.Contains(prompt.systemPrompt, "Latest mood: 4/10") {
+		t.Error("expected background context to be kept")
+	}
+}
+
+func TestPreparePrompt_TruncatesLongMessage(t *testing.T) {
+	service := &Service{
+		promptBudget: agent.NewPromptBudget(1000, agent.CharTokenEstimator{CharsPerToken: 4}),
+	}
+
+	message := strings.Repeat("pasted text ", 1000)
+	prompt := service.preparePrompt("user-1", message, nil, nil, "", nil)
+
+	if !strings.HasSuffix(prompt.message, "[message truncated]") {
+		t.Error("expected long message to be truncated")
+	}
+
+	if !strings.Contains(prompt.context, newUserContext) {
+		t.Errorf("expected new user context, got '%s'", prompt.context)
+	}
+}
@@ -0,0 +1,104 @@
+package conversation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/id"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// ResolveContextID returns the context a message will continue, creating its session when needed, so the
+// context is known before the message is processed
+func (s *Service) ResolveContextID(platformUserID, channelID, contextID string) (string, error) {
+	userRecord, err := s.userRepo.GetOrCreateUser(platformUserID)
+	if err != nil {
+		return "", fmt.Errorf("failed to process user: %w", err)
+	}
+
+	session, err := s.resolveSession(userRecord.ID, channelID, contextID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve conversation session: %w", err)
+	}
+
+	return session.ContextID, nil
+}
+
+// resolveSession finds the session a message belongs to, creating one when needed. Without a context ID
+// the user's latest session in the channel continues; a context ID owned by another user is never reused.
+func (s *Service) resolveSession(userID, channelID, contextID string) (*ConversationSession, error) {
+	var session *ConversationSession
+	var err error
+
+	if contextID != "" {
+		session, err = s.repo.GetSessionByContextID(contextID)
+		if err != nil {
+			return nil, err
+		}
+		if session != nil && session.UserID != userID {
+			logger.Warn("context id belongs to another user, starting a new session", logger.Merge(
+				logger.WithUserID(userID),
+				logger.Fields{"context_id": contextID},
+			))
+			session = nil
+			contextID = ""
+		}
+	} else {
+		session, err = s.repo.GetLatestSession(userID, channelID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+
+	if session == nil {
+		if contextID == "" {
+			contextID = id.Generate()
+		}
+
+		session = &ConversationSession{
+			ID:           id.Generate(),
+			UserID:       userID,
+			ChannelID:    channelID,
+			ContextID:    contextID,
+			CreatedAt:    now,
+			LastActiveAt: now,
+		}
+
+		if err := s.repo.CreateSession(session); err != nil {
+			return nil, err
+		}
+
+		return session, nil
+	}
+
+	if err := s.repo.TouchSession(session.ID, now); err != nil {
+		logger.Warn("failed to update session activity", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"session_id": session.ID},
+		))
+	}
+	session.LastActiveAt = now
+
+	return session, nil
+}
+
+// loadHistory returns the latest messages of the session, falling back to a recent time window
+// when the message has no session
+func (s *Service) loadHistory(userID string, session *ConversationSession) ([]*ConversationMessage, error) {
+	if session == nil {
+		return s.repo.GetRecentMessages(userID, recentHistoryMinutes)
+	}
+
+	return s.repo.GetSessionMessages(session.ID, historyWindowSize)
+}
+
+// historyStart returns when the oldest message in the prompt history was sent, so recall only looks before it
+func historyStart(history []*ConversationMessage) time.Time {
+	if len(history) == 0 {
+		return time.Now()
+	}
+
+	return history[0].CreatedAt
+}
@@ -0,0 +1,168 @@
+package conversation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var sessionColumns = []string{"id", "user_id", "channel_id", "context_id", "created_at", "last_active_at"}
+
+func TestResolveSession_ContinuesContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := &Service{repo: NewRepository(db)}
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_sessions WHERE context_id = \\?").
+		WithArgs("ctx-1").
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow("session-1", "user-1", "channel-1", "ctx-1", now.Add(-time.Hour), now.Add(-time.Hour)))
+	mock.ExpectExec("UPDATE conversation_sessions SET last_active_at").
+		WithArgs(sqlmock.AnyArg(), "session-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	session, err := service.resolveSession("user-1", "channel-1", "ctx-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.ID != "session-1" || session.ContextID != "ctx-1" {
+		t.Errorf("expected the existing session, got %+v", session)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestResolveSession_NewContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := &Service{repo: NewRepository(db)}
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_sessions WHERE context_id = \\?").
+		WithArgs("ctx-new").
+		WillReturnRows(sqlmock.NewRows(sessionColumns))
+	mock.ExpectExec("INSERT INTO conversation_sessions").
+		WithArgs(sqlmock.AnyArg(), "user-1", "channel-1", "ctx-new", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	session, err := service.resolveSession("user-1", "channel-1", "ctx-new")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.ContextID != "ctx-new" {
+		t.Errorf("expected the client's context ID to be kept, got %s", session.ContextID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestResolveSession_OtherUsersContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := &Service{repo: NewRepository(db)}
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_sessions WHERE context_id = \\?").
+		WithArgs("ctx-1").
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow("session-1", "user-2", "", "ctx-1", now, now))
+	mock.ExpectExec("INSERT INTO conversation_sessions").
+		WithArgs(sqlmock.AnyArg(), "user-1", "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	session, err := service.resolveSession("user-1", "", "ctx-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.ID == "session-1" || session.ContextID == "ctx-1" {
+		t.Errorf("expected a fresh session, got %+v", session)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestResolveSession_LatestInChannel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := &Service{repo: NewRepository(db)}
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_sessions WHERE user_id = \\? AND channel_id = \\?").
+		WithArgs("user-1", "channel-1").
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow("session-1", "user-1", "channel-1", "ctx-1", now, now))
+	mock.ExpectExec("UPDATE conversation_sessions SET last_active_at").
+		WithArgs(sqlmock.AnyArg(), "session-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	session, err := service.resolveSession("user-1", "channel-1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.ContextID != "ctx-1" {
+		t.Errorf("expected the channel's latest context, got %s", session.ContextID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetSessionMessages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "session_id", "message_role", "message_content", "context_data", "created_at"}).
+		AddRow("msg-1", "user-1", "session-1", "user", "hello", nil, now.Add(-time.Minute)).
+		AddRow("msg-2", "user-1", "session-1", "assistant", "hi there", "ctx", now)
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_history WHERE session_id = \\?").
+		WithArgs("session-1", historyWindowSize).
+		WillReturnRows(rows)
+
+	messages, err := repo.GetSessionMessages("session-1", historyWindowSize)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(messages) != 2 || messages[0].ID != "msg-1" || messages[1].SessionID != "session-1" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
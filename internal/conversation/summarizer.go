@@ -0,0 +1,131 @@
+package conversation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/pkg/id"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+const (
+	// number of most recent messages sent verbatim as conversation history
+	historyWindowSize = 10
+	// minimum number of messages outside the history window before summarizing
+	summarizeThreshold = 20
+	maxSummarizeBatch  = 100
+)
+
+// maybeSummarizeAsync condenses older messages into a new rolling summary without delaying the reply
+func (s *Service) maybeSummarizeAsync(userID string) {
+	if _, running := s.summarizing.LoadOrStore(userID, true); running {
+		return
+	}
+
+	go func() {
+		defer s.summarizing.Delete(userID)
+
+		if err := s.summarizeOlderMessages(userID); err != nil {
+			logger.Warn("failed to summarize conversation", logger.Merge(
+				logger.WithError(err),
+				logger.WithUserID(userID),
+			))
+		}
+	}()
+}
+
+func (s *Service) summarizeOlderMessages(userID string) error {
+	previous, err := s.repo.GetLatestSummary(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get latest summary: %w", err)
+	}
+
+	// the sequence cursor resumes exactly where the last summary stopped, even within one second
+	var afterSeq int64
+	if previous != nil {
+		afterSeq = previous.LastMessageSeq
+	}
+
+	fetchLimit := maxSummarizeBatch + historyWindowSize
+	messages, err := s.repo.GetMessagesAfter(userID, afterSeq, fetchLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get unsummarized messages: %w", err)
+	}
+
+	// when everything unsummarized was fetched, keep the live history window out of the summary
+	if len(messages) < fetchLimit {
+		if len(messages) <= historyWindowSize {
+			return nil
+		}
+		messages = messages[:len(messages)-historyWindowSize]
+	} else {
+		messages = messages[:maxSummarizeBatch]
+	}
+
+	if len(messages) < summarizeThreshold {
+		return nil
+	}
+
+	text, err := s.writeSummary(userID, previous, messages)
+	if err != nil {
+		return err
+	}
+
+	first, last := messages[0], messages[len(messages)-1]
+	summary := &ConversationSummary{
+		ID:             id.Generate(),
+		UserID:         userID,
+		Summary:        text,
+		FirstMessageID: first.ID,
+		LastMessageID:  last.ID,
+		FirstMessageAt: first.CreatedAt,
+		LastMessageAt:  last.CreatedAt,
+		LastMessageSeq: last.Seq,
+		MessageCount:   len(messages),
+		CreatedAt:      time.Now(),
+	}
+
+	if err := s.repo.SaveSummary(summary); err != nil {
+		return fmt.Errorf("failed to save summary: %w", err)
+	}
+
+	logger.Info("saved conversation summary", logger.Fields{
+		"user_id":       userID,
+		"message_count": summary.MessageCount,
+	})
+
+	return nil
+}
+
+func (s *Service) generateSummary(userID string, previous *ConversationSummary, messages []*ConversationMessage) (string, error) {
+	systemPrompt := `You maintain long-term notes for Eunoia, a mental wellbeing companion.
+
+Write a concise summary (under 150 words) of the conversation so far:
+- Key events, people and situations the user mentioned
+- How they have been feeling and any changes over time
+- What seemed to help them, and anything they asked Eunoia to remember
+- Write in third person ("The user...") and leave out greetings and small talk`
+
+	var prompt strings.Builder
+	if previous != nil {
+		prompt.WriteString("Earlier summary:\n")
+		prompt.WriteString(previous.Summary)
+		prompt.WriteString("\n\n")
+	}
+
+	prompt.WriteString("Newer messages:\n")
+	for _, line := range formatHistory(messages) {
+		prompt.WriteString(line)
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString("\nUpdated summary:")
+
+	summary, err := s.geminiService.For(agent.PurposeSummary, userID).GenerateContent(systemPrompt, prompt.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate summary: %w", err)
+	}
+
+	return strings.TrimSpace(summary), nil
+}
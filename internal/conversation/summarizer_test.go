@@ -0,0 +1,139 @@
+package conversation
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var historyColumns = []string{"id", "user_id", "message_role", "message_content", "context_data", "created_at", "seq"}
+
+var summaryColumns = []string{"id", "user_id", "summary", "first_message_id", "last_message_id",
+	"first_message_at", "last_message_at", "last_message_seq", "message_count", "created_at"}
+
+// historyRows returns count messages numbered from firstSeq, all stored in the same second
+func historyRows(userID string, firstSeq int64, count int, at time.Time) *sqlmock.Rows {
+	rows := sqlmock.NewRows(historyColumns)
+	for i := 0; i < count; i++ {
+		seq := firstSeq + int64(i)
+		rows.AddRow(fmt.Sprintf("msg-%d", seq), userID, "user", fmt.Sprintf("message %d", seq), nil, at, seq)
+	}
+	return rows
+}
+
+func TestSummarizeOlderMessages_ResumesFromSequence(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	var summarized []*ConversationMessage
+	service := &Service{
+		repo: NewRepository(db),
+		writeSummary: func(userID string, previous *ConversationSummary, messages []*ConversationMessage) (string, error) {
+			if previous == nil || previous.ID != "summary-1" {
+				t.Errorf("expected the previous summary to be folded in, got %+v", previous)
+			}
+			summarized = messages
+			return "The user keeps going.", nil
+		},
+	}
+	second := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	// the previous summary stopped in the middle of a second
+	mock.ExpectQuery("SELECT (.+) FROM conversation_summaries").
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows(summaryColumns).
+			AddRow("summary-1", "user-1", "Earlier notes.", "msg-1", "msg-40", second, second, 40, 40, second))
+	mock.ExpectQuery("SELECT (.+) FROM conversation_history WHERE user_id = \\? AND seq > \\?").
+		WithArgs("user-1", int64(40), maxSummarizeBatch+historyWindowSize).
+		WillReturnRows(historyRows("user-1", 41, 35, second))
+	mock.ExpectExec("INSERT INTO conversation_summaries").
+		WithArgs(sqlmock.AnyArg(), "user-1", "The user keeps going.", "msg-41", "msg-65",
+			second, second, int64(65), 25, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := service.summarizeOlderMessages("user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the live history window of the last 10 messages stays out of the summary
+	if len(summarized) != 25 || summarized[0].Seq != 41 || summarized[24].Seq != 65 {
+		t.Errorf("expected messages 41 to 65, got %d messages", len(summarized))
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSummarizeOlderMessages_FullBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := &Service{
+		repo: NewRepository(db),
+		writeSummary: func(userID string, previous *ConversationSummary, messages []*ConversationMessage) (string, error) {
+			return "A long first stretch.", nil
+		},
+	}
+	second := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_summaries").
+		WithArgs("user-1").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT (.+) FROM conversation_history").
+		WithArgs("user-1", int64(0), maxSummarizeBatch+historyWindowSize).
+		WillReturnRows(historyRows("user-1", 1, maxSummarizeBatch+historyWindowSize, second))
+	// a full fetch is cut at the batch size; the next run continues from seq 101
+	mock.ExpectExec("INSERT INTO conversation_summaries").
+		WithArgs(sqlmock.AnyArg(), "user-1", "A long first stretch.", "msg-1", "msg-100",
+			second, second, int64(100), maxSummarizeBatch, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := service.summarizeOlderMessages("user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSummarizeOlderMessages_BelowThreshold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := &Service{
+		repo: NewRepository(db),
+		writeSummary: func(userID string, previous *ConversationSummary, messages []*ConversationMessage) (string, error) {
+			t.Error("expected no summary below the threshold")
+			return "", nil
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM conversation_summaries").
+		WithArgs("user-1").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT (.+) FROM conversation_history").
+		WithArgs("user-1", int64(0), maxSummarizeBatch+historyWindowSize).
+		WillReturnRows(historyRows("user-1", 1, summarizeThreshold+historyWindowSize-1, time.Now()))
+
+	if err := service.summarizeOlderMessages("user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
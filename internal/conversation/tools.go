@@ -0,0 +1,213 @@
+package conversation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+const (
+	defaultToolDays = 7
+	maxToolDays     = 90
+	maxToolEntries  = 10
+	// longest excerpt of a reflection returned to the model
+	toolSnippetLength = 300
+)
+
+const toolsPrompt = `You can look up the user's own data with tools: mood check-in stats and entries, recent reflections, a search over past entries, and today's check-in.
+When the user asks how they have been doing, about their mood over time, or about something they wrote, call the relevant tool instead of guessing. Never invent entries the tools did not return.
+You can also propose the actions your tools offer, such as logging a check-in, saving a reflection, or forgetting something you remember about the user. Proposing only asks the user - never say an action is done until they confirm it.`
+
+// currentTimePrompt tells the model the time, which it cannot know, so reminders land when the user means
+func currentTimePrompt(now time.Time) string {
+	now = now.UTC()
+	return fmt.Sprintf("The current time is %s (%s). The user's timezone is unknown: for a reminder at a clock time, ask which timezone they mean and give remind_at with that offset, or use in_minutes for a relative time.",
+		now.Format("Monday, 2 January 2006, 15:04 MST"), now.Format(time.RFC3339))
+}
+
+type toolCheckIn struct {
+	Date        string `json:"date"`
+	MoodScore   int    `json:"mood_score"`
+	MoodLabel   string `json:"mood_label"`
+	Description string `json:"description,omitempty"`
+}
+
+type toolReflection struct {
+	Date      string `json:"date"`
+	Excerpt   string `json:"excerpt"`
+	Sentiment string `json:"sentiment,omitempty"`
+	KeyThemes string `json:"key_themes,omitempty"`
+}
+
+// newToolRegistry registers the tools the model may call about the current user
+func (s *Service) newToolRegistry() *agent.ToolRegistry {
+	registry := agent.NewToolRegistry()
+
+	tools := []*agent.Tool{
+		{
+			Name:        "get_checkin_stats",
+			Description: "Get the user's mood check-in average, trend and individual check-ins over the last N days.",
+			Parameters: map[string]*agent.ToolParameter{
+				"days": {Type: agent.ParamInteger, Description: "How many days to look back, 1-90. Defaults to 7."},
+			},
+			Handler: s.toolCheckInStats,
+		},
+		{
+			Name:        "list_recent_reflections",
+			Description: "List the user's most recent journal reflections with their sentiment and themes.",
+			Parameters: map[string]*agent.ToolParameter{
+				"limit": {Type: agent.ParamInteger, Description: "How many reflections to return, 1-10. Defaults to 5."},
+			},
+			Handler: s.toolRecentReflections,
+		},
+		{
+			Name:        "search_entries",
+			Description: "Full-text search over the user's past reflections and messages.",
+			Parameters: map[string]*agent.ToolParameter{
+				"query": {Type: agent.ParamString, Description: "Words to search for.", Required: true},
+				"days":  {Type: agent.ParamInteger, Description: "Only search the last N days. Omit to search everything."},
+			},
+			Handler: s.toolSearchEntries,
+		},
+		{
+			Name:        "get_today_checkin",
+			Description: "Get the user's mood check-in for today, if they have logged one.",
+			Handler:     s.toolTodayCheckIn,
+		},
+	}
+
+	for _, tool := range tools {
+		if err := registry.Register(tool); err != nil {
+			logger.Error("failed to register tool", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{"tool": tool.Name},
+			))
+		}
+	}
+
+	s.registerActionTools(registry)
+	s.registerDelegationTool(registry)
+
+	return registry
+}
+
+func (s *Service) toolCheckInStats(ctx *agent.ToolContext, args agent.ToolArgs) (any, error) {
+	days := clampInt(args.Int("days", defaultToolDays), 1, maxToolDays)
+
+	stats, err := s.checkInService.GetCheckInStats(ctx.PlatformUserID, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load check-in stats")
+	}
+
+	history, err := s.checkInService.GetCheckInHistory(ctx.PlatformUserID, maxToolDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load check-ins")
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	checkIns := []toolCheckIn{}
+	for _, c := range history {
+		if c.CheckInDate.Before(since) {
+			continue
+		}
+		checkIns = append(checkIns, toolCheckIn{
+			Date:        c.CheckInDate.Format(dateLayout),
+			MoodScore:   c.MoodScore,
+			MoodLabel:   c.MoodLabel,
+			Description: truncateRunes(c.Description, toolSnippetLength),
+		})
+	}
+
+	return map[string]any{
+		"days":               days,
+		"total_check_ins":    stats.TotalCheckIns,
+		"average_mood_score": stats.AverageMoodScore,
+		"mood_trend":         stats.MoodTrend,
+		"check_ins":          checkIns,
+	}, nil
+}
+
+func (s *Service) toolRecentReflections(ctx *agent.ToolContext, args agent.ToolArgs) (any, error) {
+	limit := clampInt(args.Int("limit", 5), 1, maxToolEntries)
+
+	reflections, err := s.reflectionService.GetReflectionHistory(ctx.PlatformUserID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reflections")
+	}
+
+	entries := []toolReflection{}
+	for _, r := range reflections {
+		entries = append(entries, toolReflection{
+			Date:      r.CreatedAt.Format(dateLayout),
+			Excerpt:   truncateRunes(r.Content, toolSnippetLength),
+			Sentiment: r.Sentiment,
+			KeyThemes: r.KeyThemes,
+		})
+	}
+
+	return map[string]any{"reflections": entries}, nil
+}
+
+func (s *Service) toolSearchEntries(ctx *agent.ToolContext, args agent.ToolArgs) (any, error) {
+	query := args.String("query")
+	if query == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+
+	var from time.Time
+	if days := args.Int("days", 0); days > 0 {
+		from = time.Now().AddDate(0, 0, -days)
+	}
+
+	results, err := s.searchUserEntries(ctx.UserID, query, from, time.Time{}, maxToolEntries)
+	if err != nil {
+		return nil, fmt.Errorf("search failed")
+	}
+
+	if results == nil {
+		results = []*SearchResult{}
+	}
+
+	return map[string]any{"query": query, "results": results}, nil
+}
+
+func (s *Service) toolTodayCheckIn(ctx *agent.ToolContext, args agent.ToolArgs) (any, error) {
+	checkIn, err := s.checkInService.GetTodayCheckIn(ctx.PlatformUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load today's check-in")
+	}
+
+	if checkIn == nil {
+		return map[string]any{"checked_in": false}, nil
+	}
+
+	return map[string]any{
+		"checked_in": true,
+		"check_in": toolCheckIn{
+			Date:        checkIn.CheckInDate.Format(dateLayout),
+			MoodScore:   checkIn.MoodScore,
+			MoodLabel:   checkIn.MoodLabel,
+			Description: truncateRunes(checkIn.Description, toolSnippetLength),
+		},
+	}, nil
+}
+
+func clampInt(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "..."
+}
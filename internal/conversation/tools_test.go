@@ -0,0 +1,125 @@
+package conversation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/checkin"
+	"github.com/zjoart/eunoia/internal/user"
+)
+
+func TestNewToolRegistry(t *testing.T) {
+	service := &Service{}
+	registry := service.newToolRegistry()
+
+	expected := []string{
+		"get_checkin_stats", "get_today_checkin", "list_recent_reflections",
+		"log_checkin", "save_reflection", "schedule_reminder", "search_entries",
+	}
+	names := registry.Names()
+
+	if len(names) != len(expected) {
+		t.Fatalf("expected tools %v, got %v", expected, names)
+	}
+	for i, name := range expected {
+		if names[i] != name {
+			t.Errorf("expected tool %s at %d, got %s", name, i, names[i])
+		}
+	}
+}
+
+func TestToolTodayCheckIn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	userRepo := user.NewRepository(db)
+	service := &Service{
+		checkInService: checkin.NewService(checkin.NewRepository(db), userRepo),
+	}
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM users").
+		WithArgs("platform-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "platform_user_id", "username", "created_at", "updated_at"}).
+			AddRow("user-1", "platform-1", "", now, now))
+	mock.ExpectQuery("SELECT (.+) FROM emotional_checkins").
+		WithArgs("user-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "mood_score", "mood_label", "description", "check_in_date", "created_at"}).
+			AddRow("checkin-1", "user-1", 4, "low", "Rough morning", now, now))
+
+	registry := service.newToolRegistry()
+	result := registry.Execute(&agent.ToolContext{UserID: "user-1", PlatformUserID: "platform-1"}, "get_today_checkin", nil)
+
+	if result["checked_in"] != true {
+		t.Fatalf("expected a check-in, got %v", result)
+	}
+
+	checkIn, ok := result["check_in"].(map[string]any)
+	if !ok || checkIn["mood_score"] != float64(4) || checkIn["mood_label"] != "low" {
+		t.Errorf("unexpected check-in: %v", result["check_in"])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestToolSearchEntries_RequiresQuery(t *testing.T) {
+	service := &Service{}
+	registry := service.newToolRegistry()
+
+	result := registry.Execute(&agent.ToolContext{UserID: "user-1"}, "search_entries", agent.ToolArgs{"query": "  "})
+	if result["error"] == nil {
+		t.Errorf("expected an error for an empty query, got %v", result)
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	if got := truncateRunes("héllo wörld", 5); got != "héllo..." {
+		t.Errorf("expected rune-safe truncation, got %q", got)
+	}
+	if got := truncateRunes("short", 10); got != "short" {
+		t.Errorf("expected short text unchanged, got %q", got)
+	}
+}
+
+func TestToolsFor_OffersRemindersWhereDelivered(t *testing.T) {
+	service := &Service{}
+	service.tools = service.newToolRegistry()
+	service.SetReminderPlatforms("slack", ChatAPIPlatform)
+
+	tests := []struct {
+		platformUserID string
+		offered        bool
+	}{
+		{"slack:T1:U1", true},
+		{"app:u1", true},
+		{"telex:user-1", false},
+		{"discord:123", false},
+	}
+
+	for _, tt := range tests {
+		_, offered := service.toolsFor(tt.platformUserID).Get("schedule_reminder")
+		if offered != tt.offered {
+			t.Errorf("%s: expected schedule_reminder offered=%v", tt.platformUserID, tt.offered)
+		}
+	}
+}
+
+func TestCurrentTimePrompt(t *testing.T) {
+	now := time.Date(2026, 10, 19, 15, 4, 0, 0, time.FixedZone("WAT", 3600))
+
+	prompt := currentTimePrompt(now)
+	if !strings.Contains(prompt, "Monday, 19 October 2026, 14:04 UTC (2026-10-19T14:04:00Z)") {
+		t.Errorf("expected the current UTC time, got %q", prompt)
+	}
+	if !strings.Contains(prompt, "timezone is unknown") {
+		t.Errorf("expected the timezone caveat, got %q", prompt)
+	}
+}
@@ -0,0 +1,18 @@
+package delegation
+
+// TrustedAgent is an agent Eunoia is allowed to hand requests to
+type TrustedAgent struct {
+	Name string
+	// URL is the base URL the agent card is discovered under
+	URL   string
+	Token string
+}
+
+// Reply is what a delegated agent answered
+type Reply struct {
+	Agent     string `json:"agent"`
+	TaskID    string `json:"task_id,omitempty"`
+	ContextID string `json:"context_id,omitempty"`
+	State     string `json:"state"`
+	Text      string `json:"text"`
+}
@@ -0,0 +1,202 @@
+package delegation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/a2a"
+	"github.com/zjoart/eunoia/pkg/id"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+var (
+	ErrUnknownAgent      = errors.New("agent is not in the trusted list")
+	ErrUntrustedEndpoint = errors.New("agent card points outside the trusted host")
+	ErrNoReply           = errors.New("agent returned no reply")
+)
+
+const defaultPollInterval = 2 * time.Second
+
+type Service struct {
+	agents       map[string]TrustedAgent
+	httpClient   *http.Client
+	timeout      time.Duration
+	pollInterval time.Duration
+
+	mu    sync.Mutex
+	cards map[string]*a2a.AgentCard
+}
+
+// NewService creates a delegation service limited to the given agents; timeout bounds a whole delegation
+func NewService(agents []TrustedAgent, timeout time.Duration) *Service {
+	trusted := make(map[string]TrustedAgent, len(agents))
+	for _, agent := range agents {
+		trusted[agent.Name] = agent
+	}
+
+	return &Service{
+		agents:       trusted,
+		httpClient:   &http.Client{Timeout: timeout},
+		timeout:      timeout,
+		pollInterval: defaultPollInterval,
+		cards:        make(map[string]*a2a.AgentCard),
+	}
+}
+
+// Names returns the trusted agent names in alphabetical order
+func (s *Service) Names() []string {
+	names := make([]string, 0, len(s.agents))
+	for name := range s.agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Service) IsTrusted(name string) bool {
+	_, ok := s.agents[name]
+	return ok
+}
+
+// Delegate sends request to a trusted agent and waits for its answer. Only the request text is sent,
+// never the user's identity or history.
+func (s *Service) Delegate(ctx context.Context, name, request string) (*Reply, error) {
+	agent, ok := s.agents[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAgent, name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	card, err := s.card(ctx, agent)
+	if err != nil {
+		return nil, err
+	}
+
+	client := a2a.NewClient(card.URL, s.httpClient)
+	if agent.Token != "" {
+		client.SetBearerToken(agent.Token)
+	}
+
+	blocking := true
+	result, err := client.SendMessage(ctx, &a2a.MessageSendParams{
+		Message: a2a.A2AMessage{
+			Kind:      "message",
+			Role:      "user",
+			MessageID: id.Generate(),
+			Parts:     []a2a.A2APart{{Kind: "text", Text: request}},
+		},
+		Configuration: a2a.A2AConfig{
+			AcceptedOutputModes: []string{"text/plain"},
+			Blocking:            &blocking,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send message to %s: %w", name, err)
+	}
+
+	// agents may still answer blocking calls with a task in progress
+	if !a2a.IsFinalState(result.Status.State) && result.ID != "" {
+		result, err = client.WaitForTask(ctx, result.ID, s.pollInterval)
+		if err != nil {
+			return nil, fmt.Errorf("failed waiting for %s: %w", name, err)
+		}
+	}
+
+	switch result.Status.State {
+	case a2a.TaskStateFailed, a2a.TaskStateRejected, a2a.TaskStateCanceled:
+		return nil, fmt.Errorf("agent %s ended the task as %s", name, result.Status.State)
+	}
+
+	text := replyText(result)
+	if text == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNoReply, name)
+	}
+
+	logger.Info("delegated request to agent", logger.Fields{
+		"agent":   name,
+		"task_id": result.ID,
+		"state":   result.Status.State,
+	})
+
+	return &Reply{
+		Agent:     name,
+		TaskID:    result.ID,
+		ContextID: result.ContextID,
+		State:     result.Status.State,
+		Text:      text,
+	}, nil
+}
+
+// card discovers an agent's card once, rejecting cards whose endpoint is on another host
+func (s *Service) card(ctx context.Context, agent TrustedAgent) (*a2a.AgentCard, error) {
+	s.mu.Lock()
+	card, ok := s.cards[agent.Name]
+	s.mu.Unlock()
+	if ok {
+		return card, nil
+	}
+
+	card, err := a2a.DiscoverAgent(ctx, s.httpClient, agent.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover %s: %w", agent.Name, err)
+	}
+
+	if !sameHost(agent.URL, card.URL) {
+		logger.Warn("trusted agent card points to another host", logger.Fields{
+			"agent":    agent.Name,
+			"card_url": card.URL,
+		})
+		return nil, fmt.Errorf("%w: %s", ErrUntrustedEndpoint, agent.Name)
+	}
+
+	s.mu.Lock()
+	s.cards[agent.Name] = card
+	s.mu.Unlock()
+
+	return card, nil
+}
+
+func sameHost(trusted, endpoint string) bool {
+	trustedURL, err := url.Parse(trusted)
+	if err != nil {
+		return false
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+
+	return endpointURL.Scheme == trustedURL.Scheme && strings.EqualFold(endpointURL.Host, trustedURL.Host)
+}
+
+// replyText prefers the task's text artifacts, falling back to its status message
+func replyText(result *a2a.A2AResult) string {
+	var texts []string
+	for _, artifact := range result.Artifacts {
+		texts = append(texts, textParts(artifact.Parts)...)
+	}
+	if len(texts) == 0 {
+		texts = textParts(result.Status.Message.Parts)
+	}
+
+	return strings.TrimSpace(strings.Join(texts, "\n\n"))
+}
+
+func textParts(parts []a2a.A2APart) []string {
+	var texts []string
+	for _, part := range parts {
+		if part.Kind == "text" && strings.TrimSpace(part.Text) != "" {
+			texts = append(texts, strings.TrimSpace(part.Text))
+		}
+	}
+	return texts
+}
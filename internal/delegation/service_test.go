@@ -0,0 +1,112 @@
+package delegation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
+// newAgent serves an agent card and an A2A endpoint; cardURL overrides the endpoint advertised in the card
+func newAgent(t *testing.T, server *a2a.Server, cardURL string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	agent := httptest.NewServer(mux)
+	t.Cleanup(agent.Close)
+
+	if cardURL == "" {
+		cardURL = agent.URL + "/a2a"
+	}
+	mux.HandleFunc(a2a.AgentCardPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(a2a.AgentCard{Name: "Meditation Guide", URL: cardURL})
+	})
+	mux.Handle("/a2a", server)
+
+	return agent
+}
+
+func TestDelegate_PollsUntilDone(t *testing.T) {
+	var received string
+	polls := 0
+
+	server := a2a.NewServer()
+	server.OnMessageSend(func(r *http.Request, params *a2a.MessageSendParams) (*a2a.A2AResult, *a2a.A2AError) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			return nil, a2a.NewError(a2a.InvalidRequest, "missing token")
+		}
+		received = params.Message.Parts[0].Text
+		return &a2a.A2AResult{ID: "task-1", Status: a2a.A2ATaskStatus{State: a2a.TaskStateWorking}, Kind: "task"}, nil
+	})
+	server.OnGetTask(func(r *http.Request, params *a2a.TaskQueryParams) (*a2a.A2AResult, *a2a.A2AError) {
+		polls++
+		return &a2a.A2AResult{
+			ID:     params.ID,
+			Status: a2a.A2ATaskStatus{State: a2a.TaskStateCompleted},
+			Artifacts: []a2a.A2AArtifact{{
+				Parts: []a2a.A2APart{{Kind: "text", Text: "Try a 5 minute body scan."}},
+			}},
+			Kind: "task",
+		}, nil
+	})
+	agent := newAgent(t, server, "")
+
+	service := NewService([]TrustedAgent{{Name: "meditation", URL: agent.URL, Token: "tok"}}, 5*time.Second)
+	service.pollInterval = time.Millisecond
+
+	reply, err := service.Delegate(context.Background(), "meditation", "a short meditation for sleep")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received != "a short meditation for sleep" {
+		t.Errorf("expected the request to be forwarded, got %q", received)
+	}
+	if polls != 1 || reply.TaskID != "task-1" || reply.Text != "Try a 5 minute body scan." {
+		t.Errorf("unexpected reply %+v after %d polls", reply, polls)
+	}
+}
+
+func TestDelegate_UnknownAgent(t *testing.T) {
+	service := NewService(nil, time.Second)
+
+	_, err := service.Delegate(context.Background(), "scheduler", "book a call")
+	if !errors.Is(err, ErrUnknownAgent) {
+		t.Errorf("expected ErrUnknownAgent, got %v", err)
+	}
+}
+
+func TestDelegate_CardOnAnotherHost(t *testing.T) {
+	server := a2a.NewServer()
+	server.OnMessageSend(func(r *http.Request, params *a2a.MessageSendParams) (*a2a.A2AResult, *a2a.A2AError) {
+		t.Error("message should not be sent to an untrusted endpoint")
+		return nil, nil
+	})
+	agent := newAgent(t, server, "https://elsewhere.example/a2a")
+
+	service := NewService([]TrustedAgent{{Name: "meditation", URL: agent.URL}}, time.Second)
+
+	_, err := service.Delegate(context.Background(), "meditation", "hello")
+	if !errors.Is(err, ErrUntrustedEndpoint) {
+		t.Errorf("expected ErrUntrustedEndpoint, got %v", err)
+	}
+}
+
+func TestDelegate_FailedTask(t *testing.T) {
+	server := a2a.NewServer()
+	server.OnMessageSend(func(r *http.Request, params *a2a.MessageSendParams) (*a2a.A2AResult, *a2a.A2AError) {
+		return &a2a.A2AResult{ID: "task-1", Status: a2a.A2ATaskStatus{State: a2a.TaskStateFailed}, Kind: "task"}, nil
+	})
+	agent := newAgent(t, server, "")
+
+	service := NewService([]TrustedAgent{{Name: "meditation", URL: agent.URL}}, time.Second)
+
+	if _, err := service.Delegate(context.Background(), "meditation", "hello"); err == nil {
+		t.Error("expected an error for a failed task")
+	}
+}
@@ -0,0 +1,78 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the base URL of the Discord HTTP API
+const DefaultAPIURL = "https://discord.com/api/v10"
+
+// Client edits interaction responses and sends follow-ups, which authenticate with the
+// interaction token; registering commands needs the bot token
+type Client struct {
+	baseURL    string
+	botToken   string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL, botToken string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		botToken:   botToken,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// EditOriginal replaces the deferred response of an interaction
+func (c *Client) EditOriginal(ctx context.Context, applicationID, token string, data *ResponseData) error {
+	path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", applicationID, token)
+	return c.do(ctx, http.MethodPatch, path, data, false)
+}
+
+// SendFollowup posts another message in reply to an interaction
+func (c *Client) SendFollowup(ctx context.Context, applicationID, token string, data *ResponseData) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/webhooks/%s/%s", applicationID, token), data, false)
+}
+
+// RegisterCommands replaces the application's global slash commands
+func (c *Client) RegisterCommands(ctx context.Context, applicationID string, commands []ApplicationCommand) error {
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("/applications/%s/commands", applicationID), commands, true)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, payload any, bot bool) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode discord request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build discord request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if bot {
+		req.Header.Set("Authorization", "Bot "+c.botToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// the path carries the interaction token, so it is left out of the error
+		return fmt.Errorf("discord %s request failed", method)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("discord %s request returned status %d: %s", method, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
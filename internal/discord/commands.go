@@ -0,0 +1,99 @@
+package discord
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// slash command names
+const (
+	CommandCheckIn   = "checkin"
+	CommandReflect   = "reflect"
+	CommandMoodStats = "mood-stats"
+	CommandChat      = "chat"
+)
+
+// the longest message Discord accepts
+const maxContentLength = 2000
+
+// Commands returns the slash commands Eunoia registers
+func Commands() []ApplicationCommand {
+	one, ten, ninety := 1, 10, 90
+	everywhere := []int{0, 1, 2}
+
+	return []ApplicationCommand{
+		{
+			Name:        CommandCheckIn,
+			Description: "Log how you're feeling",
+			Options: []CommandOptionDef{
+				{Type: OptionInteger, Name: "mood", Description: "Your mood from 1 (very low) to 10 (great)", Required: true, MinValue: &one, MaxValue: &ten},
+				{Type: OptionString, Name: "note", Description: "What's behind it", MaxLength: 500},
+			},
+			Contexts: everywhere,
+		},
+		{
+			Name:        CommandReflect,
+			Description: "Save a reflection and get a gentle analysis",
+			Options: []CommandOptionDef{
+				{Type: OptionString, Name: "text", Description: "What's on your mind", Required: true, MaxLength: 4000},
+			},
+			Contexts: everywhere,
+		},
+		{
+			Name:        CommandMoodStats,
+			Description: "See how your mood has been lately",
+			Options: []CommandOptionDef{
+				{Type: OptionInteger, Name: "days", Description: "How many days to look back (default 7)", MinValue: &one, MaxValue: &ninety},
+			},
+			Contexts: everywhere,
+		},
+		{
+			Name:        CommandChat,
+			Description: "Talk with Eunoia",
+			Options: []CommandOptionDef{
+				{Type: OptionString, Name: "message", Description: "What you'd like to say", Required: true, MaxLength: 4000},
+			},
+			Contexts: everywhere,
+		},
+	}
+}
+
+func (d *CommandData) stringOption(name string) string {
+	for _, option := range d.Options {
+		if option.Name == name {
+			var value string
+			json.Unmarshal(option.Value, &value)
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func (d *CommandData) intOption(name string, defaultValue int) int {
+	for _, option := range d.Options {
+		if option.Name == name {
+			var value int
+			if err := json.Unmarshal(option.Value, &value); err == nil {
+				return value
+			}
+		}
+	}
+	return defaultValue
+}
+
+// splitContent breaks a reply into messages Discord accepts, preferring line and word boundaries
+func splitContent(content string) []string {
+	var chunks []string
+	for len(content) > maxContentLength {
+		cut := strings.LastIndex(content[:maxContentLength], "\n")
+		if cut <= 0 {
+			cut = strings.LastIndex(content[:maxContentLength], " ")
+		}
+		if cut <= 0 {
+			cut = maxContentLength
+		}
+		chunks = append(chunks, strings.TrimSpace(content[:cut]))
+		content = strings.TrimSpace(content[cut:])
+	}
+	return append(chunks, content)
+}
@@ -0,0 +1,280 @@
+package discord
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/checkin"
+	"github.com/zjoart/eunoia/internal/conversation"
+	"github.com/zjoart/eunoia/internal/conversation/platforms"
+	"github.com/zjoart/eunoia/internal/reflection"
+	"github.com/zjoart/eunoia/internal/user"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// largest interaction body accepted
+const maxBodyBytes = 1 << 20
+
+// how long a deferred command may take before its follow-up is given up
+const deferredTimeout = 5 * time.Minute
+
+const defaultStatsDays = 7
+
+// ConversationService is the part of the conversation service the adapter needs
+type ConversationService interface {
+	ProcessMessage(req *conversation.ChatRequest) (*conversation.ChatResponse, error)
+}
+
+type CheckInService interface {
+	CreateCheckIn(req *checkin.CreateCheckInRequest) (*checkin.EmotionalCheckIn, error)
+	GetCheckInStats(platformUserID string, days int) (*checkin.CheckInStats, error)
+	GenerateMoodInsight(stats *checkin.CheckInStats) string
+}
+
+type ReflectionService interface {
+	CreateReflection(req *reflection.CreateReflectionRequest) (*reflection.Reflection, error)
+}
+
+// Handler serves the interactions endpoint. Quick commands are answered directly; commands that
+// wait on the model are deferred and completed by editing the response.
+type Handler struct {
+	conversations ConversationService
+	checkIns      CheckInService
+	reflections   ReflectionService
+	client        *Client
+	platform      *platforms.DiscordPlatform
+	publicKey     ed25519.PublicKey
+	// background tracks deferred commands still being answered
+	background sync.WaitGroup
+}
+
+func NewHandler(conversations ConversationService, checkIns CheckInService, reflections ReflectionService, client *Client, publicKey ed25519.PublicKey) *Handler {
+	return &Handler{
+		conversations: conversations,
+		checkIns:      checkIns,
+		reflections:   reflections,
+		client:        client,
+		platform:      platforms.NewDiscordPlatform(),
+		publicKey:     publicKey,
+	}
+}
+
+// HandleInteractions serves the interactions endpoint URL
+func (h *Handler) HandleInteractions(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	// Discord checks that unsigned requests are rejected before it accepts the endpoint
+	if err := Verify(h.publicKey, r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader), body); err != nil {
+		logger.Warn("rejected discord interaction with invalid signature", logger.Fields{"path": r.URL.Path})
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var interaction Interaction
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	switch interaction.Type {
+	case InteractionPing:
+		h.respond(w, &InteractionResponse{Type: ResponsePong})
+	case InteractionApplicationCommand:
+		h.respond(w, h.handleCommand(&interaction))
+	default:
+		http.Error(w, "unsupported interaction type", http.StatusBadRequest)
+	}
+}
+
+func (h *Handler) handleCommand(interaction *Interaction) *InteractionResponse {
+	userID := h.userID(interaction)
+	if userID == "" || interaction.Data == nil {
+		return h.message(interaction, "I couldn't tell who sent that command.")
+	}
+	data := interaction.Data
+
+	fields := logger.Fields{"command": data.Name, "interaction_id": interaction.ID}
+
+	switch data.Name {
+	case CommandCheckIn:
+		return h.checkIn(interaction, userID, data, fields)
+	case CommandMoodStats:
+		return h.moodStats(interaction, userID, data, fields)
+	case CommandReflect:
+		text := data.stringOption("text")
+		if text == "" {
+			return h.message(interaction, "Share a few words to reflect on.")
+		}
+		return h.deferred(interaction, fields, func() (string, error) {
+			return h.reflect(userID, text)
+		})
+	case CommandChat:
+		message := data.stringOption("message")
+		if message == "" {
+			return h.message(interaction, "What would you like to talk about?")
+		}
+		return h.deferred(interaction, fields, func() (string, error) {
+			return h.chat(interaction, userID, message)
+		})
+	default:
+		return h.message(interaction, "I don't know that command.")
+	}
+}
+
+func (h *Handler) checkIn(interaction *Interaction, userID string, data *CommandData, fields logger.Fields) *InteractionResponse {
+	score := data.intOption("mood", 0)
+	if score < 1 || score > 10 {
+		return h.message(interaction, "Pick a mood from 1 to 10.")
+	}
+
+	label := checkin.LabelForScore(score)
+	_, err := h.checkIns.CreateCheckIn(&checkin.CreateCheckInRequest{
+		PlatformUserID: userID,
+		MoodScore:      score,
+		MoodLabel:      label,
+		Description:    data.stringOption("note"),
+	})
+	if err != nil {
+		logger.Error("failed to log discord check-in", logger.Merge(logger.WithError(err), fields))
+		return h.message(interaction, "Sorry, I couldn't log that check-in right now. Please try again in a moment.")
+	}
+
+	return h.message(interaction, fmt.Sprintf("Logged your check-in: %d/10 (%s). Thank you for checking in with yourself.", score, label))
+}
+
+func (h *Handler) moodStats(interaction *Interaction, userID string, data *CommandData, fields logger.Fields) *InteractionResponse {
+	days := data.intOption("days", defaultStatsDays)
+	if days < 1 || days > 90 {
+		days = defaultStatsDays
+	}
+
+	stats, err := h.checkIns.GetCheckInStats(userID, days)
+	if errors.Is(err, user.ErrUserNotFound) {
+		stats = &checkin.CheckInStats{}
+	} else if err != nil {
+		logger.Error("failed to get discord mood stats", logger.Merge(logger.WithError(err), fields))
+		return h.message(interaction, "Sorry, I couldn't load your mood stats right now.")
+	}
+
+	content := h.checkIns.GenerateMoodInsight(stats)
+	if stats.TotalCheckIns > 0 {
+		content = fmt.Sprintf("**Last %d days:** %d check-ins, average mood %.1f/10.\n%s", days, stats.TotalCheckIns, stats.AverageMoodScore, content)
+	}
+	return h.message(interaction, content)
+}
+
+func (h *Handler) reflect(userID, text string) (string, error) {
+	saved, err := h.reflections.CreateReflection(&reflection.CreateReflectionRequest{
+		PlatformUserID: userID,
+		Content:        text,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	content := "Your reflection is saved.\n\n" + saved.AIAnalysis
+	if saved.KeyThemes != "" {
+		content += "\n\n**Themes:** " + saved.KeyThemes
+	}
+	return content, nil
+}
+
+func (h *Handler) chat(interaction *Interaction, userID, message string) (string, error) {
+	metadata := map[string]interface{}{platforms.DiscordChannelKey: interaction.ChannelID}
+	channelID, _ := h.platform.ExtractChannelID(metadata)
+
+	resp, err := h.conversations.ProcessMessage(&conversation.ChatRequest{
+		PlatformUserID: userID,
+		Message:        message,
+		MessageID:      interaction.ID,
+		ChannelID:      channelID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Response, nil
+}
+
+// deferred acknowledges a slow command at once, then edits the response with the result and sends
+// whatever does not fit as follow-up messages
+func (h *Handler) deferred(interaction *Interaction, fields logger.Fields, run func() (string, error)) *InteractionResponse {
+	flags := ephemeralFlags(interaction)
+
+	h.background.Add(1)
+	go func() {
+		defer h.background.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), deferredTimeout)
+		defer cancel()
+
+		content, err := run()
+		if err != nil {
+			logger.Error("failed to answer discord command", logger.Merge(logger.WithError(err), fields))
+			content = "Sorry, something went wrong on my side. Please try again in a moment."
+		}
+
+		chunks := splitContent(content)
+		if err := h.client.EditOriginal(ctx, interaction.ApplicationID, interaction.Token, &ResponseData{Content: chunks[0]}); err != nil {
+			logger.Error("failed to edit discord response", logger.Merge(logger.WithError(err), fields))
+			return
+		}
+		for _, chunk := range chunks[1:] {
+			if err := h.client.SendFollowup(ctx, interaction.ApplicationID, interaction.Token, &ResponseData{Content: chunk, Flags: flags}); err != nil {
+				logger.Error("failed to send discord follow-up", logger.Merge(logger.WithError(err), fields))
+				return
+			}
+		}
+	}()
+
+	return &InteractionResponse{Type: ResponseDeferredChannelMessage, Data: &ResponseData{Flags: flags}}
+}
+
+func (h *Handler) message(interaction *Interaction, content string) *InteractionResponse {
+	return &InteractionResponse{
+		Type: ResponseChannelMessage,
+		Data: &ResponseData{Content: splitContent(content)[0], Flags: ephemeralFlags(interaction)},
+	}
+}
+
+func (h *Handler) respond(w http.ResponseWriter, response *InteractionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// userID returns the namespaced ID of the user who ran the command
+func (h *Handler) userID(interaction *Interaction) string {
+	var discordUser *User
+	switch {
+	case interaction.Member != nil && interaction.Member.User != nil:
+		discordUser = interaction.Member.User
+	case interaction.User != nil:
+		discordUser = interaction.User
+	default:
+		return ""
+	}
+
+	userID, err := h.platform.ExtractUserID(map[string]interface{}{platforms.DiscordUserKey: discordUser.ID})
+	if err != nil {
+		return ""
+	}
+	return platforms.NamespacedUserID(h.platform.Name(), userID)
+}
+
+// ephemeralFlags keeps replies in servers visible only to the user; direct messages are private already
+func ephemeralFlags(interaction *Interaction) int {
+	if interaction.GuildID != "" {
+		return FlagEphemeral
+	}
+	return 0
+}
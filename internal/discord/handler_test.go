@@ -0,0 +1,319 @@
+package discord
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/checkin"
+	"github.com/zjoart/eunoia/internal/conversation"
+	"github.com/zjoart/eunoia/internal/reflection"
+	"github.com/zjoart/eunoia/internal/user"
+)
+
+type mockConversations struct {
+	requests []*conversation.ChatRequest
+	response string
+}
+
+func (m *mockConversations) ProcessMessage(req *conversation.ChatRequest) (*conversation.ChatResponse, error) {
+	m.requests = append(m.requests, req)
+	return &conversation.ChatResponse{Response: m.response}, nil
+}
+
+type mockCheckIns struct {
+	created  []*checkin.CreateCheckInRequest
+	stats    *checkin.CheckInStats
+	statsErr error
+}
+
+func (m *mockCheckIns) CreateCheckIn(req *checkin.CreateCheckInRequest) (*checkin.EmotionalCheckIn, error) {
+	m.created = append(m.created, req)
+	return &checkin.EmotionalCheckIn{ID: "checkin-1", MoodScore: req.MoodScore}, nil
+}
+
+func (m *mockCheckIns) GetCheckInStats(platformUserID string, days int) (*checkin.CheckInStats, error) {
+	return m.stats, m.statsErr
+}
+
+func (m *mockCheckIns) GenerateMoodInsight(stats *checkin.CheckInStats) string {
+	if stats.TotalCheckIns == 0 {
+		return "Welcome!"
+	}
+	return "Your mood is trending upward."
+}
+
+type mockReflections struct {
+	created []*reflection.CreateReflectionRequest
+	err     error
+}
+
+func (m *mockReflections) CreateReflection(req *reflection.CreateReflectionRequest) (*reflection.Reflection, error) {
+	m.created = append(m.created, req)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &reflection.Reflection{ID: "reflection-1", AIAnalysis: "You're being kind to yourself.", KeyThemes: "rest"}, nil
+}
+
+type apiRequest struct {
+	method string
+	path   string
+	auth   string
+	data   ResponseData
+}
+
+// fakeDiscord records calls to the interaction webhook and command routes
+type fakeDiscord struct {
+	mu       sync.Mutex
+	requests []apiRequest
+}
+
+func newFakeDiscord(t *testing.T) (*fakeDiscord, *Client) {
+	fake := &fakeDiscord{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data ResponseData
+		json.NewDecoder(r.Body).Decode(&data)
+
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, apiRequest{method: r.Method, path: r.URL.Path, auth: r.Header.Get("Authorization"), data: data})
+		fake.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"msg-1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, NewClient(server.URL, "bot-token", time.Second)
+}
+
+type testHandler struct {
+	*Handler
+	key           ed25519.PrivateKey
+	fake          *fakeDiscord
+	conversations *mockConversations
+	checkIns      *mockCheckIns
+	reflections   *mockReflections
+}
+
+func newTestHandler(t *testing.T) *testHandler {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	fake, client := newFakeDiscord(t)
+	h := &testHandler{
+		key:           privateKey,
+		fake:          fake,
+		conversations: &mockConversations{response: "I'm here with you."},
+		checkIns:      &mockCheckIns{stats: &checkin.CheckInStats{}},
+		reflections:   &mockReflections{},
+	}
+	h.Handler = NewHandler(h.conversations, h.checkIns, h.reflections, client, publicKey)
+	return h
+}
+
+func (h *testHandler) post(t *testing.T, interaction *Interaction) (*httptest.ResponseRecorder, *InteractionResponse) {
+	t.Helper()
+
+	body, _ := json.Marshal(interaction)
+	timestamp := "1700000000"
+
+	req := httptest.NewRequest(http.MethodPost, "/discord/interactions", bytes.NewReader(body))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, hex.EncodeToString(ed25519.Sign(h.key, append([]byte(timestamp), body...))))
+	w := httptest.NewRecorder()
+
+	h.HandleInteractions(w, req)
+	h.background.Wait()
+
+	var resp InteractionResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w, &resp
+}
+
+func command(name string, options map[string]any) *Interaction {
+	data := &CommandData{ID: "cmd-1", Name: name}
+	for key, value := range options {
+		raw, _ := json.Marshal(value)
+		data.Options = append(data.Options, CommandOption{Name: key, Value: raw})
+	}
+
+	return &Interaction{
+		ID:            "int-1",
+		ApplicationID: "app-1",
+		Type:          InteractionApplicationCommand,
+		Data:          data,
+		GuildID:       "guild-1",
+		ChannelID:     "chan-1",
+		Member:        &Member{User: &User{ID: "42", Username: "ada"}},
+		Token:         "interaction-token",
+	}
+}
+
+func TestHandleInteractions_Ping(t *testing.T) {
+	h := newTestHandler(t)
+
+	w, resp := h.post(t, &Interaction{ID: "int-0", Type: InteractionPing})
+	if w.Code != http.StatusOK || resp.Type != ResponsePong {
+		t.Errorf("expected a pong, got %d %+v", w.Code, resp)
+	}
+}
+
+func TestHandleInteractions_InvalidSignature(t *testing.T) {
+	h := newTestHandler(t)
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+
+	body := []byte(`{"id":"int-0","type":1}`)
+	tests := map[string]string{
+		"missing": "",
+		"not hex": "zz",
+		"wrong":   hex.EncodeToString(ed25519.Sign(otherKey, append([]byte("1700000000"), body...))),
+	}
+
+	for name, signature := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/discord/interactions", bytes.NewReader(body))
+			req.Header.Set(TimestampHeader, "1700000000")
+			req.Header.Set(SignatureHeader, signature)
+			w := httptest.NewRecorder()
+
+			h.HandleInteractions(w, req)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("expected 401, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestHandleInteractions_CheckIn(t *testing.T) {
+	h := newTestHandler(t)
+
+	_, resp := h.post(t, command(CommandCheckIn, map[string]any{"mood": 8, "note": "slept well"}))
+
+	if resp.Type != ResponseChannelMessage || resp.Data.Flags != FlagEphemeral {
+		t.Fatalf("expected an ephemeral message, got %+v", resp)
+	}
+	if !strings.Contains(resp.Data.Content, "8/10 (content)") {
+		t.Errorf("unexpected content %q", resp.Data.Content)
+	}
+
+	if len(h.checkIns.created) != 1 {
+		t.Fatalf("expected one check-in, got %d", len(h.checkIns.created))
+	}
+	created := h.checkIns.created[0]
+	if created.PlatformUserID != "discord:42" || created.MoodScore != 8 || created.Description != "slept well" {
+		t.Errorf("unexpected check-in %+v", created)
+	}
+}
+
+func TestHandleInteractions_MoodStats(t *testing.T) {
+	t.Run("new user", func(t *testing.T) {
+		h := newTestHandler(t)
+		h.checkIns.stats, h.checkIns.statsErr = nil, fmt.Errorf("user not found: %w", user.ErrUserNotFound)
+
+		_, resp := h.post(t, command(CommandMoodStats, nil))
+		if resp.Type != ResponseChannelMessage || resp.Data.Content != "Welcome!" {
+			t.Errorf("expected the welcome insight, got %+v", resp)
+		}
+	})
+
+	t.Run("with check-ins", func(t *testing.T) {
+		h := newTestHandler(t)
+		h.checkIns.stats = &checkin.CheckInStats{TotalCheckIns: 5, AverageMoodScore: 6.4, MoodTrend: "improving"}
+
+		_, resp := h.post(t, command(CommandMoodStats, map[string]any{"days": 30}))
+		if !strings.HasPrefix(resp.Data.Content, "**Last 30 days:** 5 check-ins, average mood 6.4/10.") {
+			t.Errorf("unexpected content %q", resp.Data.Content)
+		}
+	})
+}
+
+func TestHandleInteractions_ReflectIsDeferred(t *testing.T) {
+	h := newTestHandler(t)
+
+	_, resp := h.post(t, command(CommandReflect, map[string]any{"text": "I finally rested today"}))
+	if resp.Type != ResponseDeferredChannelMessage || resp.Data.Flags != FlagEphemeral {
+		t.Fatalf("expected an ephemeral deferred response, got %+v", resp)
+	}
+
+	if len(h.reflections.created) != 1 || h.reflections.created[0].Content != "I finally rested today" {
+		t.Fatalf("unexpected reflections %+v", h.reflections.created)
+	}
+
+	if len(h.fake.requests) != 1 {
+		t.Fatalf("expected the response to be edited once, got %+v", h.fake.requests)
+	}
+	edit := h.fake.requests[0]
+	if edit.method != http.MethodPatch || edit.path != "/webhooks/app-1/interaction-token/messages/@original" {
+		t.Errorf("unexpected edit %s %s", edit.method, edit.path)
+	}
+	if !strings.Contains(edit.data.Content, "You're being kind to yourself.") || !strings.Contains(edit.data.Content, "**Themes:** rest") {
+		t.Errorf("unexpected edit content %q", edit.data.Content)
+	}
+}
+
+func TestHandleInteractions_DeferredFailure(t *testing.T) {
+	h := newTestHandler(t)
+	h.reflections.err = errors.New("database unavailable")
+
+	h.post(t, command(CommandReflect, map[string]any{"text": "today was hard"}))
+
+	if len(h.fake.requests) != 1 || !strings.HasPrefix(h.fake.requests[0].data.Content, "Sorry") {
+		t.Errorf("expected an apology in place of the deferred response, got %+v", h.fake.requests)
+	}
+}
+
+func TestHandleInteractions_ChatInDirectMessage(t *testing.T) {
+	h := newTestHandler(t)
+	h.conversations.response = strings.Repeat("word ", 500)
+
+	interaction := command(CommandChat, map[string]any{"message": "can we talk?"})
+	interaction.GuildID, interaction.Member = "", nil
+	interaction.User = &User{ID: "42", Username: "ada"}
+
+	_, resp := h.post(t, interaction)
+	if resp.Type != ResponseDeferredChannelMessage || resp.Data.Flags != 0 {
+		t.Fatalf("expected a public deferred response in a DM, got %+v", resp)
+	}
+
+	if len(h.conversations.requests) != 1 {
+		t.Fatalf("expected one processed message, got %d", len(h.conversations.requests))
+	}
+	req := h.conversations.requests[0]
+	if req.PlatformUserID != "discord:42" || req.ChannelID != "chan-1" || req.Message != "can we talk?" || req.MessageID != "int-1" {
+		t.Errorf("unexpected chat request %+v", req)
+	}
+
+	// a 2500 character reply is split into the edited response and one follow-up
+	if len(h.fake.requests) != 2 || h.fake.requests[1].method != http.MethodPost || h.fake.requests[1].path != "/webhooks/app-1/interaction-token" {
+		t.Fatalf("expected an edit and a follow-up, got %+v", h.fake.requests)
+	}
+	for _, request := range h.fake.requests {
+		if len(request.data.Content) > maxContentLength {
+			t.Errorf("message of %d characters exceeds the limit", len(request.data.Content))
+		}
+	}
+}
+
+func TestClient_RegisterCommands(t *testing.T) {
+	fake, client := newFakeDiscord(t)
+
+	if err := client.RegisterCommands(t.Context(), "app-1", Commands()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.requests) != 1 || fake.requests[0].method != http.MethodPut ||
+		fake.requests[0].path != "/applications/app-1/commands" || fake.requests[0].auth != "Bot bot-token" {
+		t.Errorf("unexpected request %+v", fake.requests)
+	}
+}
@@ -0,0 +1,90 @@
+package discord
+
+import "encoding/json"
+
+// interaction types
+const (
+	InteractionPing               = 1
+	InteractionApplicationCommand = 2
+)
+
+// interaction response types
+const (
+	ResponsePong                   = 1
+	ResponseChannelMessage         = 4
+	ResponseDeferredChannelMessage = 5
+)
+
+// FlagEphemeral shows a response only to the user who ran the command
+const FlagEphemeral = 64
+
+// application command option types
+const (
+	OptionString  = 3
+	OptionInteger = 4
+)
+
+// Interaction is the body Discord posts to the interactions endpoint
+type Interaction struct {
+	ID            string       `json:"id"`
+	ApplicationID string       `json:"application_id"`
+	Type          int          `json:"type"`
+	Data          *CommandData `json:"data,omitempty"`
+	GuildID       string       `json:"guild_id,omitempty"`
+	ChannelID     string       `json:"channel_id,omitempty"`
+	// Member is set in servers and User in direct messages
+	Member *Member `json:"member,omitempty"`
+	User   *User   `json:"user,omitempty"`
+	Token  string  `json:"token"`
+}
+
+type CommandData struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Options []CommandOption `json:"options,omitempty"`
+}
+
+type CommandOption struct {
+	Name  string          `json:"name"`
+	Type  int             `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type Member struct {
+	User *User `json:"user"`
+}
+
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// InteractionResponse answers an interaction within three seconds
+type InteractionResponse struct {
+	Type int           `json:"type"`
+	Data *ResponseData `json:"data,omitempty"`
+}
+
+type ResponseData struct {
+	Content string `json:"content,omitempty"`
+	Flags   int    `json:"flags,omitempty"`
+}
+
+// ApplicationCommand defines a slash command for registration
+type ApplicationCommand struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Options     []CommandOptionDef `json:"options,omitempty"`
+	// Contexts lets the command run in servers (0), bot DMs (1) and private channels (2)
+	Contexts []int `json:"contexts,omitempty"`
+}
+
+type CommandOptionDef struct {
+	Type        int    `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required,omitempty"`
+	MinValue    *int   `json:"min_value,omitempty"`
+	MaxValue    *int   `json:"max_value,omitempty"`
+	MaxLength   int    `json:"max_length,omitempty"`
+}
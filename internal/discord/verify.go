@@ -0,0 +1,38 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// headers Discord signs interactions with
+const (
+	SignatureHeader = "X-Signature-Ed25519"
+	TimestampHeader = "X-Signature-Timestamp"
+)
+
+var ErrInvalidSignature = errors.New("invalid discord signature")
+
+// ParsePublicKey decodes the application's hex encoded public key
+func ParsePublicKey(value string) (ed25519.PublicKey, error) {
+	key, err := hex.DecodeString(value)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("discord public key must be %d hex encoded bytes", ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Verify checks the Ed25519 signature Discord made over the timestamp followed by the body
+func Verify(publicKey ed25519.PublicKey, timestamp, signature string, body []byte) error {
+	decoded, err := hex.DecodeString(signature)
+	if err != nil || len(decoded) != ed25519.SignatureSize || timestamp == "" {
+		return ErrInvalidSignature
+	}
+
+	if !ed25519.Verify(publicKey, append([]byte(timestamp), body...), decoded) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
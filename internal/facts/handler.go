@@ -0,0 +1,100 @@
+package facts
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/zjoart/eunoia/internal/user"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+type Handler struct {
+	service ServiceInterface
+}
+
+func NewHandler(service ServiceInterface) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) HandleListFacts(w http.ResponseWriter, r *http.Request) {
+	platformUserID := mux.Vars(r)["platform_user_id"]
+
+	facts, err := h.service.ListFacts(platformUserID)
+	if err != nil {
+		h.sendServiceError(w, err, "failed to list facts")
+		return
+	}
+
+	if facts == nil {
+		facts = []*Fact{}
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{
+		"facts": facts,
+	})
+}
+
+func (h *Handler) HandleUpdateFact(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req UpdateFactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendJSONError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	if req.Content == "" {
+		h.sendJSONError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+
+	if err := h.service.UpdateFact(vars["platform_user_id"], vars["fact_id"], &req); err != nil {
+		h.sendServiceError(w, err, "failed to update fact")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]string{
+		"status": "updated",
+	})
+}
+
+func (h *Handler) HandleDeleteFact(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := h.service.DeleteFact(vars["platform_user_id"], vars["fact_id"]); err != nil {
+		h.sendServiceError(w, err, "failed to delete fact")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) sendServiceError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		h.sendJSONError(w, http.StatusNotFound, "user not found")
+	case errors.Is(err, ErrFactNotFound):
+		h.sendJSONError(w, http.StatusNotFound, "fact not found")
+	case errors.Is(err, ErrInvalidFact):
+		h.sendJSONError(w, http.StatusBadRequest, err.Error())
+	default:
+		logger.Error(message, logger.WithError(err))
+		h.sendJSONError(w, http.StatusInternalServerError, message)
+	}
+}
+
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+func (h *Handler) sendJSONError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,139 @@
+package facts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/zjoart/eunoia/internal/user"
+)
+
+func newTestRouter(handler *Handler) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/users/{platform_user_id}/facts", handler.HandleListFacts).Methods("GET")
+	router.HandleFunc("/api/v1/users/{platform_user_id}/facts/{fact_id}", handler.HandleUpdateFact).Methods("PUT")
+	router.HandleFunc("/api/v1/users/{platform_user_id}/facts/{fact_id}", handler.HandleDeleteFact).Methods("DELETE")
+	return router
+}
+
+func TestHandleListFacts(t *testing.T) {
+	mockService := &MockService{
+		ListFactsFunc: func(platformUserID string) ([]*Fact, error) {
+			return []*Fact{{ID: "fact-1", Category: CategoryPerson, Content: "Has a sister named Ada"}}, nil
+		},
+	}
+	router := newTestRouter(NewHandler(mockService))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/user-123/facts", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp struct {
+		Facts []*Fact `json:"facts"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(resp.Facts) != 1 || resp.Facts[0].ID != "fact-1" {
+		t.Errorf("expected one fact 'fact-1', got %v", resp.Facts)
+	}
+}
+
+func TestHandleListFacts_UnknownUser(t *testing.T) {
+	mockService := &MockService{
+		ListFactsFunc: func(platformUserID string) ([]*Fact, error) {
+			return nil, fmt.Errorf("user not found: %w", user.ErrUserNotFound)
+		},
+	}
+	router := newTestRouter(NewHandler(mockService))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/ghost/facts", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestHandleUpdateFact_InvalidCategory(t *testing.T) {
+	mockService := &MockService{
+		UpdateFactFunc: func(platformUserID, factID string, req *UpdateFactRequest) error {
+			return fmt.Errorf("%w: unknown category %q", ErrInvalidFact, req.Category)
+		},
+	}
+	router := newTestRouter(NewHandler(mockService))
+
+	body, _ := json.Marshal(UpdateFactRequest{Category: "zodiac", Content: "Is a Leo"})
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/user-123/facts/fact-1", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestHandleDeleteFact(t *testing.T) {
+	var deletedID string
+	mockService := &MockService{
+		DeleteFactFunc: func(platformUserID, factID string) error {
+			deletedID = factID
+			return nil
+		},
+	}
+	router := newTestRouter(NewHandler(mockService))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/users/user-123/facts/fact-1", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+
+	if deletedID != "fact-1" {
+		t.Errorf("expected fact 'fact-1' to be deleted, got '%s'", deletedID)
+	}
+}
+
+// MockService implements the service interface for testing
+type MockService struct {
+	ListFactsFunc  func(string) ([]*Fact, error)
+	UpdateFactFunc func(string, string, *UpdateFactRequest) error
+	DeleteFactFunc func(string, string) error
+}
+
+func (m *MockService) ListFacts(platformUserID string) ([]*Fact, error) {
+	if m.ListFactsFunc != nil {
+		return m.ListFactsFunc(platformUserID)
+	}
+	return nil, nil
+}
+
+func (m *MockService) UpdateFact(platformUserID, factID string, req *UpdateFactRequest) error {
+	if m.UpdateFactFunc != nil {
+		return m.UpdateFactFunc(platformUserID, factID, req)
+	}
+	return nil
+}
+
+func (m *MockService) DeleteFact(platformUserID, factID string) error {
+	if m.DeleteFactFunc != nil {
+		return m.DeleteFactFunc(platformUserID, factID)
+	}
+	return nil
+}
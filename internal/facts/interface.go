@@ -0,0 +1,8 @@
+package facts
+
+// ServiceInterface defines the methods needed by the handler
+type ServiceInterface interface {
+	ListFacts(platformUserID string) ([]*Fact, error)
+	UpdateFact(platformUserID, factID string, req *UpdateFactRequest) error
+	DeleteFact(platformUserID, factID string) error
+}
@@ -0,0 +1,33 @@
+package facts
+
+import "time"
+
+const (
+	CategoryName           = "name"
+	CategoryPronouns       = "pronouns"
+	CategoryPerson         = "person"
+	CategorySituation      = "situation"
+	CategoryCopingStrategy = "coping_strategy"
+	CategoryOther          = "other"
+)
+
+type Fact struct {
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	Category        string    `json:"category"`
+	Content         string    `json:"content"`
+	SourceMessageID string    `json:"source_message_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type UpdateFactRequest struct {
+	Category string `json:"category"`
+	Content  string `json:"content"`
+}
+
+// extractedFact is the shape the model returns during extraction
+type extractedFact struct {
+	Category string `json:"category"`
+	Content  string `json:"content"`
+}
@@ -0,0 +1,96 @@
+package facts
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateFact(fact *Fact) error {
+	query := `INSERT INTO user_facts (id, user_id, category, content, source_message_id, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, fact.ID, fact.UserID, fact.Category, fact.Content,
+		nullString(fact.SourceMessageID), fact.CreatedAt, fact.UpdatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetFactsByUserID(userID string) ([]*Fact, error) {
+	query := `SELECT id, user_id, category, content, source_message_id, created_at, updated_at
+			  FROM user_facts
+			  WHERE user_id = ?
+			  ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var facts []*Fact
+	for rows.Next() {
+		fact := &Fact{}
+		var sourceMessageID sql.NullString
+		err := rows.Scan(&fact.ID, &fact.UserID, &fact.Category, &fact.Content,
+			&sourceMessageID, &fact.CreatedAt, &fact.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if sourceMessageID.Valid {
+			fact.SourceMessageID = sourceMessageID.String
+		}
+		facts = append(facts, fact)
+	}
+
+	return facts, nil
+}
+
+// UpdateFact changes a fact's category and content, returning false when the user has no such fact
+func (r *Repository) UpdateFact(userID, factID, category, content string) (bool, error) {
+	query := `UPDATE user_facts SET category = ?, content = ?, updated_at = ? WHERE id = ? AND user_id = ?`
+
+	result, err := r.db.Exec(query, category, content, time.Now(), factID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteFact removes a fact, returning false when the user has no such fact
+func (r *Repository) DeleteFact(userID, factID string) (bool, error) {
+	query := `DELETE FROM user_facts WHERE id = ? AND user_id = ?`
+
+	result, err := r.db.Exec(query, factID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,111 @@
+package facts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCreateFact(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	fact := &Fact{
+		ID:              "fact-123",
+		UserID:          "user-456",
+		Category:        CategoryPerson,
+		Content:         "Has a sister named Ada",
+		SourceMessageID: "msg-789",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	mock.ExpectExec("INSERT INTO user_facts").
+		WithArgs(fact.ID, fact.UserID, fact.Category, fact.Content,
+			nullString(fact.SourceMessageID), fact.CreatedAt, fact.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.CreateFact(fact)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetFactsByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	userID := "user-456"
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "category", "content", "source_message_id", "created_at", "updated_at"}).
+		AddRow("fact-1", userID, CategoryName, "Prefers to be called Jo", "msg-1", now, now).
+		AddRow("fact-2", userID, CategoryCopingStrategy, "Evening walks help", nil, now, now)
+
+	mock.ExpectQuery("SELECT (.+) FROM user_facts").
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	facts, err := repo.GetFactsByUserID(userID)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if len(facts) != 2 {
+		t.Fatalf("expected 2 facts, got %d", len(facts))
+	}
+
+	if facts[0].SourceMessageID != "msg-1" {
+		t.Errorf("expected source message 'msg-1', got '%s'", facts[0].SourceMessageID)
+	}
+
+	if facts[1].SourceMessageID != "" {
+		t.Errorf("expected empty source message, got '%s'", facts[1].SourceMessageID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteFact_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	mock.ExpectExec("DELETE FROM user_facts").
+		WithArgs("fact-1", "user-456").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	deleted, err := repo.DeleteFact("user-456", "fact-1")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if deleted {
+		t.Error("expected no fact to be deleted")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package facts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/user"
+	"github.com/zjoart/eunoia/pkg/id"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+var (
+	ErrFactNotFound = errors.New("fact not found")
+	ErrInvalidFact  = errors.New("invalid fact")
+)
+
+var validCategories = map[string]bool{
+	CategoryName:           true,
+	CategoryPronouns:       true,
+	CategoryPerson:         true,
+	CategorySituation:      true,
+	CategoryCopingStrategy: true,
+	CategoryOther:          true,
+}
+
+// categories a user only has one of, so a newer fact replaces the older one
+var singleValueCategories = map[string]bool{
+	CategoryName:     true,
+	CategoryPronouns: true,
+}
+
+const minExtractionWords = 4
+
+type Service struct {
+	repo          *Repository
+	userRepo      *user.Repository
+	geminiService *agent.GeminiService
+}
+
+func NewService(repo *Repository, userRepo *user.Repository, geminiService *agent.GeminiService) *Service {
+	return &Service{
+		repo:          repo,
+		userRepo:      userRepo,
+		geminiService: geminiService,
+	}
+}
+
+// ExtractFacts asks the model for durable facts in a user message and stores any new ones
+func (s *Service) ExtractFacts(userID, sourceMessageID, message string) ([]*Fact, error) {
+	if len(strings.Fields(message)) < minExtractionWords {
+		return nil, nil
+	}
+
+	existing, err := s.repo.GetFactsByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load facts: %w", err)
+	}
+
+	extracted, err := s.extract(userID, message)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []*Fact
+	for _, candidate := range extracted {
+		category := strings.ToLower(strings.TrimSpace(candidate.Category))
+		content := strings.TrimSpace(candidate.Content)
+		if content == "" || !validCategories[category] || isKnownFact(existing, content) {
+			continue
+		}
+
+		if singleValueCategories[category] {
+			s.replaceCategory(userID, category, existing)
+		}
+
+		fact := &Fact{
+			ID:              id.Generate(),
+			UserID:          userID,
+			Category:        category,
+			Content:         content,
+			SourceMessageID: sourceMessageID,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+
+		if err := s.repo.CreateFact(fact); err != nil {
+			return created, fmt.Errorf("failed to save fact: %w", err)
+		}
+
+		existing = append(existing, fact)
+		created = append(created, fact)
+	}
+
+	if len(created) > 0 {
+		logger.Info("learned user facts", logger.Fields{
+			"user_id": userID,
+			"count":   len(created),
+		})
+	}
+
+	return created, nil
+}
+
+func (s *Service) extract(userID, message string) ([]extractedFact, error) {
+	systemPrompt := `You extract durable personal facts from a message someone sent to a wellbeing companion.
+
+Only extract facts worth remembering across conversations:
+- name: what they want to be called
+- pronouns: their pronouns
+- person: important people in their life and who they are
+- situation: ongoing circumstances (a new job, an illness, a move, exams)
+- coping_strategy: things they said help them feel better
+- other: any other lasting preference they asked to be remembered
+
+Ignore passing moods and small talk. Write each fact as a short third-person statement.
+Respond with only a JSON array like [{"category": "person", "content": "Has a sister named Ada"}], or [] if there is nothing to remember.`
+
+	response, err := s.geminiService.For(agent.PurposeFactExtraction, userID).GenerateContent(systemPrompt, message, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract facts: %w", err)
+	}
+
+	return parseExtractedFacts(response)
+}
+
+func parseExtractedFacts(response string) ([]extractedFact, error) {
+	response = strings.TrimSpace(response)
+	response = strings.TrimPrefix(response, "```json")
+	response = strings.TrimPrefix(response, "```")
+	response = strings.TrimSuffix(response, "```")
+	response = strings.TrimSpace(response)
+
+	var extracted []extractedFact
+	if err := json.Unmarshal([]byte(response), &extracted); err != nil {
+		return nil, fmt.Errorf("failed to parse extracted facts: %w", err)
+	}
+
+	return extracted, nil
+}
+
+func (s *Service) replaceCategory(userID, category string, existing []*Fact) {
+	for _, fact := range existing {
+		if fact.Category != category {
+			continue
+		}
+		if _, err := s.repo.DeleteFact(userID, fact.ID); err != nil {
+			logger.Warn("failed to replace fact", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{"fact_id": fact.ID},
+			))
+		}
+	}
+}
+
+func isKnownFact(existing []*Fact, content string) bool {
+	for _, fact := range existing {
+		if strings.EqualFold(fact.Content, content) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFactsForUser returns everything known about a user by internal ID, newest first
+func (s *Service) GetFactsForUser(userID string) ([]*Fact, error) {
+	return s.repo.GetFactsByUserID(userID)
+}
+
+func (s *Service) ListFacts(platformUserID string) ([]*Fact, error) {
+	userRecord, err := s.userRepo.GetUserByPlatformID(platformUserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	return s.repo.GetFactsByUserID(userRecord.ID)
+}
+
+func (s *Service) UpdateFact(platformUserID, factID string, req *UpdateFactRequest) error {
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return fmt.Errorf("%w: content cannot be empty", ErrInvalidFact)
+	}
+
+	category := strings.ToLower(strings.TrimSpace(req.Category))
+	if category == "" {
+		category = CategoryOther
+	}
+	if !validCategories[category] {
+		return fmt.Errorf("%w: unknown category %q", ErrInvalidFact, category)
+	}
+
+	userRecord, err := s.userRepo.GetUserByPlatformID(platformUserID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	updated, err := s.repo.UpdateFact(userRecord.ID, factID, category, content)
+	if err != nil {
+		return fmt.Errorf("failed to update fact: %w", err)
+	}
+	if !updated {
+		return ErrFactNotFound
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteFact(platformUserID, factID string) error {
+	userRecord, err := s.userRepo.GetUserByPlatformID(platformUserID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	return s.deleteFact(userRecord.ID, factID)
+}
+
+func (s *Service) deleteFact(userID, factID string) error {
+	deleted, err := s.repo.DeleteFact(userID, factID)
+	if err != nil {
+		return fmt.Errorf("failed to delete fact: %w", err)
+	}
+	if !deleted {
+		return ErrFactNotFound
+	}
+
+	return nil
+}
+
+// FindExact returns the fact whose content is the given text, ignoring case and closing punctuation.
+// Forget requests only ever name one fact this way, so a passing mention never matches.
+func (s *Service) FindExact(userID, text string) (*Fact, error) {
+	facts, err := s.repo.GetFactsByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load facts: %w", err)
+	}
+
+	fact := exactMatch(facts, text)
+	if fact == nil {
+		return nil, ErrFactNotFound
+	}
+
+	return fact, nil
+}
+
+// ForgetFact deletes one fact by internal user ID, once the user has confirmed it
+func (s *Service) ForgetFact(userID, factID string) error {
+	return s.deleteFact(userID, factID)
+}
+
+func exactMatch(existing []*Fact, text string) *Fact {
+	text = normalizeFactText(text)
+	if text == "" {
+		return nil
+	}
+
+	for _, fact := range existing {
+		if normalizeFactText(fact.Content) == text {
+			return fact
+		}
+	}
+	return nil
+}
+
+func normalizeFactText(text string) string {
+	return strings.Join(strings.Fields(strings.Trim(strings.ToLower(text), ".!?\"' ")), " ")
+}
@@ -0,0 +1,64 @@
+package facts
+
+import "testing"
+
+func TestParseExtractedFacts(t *testing.T) {
+	response := "```json\n[{\"category\": \"person\", \"content\": \"Has a sister named Ada\"}, {\"category\": \"coping_strategy\", \"content\": \"Journaling before bed helps\"}]\n```"
+
+	extracted, err := parseExtractedFacts(response)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(extracted) != 2 {
+		t.Fatalf("expected 2 facts, got %d", len(extracted))
+	}
+
+	if extracted[1].Category != CategoryCopingStrategy {
+		t.Errorf("expected category '%s', got '%s'", CategoryCopingStrategy, extracted[1].Category)
+	}
+}
+
+func TestParseExtractedFacts_Empty(t *testing.T) {
+	extracted, err := parseExtractedFacts("[]")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(extracted) != 0 {
+		t.Errorf("expected no facts, got %d", len(extracted))
+	}
+
+	if _, err := parseExtractedFacts("Nothing to remember here."); err == nil {
+		t.Error("expected error for non-JSON response")
+	}
+}
+
+func TestIsKnownFact(t *testing.T) {
+	existing := []*Fact{{Content: "Has a sister named Ada"}}
+
+	if !isKnownFact(existing, "has a sister named ada") {
+		t.Error("expected case-insensitive match to be known")
+	}
+
+	if isKnownFact(existing, "Has a brother named Tunde") {
+		t.Error("expected different fact to be unknown")
+	}
+}
+
+func TestExactMatch(t *testing.T) {
+	existing := []*Fact{
+		{ID: "f1", Content: "Has a sister named Ada"},
+		{ID: "f2", Content: "Sister lives in Lagos"},
+	}
+
+	if fact := exactMatch(existing, "  has a sister  named Ada. "); fact == nil || fact.ID != "f1" {
+		t.Errorf("expected f1 to match ignoring case, spacing and punctuation, got %+v", fact)
+	}
+
+	for _, text := range []string{"sister", "a sister named ada and her kids", ""} {
+		if fact := exactMatch(existing, text); fact != nil {
+			t.Errorf("expected %q not to match, got %+v", text, fact)
+		}
+	}
+}
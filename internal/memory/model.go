@@ -0,0 +1,24 @@
+package memory
+
+import "time"
+
+const (
+	SourceReflection = "reflection"
+	SourceMessage    = "message"
+)
+
+type Memory struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	SourceType string    `json:"source_type"`
+	SourceID   string    `json:"source_id"`
+	Content    string    `json:"content"`
+	Embedding  []float32 `json:"-"`
+	Model      string    `json:"model"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type RetrievedMemory struct {
+	Memory     *Memory `json:"memory"`
+	Similarity float64 `json:"similarity"`
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) SaveMemory(memory *Memory) error {
+	query := `INSERT INTO memory_embeddings (id, user_id, source_type, source_id, content, embedding, dimensions, model, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			  ON DUPLICATE KEY UPDATE content = VALUES(content), embedding = VALUES(embedding),
+			  dimensions = VALUES(dimensions), model = VALUES(model)`
+
+	_, err := r.db.Exec(query, memory.ID, memory.UserID, memory.SourceType, memory.SourceID, memory.Content,
+		encodeVector(memory.Embedding), len(memory.Embedding), memory.Model, memory.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetMemoriesBefore returns the user's most recent memories created before the given time
+func (r *Repository) GetMemoriesBefore(userID string, before time.Time, limit int) ([]*Memory, error) {
+	query := `SELECT id, user_id, source_type, source_id, content, embedding, model, created_at
+			  FROM memory_embeddings
+			  WHERE user_id = ? AND created_at < ?
+			  ORDER BY created_at DESC
+			  LIMIT ?`
+
+	rows, err := r.db.Query(query, userID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []*Memory
+	for rows.Next() {
+		memory := &Memory{}
+		var embedding []byte
+		err := rows.Scan(&memory.ID, &memory.UserID, &memory.SourceType, &memory.SourceID,
+			&memory.Content, &embedding, &memory.Model, &memory.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		memory.Embedding, err = decodeVector(embedding)
+		if err != nil {
+			return nil, err
+		}
+		memories = append(memories, memory)
+	}
+
+	return memories, nil
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSaveMemory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	memory := &Memory{
+		ID:         "memory-123",
+		UserID:     "user-456",
+		SourceType: SourceReflection,
+		SourceID:   "ref-1",
+		Content:    "I have been worried about my sister's health",
+		Embedding:  []float32{0.1, 0.2, 0.3},
+		Model:      "text-embedding-004",
+		CreatedAt:  time.Now(),
+	}
+
+	mock.ExpectExec("INSERT INTO memory_embeddings").
+		WithArgs(memory.ID, memory.UserID, memory.SourceType, memory.SourceID, memory.Content,
+			encodeVector(memory.Embedding), 3, memory.Model, memory.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.SaveMemory(memory)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetMemoriesBefore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	userID := "user-456"
+	now := time.Now()
+	before := now.Add(-30 * time.Minute)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "source_type", "source_id", "content", "embedding", "model", "created_at"}).
+		AddRow("memory-1", userID, SourceMessage, "msg-1", "Work has been overwhelming lately", encodeVector([]float32{1, 0, 0.5}), "text-embedding-004", now)
+
+	mock.ExpectQuery("SELECT (.+) FROM memory_embeddings").
+		WithArgs(userID, before, 100).
+		WillReturnRows(rows)
+
+	memories, err := repo.GetMemoriesBefore(userID, before, 100)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if len(memories) != 1 {
+		t.Fatalf("expected 1 memory, got %d", len(memories))
+	}
+
+	if len(memories[0].Embedding) != 3 || memories[0].Embedding[2] != 0.5 {
+		t.Errorf("expected decoded embedding [1 0 0.5], got %v", memories[0].Embedding)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
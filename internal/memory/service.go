@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/pkg/id"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+const (
+	// short messages like "ok thanks" carry no useful long-term signal
+	minMemoryWords = 5
+	// upper bound on vectors scanned per recall for the brute-force search
+	maxRecallCandidates = 2000
+	minSimilarity       = 0.55
+)
+
+type Service struct {
+	repo     *Repository
+	provider agent.EmbeddingProvider
+}
+
+func NewService(repo *Repository, provider agent.EmbeddingProvider) *Service {
+	return &Service{
+		repo:     repo,
+		provider: provider,
+	}
+}
+
+// Remember embeds content and stores it as a retrievable memory for the user
+func (s *Service) Remember(userID, sourceType, sourceID, content string, createdAt time.Time) error {
+	if len(strings.Fields(content)) < minMemoryWords {
+		return nil
+	}
+
+	embedding, err := s.provider.Embed(content)
+	if err != nil {
+		return fmt.Errorf("failed to embed memory: %w", err)
+	}
+
+	memory := &Memory{
+		ID:         id.Generate(),
+		UserID:     userID,
+		SourceType: sourceType,
+		SourceID:   sourceID,
+		Content:    content,
+		Embedding:  embedding,
+		Model:      s.provider.EmbeddingModel(),
+		CreatedAt:  createdAt,
+	}
+
+	if err := s.repo.SaveMemory(memory); err != nil {
+		return fmt.Errorf("failed to save memory: %w", err)
+	}
+
+	return nil
+}
+
+// Recall returns up to k memories created before the given time that are most similar to the query
+func (s *Service) Recall(userID, query string, before time.Time, k int) ([]*RetrievedMemory, error) {
+	if strings.TrimSpace(query) == "" || k <= 0 {
+		return nil, nil
+	}
+
+	candidates, err := s.repo.GetMemoriesBefore(userID, before, maxRecallCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load memories: %w", err)
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	queryEmbedding, err := s.provider.Embed(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	return rankMemories(queryEmbedding, s.provider.EmbeddingModel(), candidates, k), nil
+}
+
+func rankMemories(queryEmbedding []float32, model string, candidates []*Memory, k int) []*RetrievedMemory {
+	var ranked []*RetrievedMemory
+	seen := make(map[string]bool)
+
+	for _, candidate := range candidates {
+		// vectors from a different model live in a different space
+		if candidate.Model != model {
+			continue
+		}
+
+		similarity := cosineSimilarity(queryEmbedding, candidate.Embedding)
+		if similarity < minSimilarity {
+			continue
+		}
+
+		ranked = append(ranked, &RetrievedMemory{
+			Memory:     candidate,
+			Similarity: similarity,
+		})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Similarity > ranked[j].Similarity
+	})
+
+	var top []*RetrievedMemory
+	for _, r := range ranked {
+		// a message saved as a reflection is stored under both sources
+		key := strings.TrimSpace(r.Memory.Content)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		top = append(top, r)
+		if len(top) == k {
+			break
+		}
+	}
+
+	logger.Debug("ranked memories", logger.Fields{
+		"candidates": len(candidates),
+		"returned":   len(top),
+	})
+
+	return top
+}
@@ -0,0 +1,44 @@
+package memory
+
+import "testing"
+
+func TestRankMemories(t *testing.T) {
+	model := "text-embedding-004"
+	query := []float32{1, 0, 0}
+
+	candidates := []*Memory{
+		{ID: "unrelated", Content: "The weather was nice", Embedding: []float32{0, 1, 0}, Model: model},
+		{ID: "close", Content: "My sister called me", Embedding: []float32{0.9, 0.1, 0}, Model: model},
+		{ID: "closest", Content: "I miss my sister", Embedding: []float32{1, 0, 0.05}, Model: model},
+		{ID: "duplicate", Content: "I miss my sister", Embedding: []float32{1, 0, 0.05}, Model: model},
+		{ID: "other-model", Content: "Sister visit", Embedding: []float32{1, 0, 0}, Model: "other"},
+	}
+
+	ranked := rankMemories(query, model, candidates, 5)
+
+	if len(ranked) != 2 {
+		t.Fatalf("expected 2 memories, got %d", len(ranked))
+	}
+
+	if ranked[0].Memory.ID != "closest" {
+		t.Errorf("expected 'closest' first, got '%s'", ranked[0].Memory.ID)
+	}
+
+	if ranked[1].Memory.ID != "close" {
+		t.Errorf("expected 'close' second, got '%s'", ranked[1].Memory.ID)
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	if sim := cosineSimilarity([]float32{1, 2, 3}, []float32{1, 2, 3}); sim < 0.999 {
+		t.Errorf("expected identical vectors to have similarity 1, got %f", sim)
+	}
+
+	if sim := cosineSimilarity([]float32{1, 0}, []float32{0, 1}); sim != 0 {
+		t.Errorf("expected orthogonal vectors to have similarity 0, got %f", sim)
+	}
+
+	if sim := cosineSimilarity([]float32{1, 0}, []float32{1, 0, 0}); sim != 0 {
+		t.Errorf("expected mismatched dimensions to have similarity 0, got %f", sim)
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// APIKeyHeader is the header alternative to an Authorization bearer token
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyMiddleware requires the key as a bearer token or in the X-API-Key header; an empty key allows every request
+func APIKeyMiddleware(apiKey string) Middleware {
+	return func(next http.Handler) http.Handler {
+		if apiKey == "" {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subtle.ConstantTimeCompare([]byte(RequestAPIKey(r)), []byte(apiKey)) != 1 {
+				logger.Warn("rejected request with invalid API key", logger.Fields{
+					"path": r.URL.Path,
+				})
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestAPIKey returns the key a request carries as a bearer token or in the X-API-Key header
+func RequestAPIKey(r *http.Request) string {
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return bearer
+	}
+	return r.Header.Get(APIKeyHeader)
+}
@@ -0,0 +1,27 @@
+package push
+
+import "time"
+
+// Config is where and how to deliver a task's result
+type Config struct {
+	TaskID      string    `json:"task_id"`
+	ConfigID    string    `json:"config_id,omitempty"`
+	URL         string    `json:"url"`
+	Token       string    `json:"token,omitempty"`
+	AuthSchemes []string  `json:"auth_schemes,omitempty"`
+	Credentials string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Delivery records a single attempt to post a task to its push URL
+type Delivery struct {
+	ID           string    `json:"id"`
+	TaskID       string    `json:"task_id"`
+	URL          string    `json:"url"`
+	Attempt      int       `json:"attempt"`
+	StatusCode   int       `json:"status_code,omitempty"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	Delivered    bool      `json:"delivered"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,107 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// redirects followed before a delivery fails
+const maxRedirects = 3
+
+var errPrivateAddress = errors.New("push URL resolves to a private or local address")
+
+// sharedAddressSpace is the carrier-grade NAT range, private in practice but not reported by IsPrivate
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// publicAddress reports whether a push notification may be sent to addr. Loopback, private,
+// link-local (including cloud metadata endpoints), multicast and unspecified addresses are refused.
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// newPublicClient returns a client that only connects to public addresses. The check runs on the
+// address actually dialled, so it also covers redirects and a host that resolves differently later.
+func newPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !publicAddress(addrPort.Addr()) {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		// no proxy: the dialer would check the proxy's address instead of the push URL's
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: checkRedirect,
+	}
+}
+
+// newClient returns a client without the address check, for local development
+func newClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, CheckRedirect: checkRedirect}
+}
+
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if req.URL.Scheme != "https" && req.URL.Scheme != "http" {
+		return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+	}
+	return nil
+}
+
+// checkHost resolves a push URL's host when the config is set, so a private address is refused
+// up front rather than on every delivery
+func (s *Service) checkHost(host string) error {
+	if s.allowPrivate {
+		return nil
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !publicAddress(addr) {
+			return fmt.Errorf("%w: url must not point to a private or local address", ErrInvalidConfig)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.client.Timeout)
+	defer cancel()
+
+	addrs, err := s.lookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: url host could not be resolved", ErrInvalidConfig)
+	}
+	for _, addr := range addrs {
+		if !publicAddress(addr) {
+			return fmt.Errorf("%w: url must not point to a private or local address", ErrInvalidConfig)
+		}
+	}
+
+	return nil
+}
+
+func lookupHost(ctx context.Context, host string) ([]netip.Addr, error) {
+	return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+}
@@ -0,0 +1,88 @@
+package push
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// SaveConfig stores the push config for a task, replacing any earlier one
+func (r *Repository) SaveConfig(config *Config) error {
+	query := `INSERT INTO push_notification_configs (task_id, config_id, url, token, auth_schemes, credentials, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			  ON DUPLICATE KEY UPDATE config_id = VALUES(config_id), url = VALUES(url), token = VALUES(token),
+			  auth_schemes = VALUES(auth_schemes), credentials = VALUES(credentials), updated_at = VALUES(updated_at)`
+
+	schemes, err := json.Marshal(config.AuthSchemes)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(query, config.TaskID, nullString(config.ConfigID), config.URL, nullString(config.Token),
+		schemes, nullString(config.Credentials), config.CreatedAt, config.UpdatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetConfig returns nil when the task has no push config
+func (r *Repository) GetConfig(taskID string) (*Config, error) {
+	query := `SELECT task_id, config_id, url, token, auth_schemes, credentials, created_at, updated_at
+			  FROM push_notification_configs
+			  WHERE task_id = ?`
+
+	config := &Config{}
+	var configID, token, credentials sql.NullString
+	var schemes []byte
+	err := r.db.QueryRow(query, taskID).Scan(&config.TaskID, &configID, &config.URL, &token,
+		&schemes, &credentials, &config.CreatedAt, &config.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	config.ConfigID = configID.String
+	config.Token = token.String
+	config.Credentials = credentials.String
+
+	if len(schemes) > 0 {
+		if err := json.Unmarshal(schemes, &config.AuthSchemes); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+func (r *Repository) LogDelivery(delivery *Delivery) error {
+	query := `INSERT INTO push_deliveries (id, task_id, url, attempt, status_code, error_message, delivered, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, delivery.ID, delivery.TaskID, delivery.URL, delivery.Attempt,
+		sql.NullInt64{Int64: int64(delivery.StatusCode), Valid: delivery.StatusCode != 0},
+		nullString(delivery.ErrorMessage), delivery.Delivered, delivery.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
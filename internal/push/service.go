@@ -0,0 +1,282 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/a2a"
+	"github.com/zjoart/eunoia/pkg/id"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+var (
+	ErrInvalidConfig  = errors.New("invalid push notification config")
+	ErrConfigNotFound = errors.New("push notification config not found")
+)
+
+// headers sent with every push notification
+const (
+	TokenHeader     = "X-A2A-Notification-Token"
+	SignatureHeader = "X-Eunoia-Signature"
+	TimestampHeader = "X-Eunoia-Timestamp"
+)
+
+const (
+	maxTokenLength = 255
+	// wait before the second attempt, doubled after each failure
+	initialBackoff = time.Second
+)
+
+// supportedSchemes are sent as an Authorization header with the config's credentials. Clients may name
+// other schemes, such as Telex's TelexApiKey; those are stored but only the token header is sent.
+var supportedSchemes = map[string]string{
+	"bearer": "Bearer",
+	"basic":  "Basic",
+}
+
+type Service struct {
+	repo          *Repository
+	client        *http.Client
+	signingSecret string
+	maxAttempts   int
+	backoff       time.Duration
+	sleep         func(time.Duration)
+	now           func() time.Time
+	allowPrivate  bool
+	lookupHost    func(ctx context.Context, host string) ([]netip.Addr, error)
+}
+
+// NewService creates the push notifier; an empty signingSecret sends unsigned notifications
+func NewService(repo *Repository, signingSecret string, maxAttempts int, timeout time.Duration) *Service {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &Service{
+		repo:          repo,
+		client:        newPublicClient(timeout),
+		signingSecret: signingSecret,
+		maxAttempts:   maxAttempts,
+		backoff:       initialBackoff,
+		sleep:         time.Sleep,
+		now:           time.Now,
+		lookupHost:    lookupHost,
+	}
+}
+
+// AllowPrivateNetworks lets push URLs point to loopback and private addresses, for local development
+func (s *Service) AllowPrivateNetworks() {
+	s.allowPrivate = true
+	s.client = newClient(s.client.Timeout)
+}
+
+// SetConfig validates and stores where a task's result should be sent
+func (s *Service) SetConfig(taskID string, config *a2a.A2APushNotification) error {
+	if err := validateConfig(config); err != nil {
+		return err
+	}
+	parsed, _ := url.Parse(config.URL)
+	if err := s.checkHost(parsed.Hostname()); err != nil {
+		return err
+	}
+
+	now := s.now()
+	stored := &Config{
+		TaskID:    taskID,
+		ConfigID:  config.ID,
+		URL:       config.URL,
+		Token:     config.Token,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if config.Authentication != nil {
+		for _, scheme := range config.Authentication.Schemes {
+			if scheme = strings.TrimSpace(scheme); scheme != "" {
+				stored.AuthSchemes = append(stored.AuthSchemes, scheme)
+			}
+		}
+		stored.Credentials = config.Authentication.Credentials
+	}
+
+	if err := s.repo.SaveConfig(stored); err != nil {
+		return fmt.Errorf("failed to save push notification config: %w", err)
+	}
+
+	return nil
+}
+
+// GetConfig returns a task's push config without its credentials
+func (s *Service) GetConfig(taskID string) (*a2a.A2APushNotification, error) {
+	stored, err := s.repo.GetConfig(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get push notification config: %w", err)
+	}
+	if stored == nil {
+		return nil, ErrConfigNotFound
+	}
+
+	config := &a2a.A2APushNotification{
+		ID:    stored.ConfigID,
+		URL:   stored.URL,
+		Token: stored.Token,
+	}
+	if len(stored.AuthSchemes) > 0 {
+		config.Authentication = &a2a.A2APushAuthentication{Schemes: stored.AuthSchemes}
+	}
+
+	return config, nil
+}
+
+// Notify posts the task to its push URL, retrying server errors and network failures with backoff.
+// Tasks without a push config are skipped.
+func (s *Service) Notify(taskID string, result *a2a.A2AResult) error {
+	stored, err := s.repo.GetConfig(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get push notification config: %w", err)
+	}
+	if stored == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode task: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		statusCode, err := s.deliver(stored, body)
+
+		s.logDelivery(stored, attempt, statusCode, err)
+
+		if err == nil {
+			logger.Info("push notification delivered", logger.Fields{
+				"task_id": taskID,
+				"attempt": attempt,
+			})
+			return nil
+		}
+
+		lastErr = err
+		if errors.Is(err, errPrivateAddress) || !retryable(statusCode) || attempt == s.maxAttempts {
+			break
+		}
+
+		s.sleep(s.backoff << (attempt - 1))
+	}
+
+	return fmt.Errorf("push notification failed: %w", lastErr)
+}
+
+// deliver makes one POST, returning the response status (0 when no response arrived)
+func (s *Service) deliver(config *Config, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, config.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if config.Token != "" {
+		req.Header.Set(TokenHeader, config.Token)
+	}
+	if authorization := authorizationHeader(config); authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	if s.signingSecret != "" {
+		timestamp := strconv.FormatInt(s.now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, Sign(s.signingSecret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("push URL returned status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (s *Service) logDelivery(config *Config, attempt, statusCode int, deliveryErr error) {
+	delivery := &Delivery{
+		ID:         id.Generate(),
+		TaskID:     config.TaskID,
+		URL:        config.URL,
+		Attempt:    attempt,
+		StatusCode: statusCode,
+		Delivered:  deliveryErr == nil,
+		CreatedAt:  s.now(),
+	}
+	if deliveryErr != nil {
+		delivery.ErrorMessage = deliveryErr.Error()
+	}
+
+	if err := s.repo.LogDelivery(delivery); err != nil {
+		logger.Warn("failed to log push delivery", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"task_id": config.TaskID},
+		))
+	}
+}
+
+// Sign returns the signature header value for a body: HMAC-SHA256 of "timestamp.body", hex encoded
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// authorizationHeader uses the first scheme Eunoia knows how to send, or none
+func authorizationHeader(config *Config) string {
+	if config.Credentials == "" {
+		return ""
+	}
+	for _, scheme := range config.AuthSchemes {
+		if name, ok := supportedSchemes[strings.ToLower(scheme)]; ok {
+			return name + " " + config.Credentials
+		}
+	}
+	return ""
+}
+
+// retryable reports whether a failed attempt may succeed later; 0 means the request never got a response
+func retryable(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+func validateConfig(config *a2a.A2APushNotification) error {
+	if config == nil || config.URL == "" {
+		return fmt.Errorf("%w: url is required", ErrInvalidConfig)
+	}
+
+	parsed, err := url.Parse(config.URL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidConfig)
+	}
+
+	if len(config.Token) > maxTokenLength {
+		return fmt.Errorf("%w: token is too long", ErrInvalidConfig)
+	}
+
+	return nil
+}
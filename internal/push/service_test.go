@@ -0,0 +1,347 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
+var configColumns = []string{"task_id", "config_id", "url", "token", "auth_schemes", "credentials", "created_at", "updated_at"}
+
+func newTestService(t *testing.T, maxAttempts int) (*Service, sqlmock.Sqlmock, *[]time.Duration) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service := NewService(NewRepository(db), "secret", maxAttempts, time.Second)
+	// the test servers listen on loopback
+	service.AllowPrivateNetworks()
+	service.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	var waits []time.Duration
+	service.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	return service, mock, &waits
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *a2a.A2APushNotification
+		valid  bool
+	}{
+		{"valid", &a2a.A2APushNotification{URL: "https://example.com/hook", Token: "abc"}, true},
+		{"bearer auth", &a2a.A2APushNotification{URL: "https://example.com/hook",
+			Authentication: &a2a.A2APushAuthentication{Schemes: []string{"bearer"}, Credentials: "xyz"}}, true},
+		{"missing url", &a2a.A2APushNotification{}, false},
+		{"relative url", &a2a.A2APushNotification{URL: "/hook"}, false},
+		{"other scheme", &a2a.A2APushNotification{URL: "ftp://example.com/hook"}, false},
+		{"telex auth", &a2a.A2APushNotification{URL: "https://example.com/hook", Token: "abc",
+			Authentication: &a2a.A2APushAuthentication{Schemes: []string{"TelexApiKey"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateConfig(tt.config)
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNotify_SignsAndAuthenticates(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service, mock, _ := newTestService(t, 3)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM push_notification_configs").
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(configColumns).
+			AddRow("task-1", nil, server.URL, "client-token", []byte(`["Bearer"]`), "creds", now, now))
+	mock.ExpectExec("INSERT INTO push_deliveries").
+		WithArgs(sqlmock.AnyArg(), "task-1", server.URL, 1, int64(200), nil, true, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := service.Notify("task-1", &a2a.A2AResult{ID: "task-1", Kind: "task",
+		Status: a2a.A2ATaskStatus{State: a2a.TaskStateCompleted}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Header.Get(TokenHeader) != "client-token" {
+		t.Errorf("expected token header, got %q", received.Header.Get(TokenHeader))
+	}
+	if received.Header.Get("Authorization") != "Bearer creds" {
+		t.Errorf("expected bearer credentials, got %q", received.Header.Get("Authorization"))
+	}
+
+	timestamp := received.Header.Get(TimestampHeader)
+	if timestamp != "1700000000" {
+		t.Errorf("unexpected timestamp %q", timestamp)
+	}
+	if received.Header.Get(SignatureHeader) != Sign("secret", timestamp, body) {
+		t.Error("signature does not match the body")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestNotify_UnknownSchemeSendsOnlyTheToken(t *testing.T) {
+	var received *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service, mock, _ := newTestService(t, 1)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM push_notification_configs").
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(configColumns).
+			AddRow("task-1", nil, server.URL, "telex-push-token", []byte(`["TelexApiKey"]`), "creds", now, now))
+	mock.ExpectExec("INSERT INTO push_deliveries").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := service.Notify("task-1", &a2a.A2AResult{ID: "task-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Header.Get(TokenHeader) != "telex-push-token" {
+		t.Errorf("expected token header, got %q", received.Header.Get(TokenHeader))
+	}
+	if received.Header.Get("Authorization") != "" {
+		t.Errorf("expected no Authorization header for an unknown scheme, got %q", received.Header.Get("Authorization"))
+	}
+}
+
+func TestNotify_RetriesServerErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service, mock, waits := newTestService(t, 3)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM push_notification_configs").
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(configColumns).
+			AddRow("task-1", nil, server.URL, nil, nil, nil, now, now))
+	for attempt := 1; attempt <= 3; attempt++ {
+		mock.ExpectExec("INSERT INTO push_deliveries").
+			WithArgs(sqlmock.AnyArg(), "task-1", server.URL, attempt, sqlmock.AnyArg(), sqlmock.AnyArg(), attempt == 3, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	if err := service.Notify("task-1", &a2a.A2AResult{ID: "task-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+	if len(*waits) != 2 || (*waits)[0] != time.Second || (*waits)[1] != 2*time.Second {
+		t.Errorf("expected exponential backoff, got %v", *waits)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestNotify_DoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	service, mock, _ := newTestService(t, 3)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM push_notification_configs").
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(configColumns).
+			AddRow("task-1", nil, server.URL, nil, nil, nil, now, now))
+	mock.ExpectExec("INSERT INTO push_deliveries").
+		WithArgs(sqlmock.AnyArg(), "task-1", server.URL, 1, int64(401), sqlmock.AnyArg(), false, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := service.Notify("task-1", &a2a.A2AResult{ID: "task-1"}); err == nil {
+		t.Fatal("expected an error for a rejected notification")
+	}
+
+	if calls != 1 {
+		t.Errorf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestNotify_NoConfig(t *testing.T) {
+	service, mock, _ := newTestService(t, 3)
+
+	mock.ExpectQuery("SELECT (.+) FROM push_notification_configs").
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(configColumns))
+
+	if err := service.Notify("task-1", &a2a.A2AResult{ID: "task-1"}); err != nil {
+		t.Errorf("expected tasks without a push config to be skipped, got %v", err)
+	}
+}
+
+func TestGetConfig_HidesCredentials(t *testing.T) {
+	service, mock, _ := newTestService(t, 3)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM push_notification_configs").
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(configColumns).
+			AddRow("task-1", "cfg-1", "https://example.com/hook", "tok", []byte(`["Bearer"]`), "secret-creds", now, now))
+
+	config, err := service.GetConfig("task-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if config.ID != "cfg-1" || config.Token != "tok" {
+		t.Errorf("unexpected config %+v", config)
+	}
+	if config.Authentication == nil || config.Authentication.Credentials != "" {
+		t.Errorf("expected schemes without credentials, got %+v", config.Authentication)
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"100.64.0.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := publicAddress(netip.MustParseAddr(tt.addr)); got != tt.public {
+				t.Errorf("expected public=%v, got %v", tt.public, got)
+			}
+		})
+	}
+}
+
+func TestSetConfig_RejectsPrivateHosts(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := NewService(NewRepository(db), "", 1, time.Second)
+	service.lookupHost = func(ctx context.Context, host string) ([]netip.Addr, error) {
+		switch host {
+		case "internal.example.com":
+			return []netip.Addr{netip.MustParseAddr("93.184.216.34"), netip.MustParseAddr("10.0.0.5")}, nil
+		case "localhost":
+			return []netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	for _, pushURL := range []string{
+		"http://169.254.169.254/latest/meta-data",
+		"http://127.0.0.1:8080/hook",
+		"http://[::1]/hook",
+		"https://localhost/hook",
+		"https://internal.example.com/hook",
+		"https://unknown.example.com/hook",
+	} {
+		t.Run(pushURL, func(t *testing.T) {
+			err := service.SetConfig("task-1", &a2a.A2APushNotification{URL: pushURL})
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNotify_RefusesPrivateAddresses(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	// a config stored before its host started resolving to a private address
+	service := NewService(NewRepository(db), "", 3, time.Second)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM push_notification_configs").
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(configColumns).
+			AddRow("task-1", nil, server.URL, nil, nil, nil, now, now))
+	mock.ExpectExec("INSERT INTO push_deliveries").
+		WithArgs(sqlmock.AnyArg(), "task-1", server.URL, 1, nil, sqlmock.AnyArg(), false, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = service.Notify("task-1", &a2a.A2AResult{ID: "task-1"})
+	if !errors.Is(err, errPrivateAddress) {
+		t.Fatalf("expected errPrivateAddress, got %v", err)
+	}
+
+	if calls != 0 {
+		t.Errorf("expected the request never to reach the server, got %d calls", calls)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,347 @@
+package realtime
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/conversation"
+	"github.com/zjoart/eunoia/internal/conversation/platforms"
+	"github.com/zjoart/eunoia/internal/middleware"
+	"github.com/zjoart/eunoia/internal/reminder"
+	"github.com/zjoart/eunoia/internal/user"
+	"github.com/zjoart/eunoia/pkg/id"
+	"github.com/zjoart/eunoia/pkg/logger"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// how long a new connection has to send its auth frame
+	authTimeout = 10 * time.Second
+	// how long writing one frame may take before the client is considered gone
+	writeTimeout = 10 * time.Second
+	// largest frame accepted from the client
+	maxFrameBytes = 1 << 20
+	// messages a client may send while an earlier one is still being answered
+	maxQueuedMessages = 8
+	// messages returned by one resume; the client resumes again when More is set
+	resumeLimit = 100
+	// due reminders delivered per check
+	nudgeBatchSize = 10
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+// ConversationService is the part of the conversation service the socket needs
+type ConversationService interface {
+	ProcessMessage(req *conversation.ChatRequest) (*conversation.ChatResponse, error)
+	GetConversationHistory(platformUserID string, limit int) ([]*conversation.ConversationMessage, error)
+	GetMessagesSince(platformUserID, lastMessageID string, limit int) ([]*conversation.ConversationMessage, error)
+}
+
+// ReminderService finds and claims the reminders pushed as nudges
+type ReminderService interface {
+	GetDueForUser(userID string, limit int) ([]*reminder.Reminder, error)
+	MarkSent(reminderID string) (bool, error)
+}
+
+// UserRepository resolves the user whose reminders are checked
+type UserRepository interface {
+	GetUserByPlatformID(platformUserID string) (*user.User, error)
+}
+
+// TokenVerifier returns the user a chat API token was issued to
+type TokenVerifier interface {
+	Verify(token string) (string, error)
+}
+
+// Handler serves the chat WebSocket. Its users are the chat API's, so both share one history.
+type Handler struct {
+	conversations ConversationService
+	reminders     ReminderService
+	users         UserRepository
+	tokens        TokenVerifier
+	nudgeInterval time.Duration
+}
+
+// NewHandler creates the WebSocket handler; due reminders are checked every nudgeInterval,
+// and a nil reminder service or a zero interval turns nudges off
+func NewHandler(conversations ConversationService, reminders ReminderService, users UserRepository,
+	tokens TokenVerifier, nudgeInterval time.Duration) *Handler {
+	return &Handler{
+		conversations: conversations,
+		reminders:     reminders,
+		users:         users,
+		tokens:        tokens,
+		nudgeInterval: nudgeInterval,
+	}
+}
+
+// HandleWebSocket upgrades the request. Any origin may connect, since access depends on a user token
+// rather than cookies; browsers cannot set headers on a WebSocket, so the token usually comes in the auth frame.
+func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	server := websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler:   h.serve,
+	}
+	server.ServeHTTP(w, r)
+}
+
+// session is one authenticated connection; frames are written from several goroutines
+type session struct {
+	conn           *websocket.Conn
+	platformUserID string
+	writeMu        sync.Mutex
+}
+
+func (s *session) send(frame *Frame) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return websocket.JSON.Send(s.conn, frame)
+}
+
+func (s *session) sendError(frameID, message string) {
+	s.send(&Frame{Type: FrameError, ID: frameID, Error: message})
+}
+
+func (h *Handler) serve(conn *websocket.Conn) {
+	defer conn.Close()
+	conn.MaxPayloadBytes = maxFrameBytes
+
+	s := &session{conn: conn}
+
+	platformUserID, lastMessageID, err := h.authenticate(conn)
+	if err != nil {
+		logger.Warn("rejected websocket connection", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"remote_addr": conn.Request().RemoteAddr},
+		))
+		s.sendError("", err.Error())
+		return
+	}
+
+	s.platformUserID = platforms.NamespacedUserID(conversation.ChatAPIPlatform, platformUserID)
+	if err := s.send(&Frame{Type: FrameReady, PlatformUserID: platformUserID}); err != nil {
+		return
+	}
+
+	logger.Info("websocket connected", logger.Fields{"platform_user_id": s.platformUserID})
+
+	if lastMessageID != "" {
+		h.resume(s, lastMessageID)
+	}
+
+	messages := make(chan *Frame, maxQueuedMessages)
+	done := make(chan struct{})
+
+	var workers sync.WaitGroup
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		h.answer(s, messages)
+	}()
+	go func() {
+		defer workers.Done()
+		h.nudge(s, done)
+	}()
+
+	h.read(s, messages)
+
+	close(messages)
+	close(done)
+	workers.Wait()
+
+	logger.Info("websocket disconnected", logger.Fields{"platform_user_id": s.platformUserID})
+}
+
+// authenticate reads the auth frame, taking the token from the upgrade request's headers when the frame
+// has none, and returns the user the token was issued to with the frame's last message ID
+func (h *Handler) authenticate(conn *websocket.Conn) (string, string, error) {
+	conn.SetReadDeadline(time.Now().Add(authTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	var frame Frame
+	if err := websocket.JSON.Receive(conn, &frame); err != nil {
+		return "", "", errors.New("expected an auth frame")
+	}
+	if frame.Type != FrameAuth {
+		return "", "", errors.New("expected an auth frame")
+	}
+
+	token := frame.Token
+	if token == "" {
+		token = middleware.RequestAPIKey(conn.Request())
+	}
+	platformUserID, err := h.tokens.Verify(token)
+	if err != nil {
+		return "", "", errUnauthorized
+	}
+
+	return platformUserID, frame.LastMessageID, nil
+}
+
+// read handles client frames until the connection closes, queueing messages for answer
+func (h *Handler) read(s *session, messages chan<- *Frame) {
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(s.conn, &data); err != nil {
+			return
+		}
+
+		frame := &Frame{}
+		if err := json.Unmarshal(data, frame); err != nil {
+			s.sendError("", "invalid JSON frame")
+			continue
+		}
+
+		switch frame.Type {
+		case FrameMessage:
+			if strings.TrimSpace(frame.Message) == "" && len(frame.Data) == 0 {
+				s.sendError(frame.ID, "message or data is required")
+				continue
+			}
+			select {
+			case messages <- frame:
+			default:
+				s.sendError(frame.ID, "too many messages waiting for a reply")
+			}
+		case FrameResume:
+			if frame.LastMessageID == "" {
+				s.sendError(frame.ID, "last_message_id is required")
+				continue
+			}
+			h.resume(s, frame.LastMessageID)
+		case FramePing:
+			s.send(&Frame{Type: FramePong, ID: frame.ID})
+		case FrameTyping:
+			// the user typing changes nothing on the server
+		case FrameAuth:
+			s.sendError(frame.ID, "already authenticated")
+		default:
+			s.sendError(frame.ID, "unknown frame type")
+		}
+	}
+}
+
+// answer replies to queued messages one at a time, so replies never interleave
+func (h *Handler) answer(s *session, messages <-chan *Frame) {
+	for frame := range messages {
+		if frame.ID == "" {
+			frame.ID = id.Generate()
+		}
+		frameID := frame.ID
+
+		s.send(&Frame{Type: FrameTyping, ID: frameID})
+
+		resp, err := h.conversations.ProcessMessage(&conversation.ChatRequest{
+			PlatformUserID: s.platformUserID,
+			Message:        frame.Message,
+			MessageID:      frameID,
+			ChannelID:      frame.ChannelID,
+			ContextID:      frame.ContextID,
+			Data:           frame.Data,
+			OnToken: func(text string) {
+				s.send(&Frame{Type: FrameToken, ID: frameID, Text: text})
+			},
+		})
+		if err != nil {
+			logger.Error("failed to process websocket message", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{"message_id": frameID},
+			))
+			s.sendError(frameID, "failed to process message")
+			continue
+		}
+
+		s.send(&Frame{Type: FrameReply, ID: frameID, Reply: resp})
+	}
+}
+
+// resume sends the messages stored after lastMessageID, or the latest ones with Reset when the ID is unknown
+func (h *Handler) resume(s *session, lastMessageID string) {
+	frame := &Frame{Type: FrameHistory}
+
+	messages, err := h.conversations.GetMessagesSince(s.platformUserID, lastMessageID, resumeLimit)
+	if errors.Is(err, conversation.ErrMessageNotFound) {
+		frame.Reset = true
+		messages, err = h.conversations.GetConversationHistory(s.platformUserID, resumeLimit)
+	}
+	// a user who has not chatted yet simply has no history
+	if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+		logger.Error("failed to resume websocket history", logger.WithError(err))
+		s.sendError("", "failed to load history")
+		return
+	}
+
+	frame.Messages = conversation.ToChatHistory(messages)
+	frame.More = !frame.Reset && len(messages) == resumeLimit
+	s.send(frame)
+}
+
+// nudge pushes reminders as they fall due until done is closed. A reminder is claimed before it is
+// sent, so a user connected twice gets it once; reminders wait while the user is not connected.
+func (h *Handler) nudge(s *session, done <-chan struct{}) {
+	if h.reminders == nil || h.nudgeInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(h.nudgeInterval)
+	defer ticker.Stop()
+
+	var userID string
+	for {
+		if userID == "" {
+			// the user record is created by the first message, so keep looking until it exists
+			if record, err := h.users.GetUserByPlatformID(s.platformUserID); err == nil {
+				userID = record.ID
+			} else if !errors.Is(err, user.ErrUserNotFound) {
+				logger.Warn("failed to look up websocket user", logger.WithError(err))
+			}
+		}
+		if userID != "" {
+			h.sendDueReminders(s, userID)
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Handler) sendDueReminders(s *session, userID string) {
+	due, err := h.reminders.GetDueForUser(userID, nudgeBatchSize)
+	if err != nil {
+		logger.Warn("failed to get due reminders", logger.Merge(logger.WithError(err), logger.WithUserID(userID)))
+		return
+	}
+
+	for _, pending := range due {
+		claimed, err := h.reminders.MarkSent(pending.ID)
+		if err != nil {
+			logger.Warn("failed to mark reminder sent", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{"reminder_id": pending.ID},
+			))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		nudge := &Nudge{ReminderID: pending.ID, Note: pending.Note, RemindAt: pending.RemindAt}
+		if err := s.send(&Frame{Type: FrameNudge, Nudge: nudge}); err != nil {
+			logger.Warn("failed to deliver reminder", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{"reminder_id": pending.ID},
+			))
+			return
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package realtime
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/chatauth"
+	"github.com/zjoart/eunoia/internal/conversation"
+	"github.com/zjoart/eunoia/internal/reminder"
+	"github.com/zjoart/eunoia/internal/user"
+	"golang.org/x/net/websocket"
+)
+
+var testTokens = chatauth.NewTokens("secret", time.Hour)
+
+func testToken(t *testing.T, platformUserID string) string {
+	t.Helper()
+	token, _, err := testTokens.Issue(platformUserID)
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+	return token
+}
+
+type fakeConversations struct {
+	mu       sync.Mutex
+	requests []*conversation.ChatRequest
+	history  []*conversation.ConversationMessage
+	since    map[string][]*conversation.ConversationMessage
+}
+
+func (f *fakeConversations) ProcessMessage(req *conversation.ChatRequest) (*conversation.ChatResponse, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+
+	if req.Message == "fail" {
+		return nil, errors.New("model unavailable")
+	}
+
+	req.OnToken("Hello")
+	req.OnToken(" there")
+	return &conversation.ChatResponse{Response: "Hello there", ContextID: "ctx-1", HistoryID: "h-reply"}, nil
+}
+
+func (f *fakeConversations) GetConversationHistory(platformUserID string, limit int) ([]*conversation.ConversationMessage, error) {
+	return f.history, nil
+}
+
+func (f *fakeConversations) GetMessagesSince(platformUserID, lastMessageID string, limit int) ([]*conversation.ConversationMessage, error) {
+	messages, ok := f.since[lastMessageID]
+	if !ok {
+		return nil, fmt.Errorf("resume: %w", conversation.ErrMessageNotFound)
+	}
+	return messages, nil
+}
+
+type fakeReminders struct {
+	mu      sync.Mutex
+	due     []*reminder.Reminder
+	claimed map[string]bool
+}
+
+func (f *fakeReminders) GetDueForUser(userID string, limit int) ([]*reminder.Reminder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var due []*reminder.Reminder
+	for _, r := range f.due {
+		if r.UserID == userID && !f.claimed[r.ID] {
+			due = append(due, r)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeReminders) MarkSent(reminderID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.claimed[reminderID] {
+		return false, nil
+	}
+	f.claimed[reminderID] = true
+	return true, nil
+}
+
+type fakeUsers map[string]string
+
+func (f fakeUsers) GetUserByPlatformID(platformUserID string) (*user.User, error) {
+	userID, ok := f[platformUserID]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	return &user.User{ID: userID, PlatformUserID: platformUserID}, nil
+}
+
+func newTestServer(t *testing.T, handler *Handler) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dial(t *testing.T, url string, header http.Header) *websocket.Conn {
+	t.Helper()
+	config, err := websocket.NewConfig(url, "http://localhost/")
+	if err != nil {
+		t.Fatalf("failed to configure websocket: %v", err)
+	}
+	for name, values := range header {
+		config.Header[name] = values
+	}
+
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func send(t *testing.T, conn *websocket.Conn, frame *Frame) {
+	t.Helper()
+	if err := websocket.JSON.Send(conn, frame); err != nil {
+		t.Fatalf("failed to send %s frame: %v", frame.Type, err)
+	}
+}
+
+func receive(t *testing.T, conn *websocket.Conn) *Frame {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	var frame Frame
+	if err := websocket.JSON.Receive(conn, &frame); err != nil {
+		t.Fatalf("failed to receive a frame: %v", err)
+	}
+	return &frame
+}
+
+func connect(t *testing.T, url string, lastMessageID string) *websocket.Conn {
+	t.Helper()
+	conn := dial(t, url, nil)
+	send(t, conn, &Frame{Type: FrameAuth, Token: testToken(t, "u1"), LastMessageID: lastMessageID})
+
+	if ready := receive(t, conn); ready.Type != FrameReady || ready.PlatformUserID != "u1" {
+		t.Fatalf("expected a ready frame, got %+v", ready)
+	}
+	return conn
+}
+
+func TestHandleWebSocket_Authentication(t *testing.T) {
+	url := newTestServer(t, NewHandler(&fakeConversations{}, nil, fakeUsers{}, testTokens, 0))
+	token := testToken(t, "u1")
+
+	tests := []struct {
+		name   string
+		header http.Header
+		frame  *Frame
+		error  string
+	}{
+		{"wrong token", nil, &Frame{Type: FrameAuth, Token: "guess"}, "unauthorized"},
+		{"missing token", nil, &Frame{Type: FrameAuth, PlatformUserID: "u1"}, "unauthorized"},
+		{"message before auth", nil, &Frame{Type: FrameMessage, Message: "hi"}, "expected an auth frame"},
+		{"token in header", http.Header{"Authorization": {"Bearer " + token}}, &Frame{Type: FrameAuth}, ""},
+		// the user comes from the token, whatever the frame names
+		{"frame names another user", nil, &Frame{Type: FrameAuth, Token: token, PlatformUserID: "u2"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dial(t, url, tt.header)
+			send(t, conn, tt.frame)
+
+			frame := receive(t, conn)
+			if tt.error == "" {
+				if frame.Type != FrameReady || frame.PlatformUserID != "u1" {
+					t.Errorf("expected a ready frame for u1, got %+v", frame)
+				}
+				return
+			}
+
+			if frame.Type != FrameError || frame.Error != tt.error {
+				t.Errorf("expected error %q, got %+v", tt.error, frame)
+			}
+
+			var next Frame
+			if err := websocket.JSON.Receive(conn, &next); err == nil {
+				t.Errorf("expected the connection to close, got %+v", next)
+			}
+		})
+	}
+}
+
+func TestHandleWebSocket_StreamsReplies(t *testing.T) {
+	conversations := &fakeConversations{}
+	conn := connect(t, newTestServer(t, NewHandler(conversations, nil, fakeUsers{}, testTokens, 0)), "")
+
+	send(t, conn, &Frame{Type: FrameTyping})
+	send(t, conn, &Frame{Type: FrameMessage, ID: "c1", Message: "hi", ChannelID: "web"})
+
+	var types []string
+	var text strings.Builder
+	for {
+		frame := receive(t, conn)
+		if frame.ID != "c1" {
+			t.Fatalf("expected frames for c1, got %+v", frame)
+		}
+		types = append(types, frame.Type)
+		text.WriteString(frame.Text)
+
+		if frame.Type == FrameReply {
+			if frame.Reply.Response != "Hello there" || frame.Reply.HistoryID != "h-reply" {
+				t.Errorf("unexpected reply %+v", frame.Reply)
+			}
+			break
+		}
+	}
+
+	if got := strings.Join(types, ","); got != "typing,token,token,reply" {
+		t.Errorf("unexpected frame sequence %s", got)
+	}
+	if text.String() != "Hello there" {
+		t.Errorf("expected the streamed tokens to add up to the reply, got %q", text.String())
+	}
+
+	conversations.mu.Lock()
+	req := conversations.requests[0]
+	conversations.mu.Unlock()
+	if req.PlatformUserID != "app:u1" || req.MessageID != "c1" || req.ChannelID != "web" {
+		t.Errorf("unexpected chat request %+v", req)
+	}
+
+	send(t, conn, &Frame{Type: FrameMessage, ID: "c2", Message: "fail"})
+	receive(t, conn)
+	if frame := receive(t, conn); frame.Type != FrameError || frame.ID != "c2" {
+		t.Errorf("expected an error for c2, got %+v", frame)
+	}
+
+	send(t, conn, &Frame{Type: FrameMessage, ID: "c3", Message: " "})
+	if frame := receive(t, conn); frame.Type != FrameError || frame.Error != "message or data is required" {
+		t.Errorf("expected a validation error, got %+v", frame)
+	}
+
+	send(t, conn, &Frame{Type: FramePing, ID: "p1"})
+	if frame := receive(t, conn); frame.Type != FramePong || frame.ID != "p1" {
+		t.Errorf("expected a pong, got %+v", frame)
+	}
+}
+
+func TestHandleWebSocket_Resume(t *testing.T) {
+	created := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+	conversations := &fakeConversations{
+		history: []*conversation.ConversationMessage{
+			{ID: "h1", MessageRole: "user", MessageContent: "hi", CreatedAt: created},
+			{ID: "h2", MessageRole: "assistant", MessageContent: "Hello!", ContextData: "prompt context", CreatedAt: created},
+		},
+		since: map[string][]*conversation.ConversationMessage{
+			"h1": {{ID: "h2", MessageRole: "assistant", MessageContent: "Hello!", CreatedAt: created}},
+			"h2": nil,
+		},
+	}
+	url := newTestServer(t, NewHandler(conversations, nil, fakeUsers{}, testTokens, 0))
+
+	conn := connect(t, url, "h1")
+	frame := receive(t, conn)
+	if frame.Type != FrameHistory || frame.Reset || len(frame.Messages) != 1 || frame.Messages[0].ID != "h2" {
+		t.Errorf("expected the message after h1, got %+v", frame)
+	}
+
+	send(t, conn, &Frame{Type: FrameResume, LastMessageID: "h2"})
+	if frame := receive(t, conn); frame.Type != FrameHistory || len(frame.Messages) != 0 || frame.Reset {
+		t.Errorf("expected nothing new after h2, got %+v", frame)
+	}
+
+	send(t, conn, &Frame{Type: FrameResume, LastMessageID: "unknown"})
+	frame = receive(t, conn)
+	if frame.Type != FrameHistory || !frame.Reset || len(frame.Messages) != 2 {
+		t.Errorf("expected the latest history with reset, got %+v", frame)
+	}
+}
+
+func TestHandleWebSocket_Nudges(t *testing.T) {
+	remindAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	reminders := &fakeReminders{
+		due: []*reminder.Reminder{
+			{ID: "r1", UserID: "user-1", Note: "take a walk", RemindAt: remindAt},
+			{ID: "r2", UserID: "user-2", Note: "someone else's", RemindAt: remindAt},
+		},
+		claimed: map[string]bool{},
+	}
+	users := fakeUsers{"app:u1": "user-1"}
+	url := newTestServer(t, NewHandler(&fakeConversations{}, reminders, users, testTokens, 20*time.Millisecond))
+
+	conn := connect(t, url, "")
+	frame := receive(t, conn)
+	if frame.Type != FrameNudge || frame.Nudge.ReminderID != "r1" || frame.Nudge.Note != "take a walk" {
+		t.Fatalf("expected the due reminder, got %+v", frame)
+	}
+	if !frame.Nudge.RemindAt.Equal(remindAt) {
+		t.Errorf("expected remind_at %v, got %v", remindAt, frame.Nudge.RemindAt)
+	}
+
+	// a second connection must not receive the reminder the first one claimed
+	other := connect(t, url, "")
+	send(t, other, &Frame{Type: FramePing, ID: "p1"})
+	time.Sleep(60 * time.Millisecond)
+	if frame := receive(t, other); frame.Type != FramePong {
+		t.Errorf("expected no second nudge, got %+v", frame)
+	}
+	if reminders.claimed["r2"] {
+		t.Error("expected another user's reminder to stay pending")
+	}
+}
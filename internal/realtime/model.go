@@ -0,0 +1,66 @@
+package realtime
+
+import (
+	"time"
+
+	"github.com/zjoart/eunoia/internal/conversation"
+)
+
+// frame types sent by the client
+const (
+	FrameAuth    = "auth"
+	FrameMessage = "message"
+	FrameResume  = "resume"
+	FramePing    = "ping"
+)
+
+// frame types sent by the server; typing is also accepted from the client and ignored
+const (
+	FrameReady   = "ready"
+	FrameTyping  = "typing"
+	FrameToken   = "token"
+	FrameReply   = "reply"
+	FrameHistory = "history"
+	FrameNudge   = "nudge"
+	FramePong    = "pong"
+	FrameError   = "error"
+)
+
+// Frame is one JSON message on the socket in either direction; Type decides which fields are set
+type Frame struct {
+	Type string `json:"type"`
+	// ID is chosen by the client for a message and echoed on the typing, token, reply and error frames answering it
+	ID string `json:"id,omitempty"`
+
+	// auth carries a user token; ready names the user it was issued to
+	Token          string `json:"token,omitempty"`
+	PlatformUserID string `json:"platform_user_id,omitempty"`
+	// LastMessageID is the history ID of the last message the client received, on auth or resume
+	LastMessageID string `json:"last_message_id,omitempty"`
+
+	// message
+	Message   string                   `json:"message,omitempty"`
+	ChannelID string                   `json:"channel_id,omitempty"`
+	ContextID string                   `json:"context_id,omitempty"`
+	Data      []map[string]interface{} `json:"data,omitempty"`
+
+	// token carries the next chunk of a reply while the model writes it
+	Text  string                     `json:"text,omitempty"`
+	Reply *conversation.ChatResponse `json:"reply,omitempty"`
+
+	// history; Reset means the last message ID was unknown and Messages are the latest instead,
+	// More that another resume from the last of Messages will return the rest
+	Messages []conversation.ChatHistoryMessage `json:"messages,omitempty"`
+	Reset    bool                              `json:"reset,omitempty"`
+	More     bool                              `json:"more,omitempty"`
+
+	Nudge *Nudge `json:"nudge,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Nudge is a due reminder pushed to a connected client
+type Nudge struct {
+	ReminderID string    `json:"reminder_id"`
+	Note       string    `json:"note"`
+	RemindAt   time.Time `json:"remind_at"`
+}
@@ -22,3 +22,8 @@ type ReflectionResponse struct {
 	Reflection *Reflection `json:"reflection"`
 	Insights   string      `json:"insights"`
 }
+
+type SearchResult struct {
+	Reflection *Reflection `json:"reflection"`
+	Score      float64     `json:"score"`
+}
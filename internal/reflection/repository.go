@@ -81,3 +81,45 @@ func (r *Repository) GetRecentReflections(userID string, days int) ([]*Reflectio
 
 	return reflections, nil
 }
+
+// SearchReflections runs a full-text search over the user's reflections, ranked by relevance.
+// Zero from/to values leave that side of the date range open.
+func (r *Repository) SearchReflections(userID, searchQuery string, from, to time.Time, limit int) ([]*SearchResult, error) {
+	query := `SELECT id, user_id, content, sentiment, key_themes, ai_analysis, created_at, updated_at,
+			  MATCH(content) AGAINST (? IN NATURAL LANGUAGE MODE) AS score
+			  FROM reflections
+			  WHERE user_id = ? AND MATCH(content) AGAINST (? IN NATURAL LANGUAGE MODE)`
+	args := []interface{}{searchQuery, userID, searchQuery}
+
+	if !from.IsZero() {
+		query += ` AND created_at >= ?`
+		args = append(args, from)
+	}
+	if !to.IsZero() {
+		query += ` AND created_at < ?`
+		args = append(args, to)
+	}
+
+	query += ` ORDER BY score DESC, created_at DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*SearchResult
+	for rows.Next() {
+		reflection := &Reflection{}
+		result := &SearchResult{Reflection: reflection}
+		err := rows.Scan(&reflection.ID, &reflection.UserID, &reflection.Content, &reflection.Sentiment,
+			&reflection.KeyThemes, &reflection.AIAnalysis, &reflection.CreatedAt, &reflection.UpdatedAt, &result.Score)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
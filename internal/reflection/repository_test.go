@@ -79,3 +79,46 @@ func TestGetReflectionsByUserID(t *testing.T) {
 		t.Errorf("unmet expectations: %v", err)
 	}
 }
+
+func TestSearchReflections(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	userID := "user-456"
+	now := time.Now()
+	from := now.AddDate(0, -1, 0)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "content", "sentiment", "key_themes", "ai_analysis", "created_at", "updated_at", "score"}).
+		AddRow("ref-1", userID, "Called my sister today", "positive", "family", "Analysis 1", now, now, 2.5).
+		AddRow("ref-2", userID, "My sister and I argued", "negative", "family", "Analysis 2", now, now, 1.2)
+
+	mock.ExpectQuery("SELECT (.+) FROM reflections WHERE user_id = \\? AND MATCH\\(content\\) AGAINST (.+) AND created_at >= \\? ORDER BY score DESC").
+		WithArgs("sister", userID, "sister", from, 10).
+		WillReturnRows(rows)
+
+	results, err := repo.SearchReflections(userID, "sister", from, time.Time{}, 10)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	if results[0].Score != 2.5 {
+		t.Errorf("expected score 2.5, got %f", results[0].Score)
+	}
+
+	if results[0].Reflection.ID != "ref-1" {
+		t.Errorf("expected ref-1 first, got '%s'", results[0].Reflection.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -36,19 +36,21 @@ func (s *Service) CreateReflection(req *CreateReflectionRequest) (*Reflection, e
 		return nil, fmt.Errorf("failed to process user: %w", err)
 	}
 
-	sentiment, err := s.geminiService.AnalyzeSentiment(req.Content)
+	gemini := s.geminiService.For(agent.PurposeReflectionAnalysis, userRecord.ID)
+
+	sentiment, err := gemini.AnalyzeSentiment(req.Content)
 	if err != nil {
 		logger.Warn("failed to analyze sentiment", logger.WithError(err))
 		sentiment = "unknown"
 	}
 
-	keyThemes, err := s.geminiService.ExtractKeyThemes(req.Content)
+	keyThemes, err := gemini.ExtractKeyThemes(req.Content)
 	if err != nil {
 		logger.Warn("failed to extract key themes", logger.WithError(err))
 		keyThemes = ""
 	}
 
-	aiAnalysis, err := s.generateReflectionAnalysis(req.Content, sentiment, keyThemes)
+	aiAnalysis, err := s.generateReflectionAnalysis(gemini, req.Content, sentiment, keyThemes)
 	if err != nil {
 		logger.Warn("failed to generate AI analysis", logger.WithError(err))
 		aiAnalysis = "Analysis unavailable at this time."
@@ -81,7 +83,7 @@ func (s *Service) GetReflectionHistory(platformUserID string, limit int) ([]*Ref
 	return s.repo.GetReflectionsByUserID(userRecord.ID, limit)
 }
 
-func (s *Service) generateReflectionAnalysis(content, sentiment, themes string) (string, error) {
+func (s *Service) generateReflectionAnalysis(gemini *agent.GeminiService, content, sentiment, themes string) (string, error) {
 	systemPrompt := `You are a thoughtful companion helping someone process their inner experience.
 
 Respond with warmth and insight:
@@ -97,7 +99,7 @@ The emotional tone seems %s, touching on: %s
 
 Offer a brief, supportive response that honors their experience:`, content, sentiment, themes)
 
-	analysis, err := s.geminiService.GenerateContent(systemPrompt, userPrompt, nil)
+	analysis, err := gemini.GenerateContent(systemPrompt, userPrompt, nil)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,116 @@
+package reminder
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+const (
+	// due reminders delivered per platform on each check
+	dispatchBatchSize = 50
+	sendTimeout       = 10 * time.Second
+)
+
+// Sender delivers reminders to the users of one platform that can message them first
+type Sender interface {
+	// SendReminder delivers the note to userID, the platform's own ID for the user without the platform prefix
+	SendReminder(ctx context.Context, userID, note string) error
+}
+
+// Dispatcher delivers due reminders through each platform's Sender. Platforms without one, such as
+// the chat WebSocket, deliver their own users' reminders.
+type Dispatcher struct {
+	service  *Service
+	senders  map[string]Sender
+	interval time.Duration
+}
+
+func NewDispatcher(service *Service, interval time.Duration) *Dispatcher {
+	return &Dispatcher{
+		service:  service,
+		senders:  make(map[string]Sender),
+		interval: interval,
+	}
+}
+
+// Register delivers the reminders of users whose platform user ID starts with platform + ":"
+func (d *Dispatcher) Register(platform string, sender Sender) {
+	d.senders[platform] = sender
+}
+
+// Platforms returns the platforms reminders are delivered to, in alphabetical order
+func (d *Dispatcher) Platforms() []string {
+	platforms := make([]string, 0, len(d.senders))
+	for platform := range d.senders {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// Run delivers due reminders every interval until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	if len(d.senders) == 0 || d.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.DeliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends every platform's due reminders once. A reminder is claimed before it is sent,
+// so running several instances never delivers it twice.
+func (d *Dispatcher) DeliverDue(ctx context.Context) {
+	for _, platform := range d.Platforms() {
+		due, err := d.service.GetDueForPlatform(platform, dispatchBatchSize)
+		if err != nil {
+			logger.Warn("failed to get due reminders", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{"platform": platform},
+			))
+			continue
+		}
+
+		for _, pending := range due {
+			d.deliver(ctx, platform, pending)
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, platform string, pending *Reminder) {
+	fields := logger.Fields{"reminder_id": pending.ID, "platform": platform}
+
+	claimed, err := d.service.MarkSent(pending.ID)
+	if err != nil {
+		logger.Warn("failed to mark reminder sent", logger.Merge(logger.WithError(err), fields))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	userID := strings.TrimPrefix(pending.PlatformUserID, platform+":")
+	if err := d.senders[platform].SendReminder(sendCtx, userID, pending.Note); err != nil {
+		logger.Warn("failed to deliver reminder", logger.Merge(logger.WithError(err), fields))
+		return
+	}
+
+	logger.Info("delivered reminder", fields)
+}
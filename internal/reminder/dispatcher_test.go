@@ -0,0 +1,87 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type sentReminder struct {
+	userID string
+	note   string
+}
+
+type fakeSender struct {
+	sent []sentReminder
+	err  error
+}
+
+func (f *fakeSender) SendReminder(ctx context.Context, userID, note string) error {
+	f.sent = append(f.sent, sentReminder{userID, note})
+	return f.err
+}
+
+var dueColumns = []string{"id", "user_id", "note", "remind_at", "status", "created_at", "sent_at", "platform_user_id"}
+
+func TestDispatcher_DeliverDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	slack := &fakeSender{}
+	telegram := &fakeSender{err: errors.New("bot was blocked by the user")}
+
+	dispatcher := NewDispatcher(NewService(NewRepository(db)), time.Minute)
+	dispatcher.Register("telegram", telegram)
+	dispatcher.Register("slack", slack)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM reminders r JOIN users u").
+		WithArgs("slack:%", StatusPending, sqlmock.AnyArg(), dispatchBatchSize).
+		WillReturnRows(sqlmock.NewRows(dueColumns).
+			AddRow("r1", "user-1", "take a walk", now, StatusPending, now, nil, "slack:T1:U1").
+			AddRow("r2", "user-2", "drink water", now, StatusPending, now, nil, "slack:U2"))
+	mock.ExpectExec("UPDATE reminders").
+		WithArgs(StatusSent, sqlmock.AnyArg(), "r1", StatusPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// another instance delivered r2 first
+	mock.ExpectExec("UPDATE reminders").
+		WithArgs(StatusSent, sqlmock.AnyArg(), "r2", StatusPending).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM reminders r JOIN users u").
+		WithArgs("telegram:%", StatusPending, sqlmock.AnyArg(), dispatchBatchSize).
+		WillReturnRows(sqlmock.NewRows(dueColumns).
+			AddRow("r3", "user-3", "stretch", now, StatusPending, now, nil, "telegram:42"))
+	mock.ExpectExec("UPDATE reminders").
+		WithArgs(StatusSent, sqlmock.AnyArg(), "r3", StatusPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	dispatcher.DeliverDue(context.Background())
+
+	if len(slack.sent) != 1 || slack.sent[0] != (sentReminder{"T1:U1", "take a walk"}) {
+		t.Errorf("expected only the claimed slack reminder, got %+v", slack.sent)
+	}
+	if len(telegram.sent) != 1 || telegram.sent[0].userID != "42" {
+		t.Errorf("expected the telegram reminder to be attempted, got %+v", telegram.sent)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestDispatcher_Platforms(t *testing.T) {
+	dispatcher := NewDispatcher(nil, time.Minute)
+	dispatcher.Register("telegram", &fakeSender{})
+	dispatcher.Register("slack", &fakeSender{})
+
+	platforms := dispatcher.Platforms()
+	if len(platforms) != 2 || platforms[0] != "slack" || platforms[1] != "telegram" {
+		t.Errorf("unexpected platforms %v", platforms)
+	}
+}
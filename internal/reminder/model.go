@@ -0,0 +1,21 @@
+package reminder
+
+import "time"
+
+const (
+	StatusPending   = "pending"
+	StatusSent      = "sent"
+	StatusCancelled = "cancelled"
+)
+
+type Reminder struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Note      string     `json:"note"`
+	RemindAt  time.Time  `json:"remind_at"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	// PlatformUserID is only loaded for delivery
+	PlatformUserID string `json:"-"`
+}
@@ -0,0 +1,126 @@
+package reminder
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateReminder(reminder *Reminder) error {
+	query := `INSERT INTO reminders (id, user_id, note, remind_at, status, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, reminder.ID, reminder.UserID, reminder.Note, reminder.RemindAt,
+		reminder.Status, reminder.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDueRemindersForPlatform returns pending reminders of one platform's users due at or before the
+// given time, oldest first, with each user's platform ID set
+func (r *Repository) GetDueRemindersForPlatform(platform string, before time.Time, limit int) ([]*Reminder, error) {
+	query := `SELECT r.id, r.user_id, r.note, r.remind_at, r.status, r.created_at, r.sent_at, u.platform_user_id
+			  FROM reminders r
+			  JOIN users u ON u.id = r.user_id
+			  WHERE u.platform_user_id LIKE ? AND r.status = ? AND r.remind_at <= ?
+			  ORDER BY r.remind_at ASC
+			  LIMIT ?`
+
+	rows, err := r.db.Query(query, platform+":%", StatusPending, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*Reminder
+	for rows.Next() {
+		reminder := &Reminder{}
+		var sentAt sql.NullTime
+		err := rows.Scan(&reminder.ID, &reminder.UserID, &reminder.Note, &reminder.RemindAt,
+			&reminder.Status, &reminder.CreatedAt, &sentAt, &reminder.PlatformUserID)
+		if err != nil {
+			return nil, err
+		}
+		if sentAt.Valid {
+			reminder.SentAt = &sentAt.Time
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, nil
+}
+
+// GetDueRemindersForUser returns one user's pending reminders due at or before the given time, oldest first
+func (r *Repository) GetDueRemindersForUser(userID string, before time.Time, limit int) ([]*Reminder, error) {
+	query := `SELECT id, user_id, note, remind_at, status, created_at, sent_at
+			  FROM reminders
+			  WHERE user_id = ? AND status = ? AND remind_at <= ?
+			  ORDER BY remind_at ASC
+			  LIMIT ?`
+
+	return r.queryReminders(query, userID, StatusPending, before, limit)
+}
+
+// GetPendingReminders returns a user's upcoming reminders, soonest first
+func (r *Repository) GetPendingReminders(userID string, limit int) ([]*Reminder, error) {
+	query := `SELECT id, user_id, note, remind_at, status, created_at, sent_at
+			  FROM reminders
+			  WHERE user_id = ? AND status = ?
+			  ORDER BY remind_at ASC
+			  LIMIT ?`
+
+	return r.queryReminders(query, userID, StatusPending, limit)
+}
+
+// MarkSent flags a pending reminder as delivered, reporting false if it was already handled
+func (r *Repository) MarkSent(reminderID string, sentAt time.Time) (bool, error) {
+	query := `UPDATE reminders SET status = ?, sent_at = ? WHERE id = ? AND status = ?`
+
+	result, err := r.db.Exec(query, StatusSent, sentAt, reminderID, StatusPending)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *Repository) queryReminders(query string, args ...interface{}) ([]*Reminder, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*Reminder
+	for rows.Next() {
+		reminder := &Reminder{}
+		var sentAt sql.NullTime
+		err := rows.Scan(&reminder.ID, &reminder.UserID, &reminder.Note, &reminder.RemindAt,
+			&reminder.Status, &reminder.CreatedAt, &sentAt)
+		if err != nil {
+			return nil, err
+		}
+		if sentAt.Valid {
+			reminder.SentAt = &sentAt.Time
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, nil
+}
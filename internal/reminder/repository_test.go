@@ -0,0 +1,126 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCreateReminder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	reminder := &Reminder{
+		ID:        "reminder-123",
+		UserID:    "user-456",
+		Note:      "take a walk",
+		RemindAt:  time.Now().Add(time.Hour),
+		Status:    StatusPending,
+		CreatedAt: time.Now(),
+	}
+
+	mock.ExpectExec("INSERT INTO reminders").
+		WithArgs(reminder.ID, reminder.UserID, reminder.Note, reminder.RemindAt, reminder.Status, reminder.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := repo.CreateReminder(reminder); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetDueRemindersForPlatform(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "note", "remind_at", "status", "created_at", "sent_at", "platform_user_id"}).
+		AddRow("reminder-1", "user-1", "drink water", now.Add(-time.Minute), StatusPending, now.Add(-time.Hour), nil, "slack:T1:U1")
+
+	mock.ExpectQuery("SELECT (.+) FROM reminders r JOIN users u").
+		WithArgs("slack:%", StatusPending, now, 10).
+		WillReturnRows(rows)
+
+	reminders, err := repo.GetDueRemindersForPlatform("slack", now, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reminders) != 1 || reminders[0].Note != "drink water" || reminders[0].SentAt != nil ||
+		reminders[0].PlatformUserID != "slack:T1:U1" {
+		t.Errorf("unexpected reminders: %+v", reminders)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetDueRemindersForUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "note", "remind_at", "status", "created_at", "sent_at"}).
+		AddRow("reminder-1", "user-1", "stretch", now.Add(-time.Minute), StatusPending, now.Add(-time.Hour), nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM reminders WHERE user_id = \\? AND status = \\? AND remind_at <= \\?").
+		WithArgs("user-1", StatusPending, now, 5).
+		WillReturnRows(rows)
+
+	reminders, err := repo.GetDueRemindersForUser("user-1", now, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reminders) != 1 || reminders[0].UserID != "user-1" {
+		t.Errorf("unexpected reminders: %+v", reminders)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestMarkSent_AlreadyHandled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE reminders SET status").
+		WithArgs(StatusSent, now, "reminder-1", StatusPending).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	marked, err := repo.MarkSent("reminder-1", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if marked {
+		t.Error("expected an already delivered reminder not to be marked again")
+	}
+}
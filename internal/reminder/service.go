@@ -0,0 +1,89 @@
+package reminder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/id"
+)
+
+var ErrInvalidReminder = errors.New("invalid reminder")
+
+const (
+	maxReminderNoteLength = 500
+	maxReminderAhead      = 365 * 24 * time.Hour
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+// Validate checks a reminder can be scheduled, returning the cleaned up note
+func Validate(note string, remindAt, now time.Time) (string, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return "", fmt.Errorf("%w: note cannot be empty", ErrInvalidReminder)
+	}
+	if len([]rune(note)) > maxReminderNoteLength {
+		return "", fmt.Errorf("%w: note is too long", ErrInvalidReminder)
+	}
+	if !remindAt.After(now) {
+		return "", fmt.Errorf("%w: time must be in the future", ErrInvalidReminder)
+	}
+	if remindAt.Sub(now) > maxReminderAhead {
+		return "", fmt.Errorf("%w: time must be within a year", ErrInvalidReminder)
+	}
+
+	return note, nil
+}
+
+func (s *Service) Schedule(userID, note string, remindAt time.Time) (*Reminder, error) {
+	now := time.Now()
+
+	note, err := Validate(note, remindAt, now)
+	if err != nil {
+		return nil, err
+	}
+
+	reminder := &Reminder{
+		ID:        id.Generate(),
+		UserID:    userID,
+		Note:      note,
+		RemindAt:  remindAt,
+		Status:    StatusPending,
+		CreatedAt: now,
+	}
+
+	if err := s.repo.CreateReminder(reminder); err != nil {
+		return nil, fmt.Errorf("failed to schedule reminder: %w", err)
+	}
+
+	return reminder, nil
+}
+
+func (s *Service) GetPending(userID string, limit int) ([]*Reminder, error) {
+	return s.repo.GetPendingReminders(userID, limit)
+}
+
+// GetDueForPlatform returns one platform's reminders ready to be delivered
+func (s *Service) GetDueForPlatform(platform string, limit int) ([]*Reminder, error) {
+	return s.repo.GetDueRemindersForPlatform(platform, time.Now(), limit)
+}
+
+// GetDueForUser returns one user's reminders ready to be delivered
+func (s *Service) GetDueForUser(userID string, limit int) ([]*Reminder, error) {
+	return s.repo.GetDueRemindersForUser(userID, time.Now(), limit)
+}
+
+// MarkSent records delivery; false means another worker already delivered it
+func (s *Service) MarkSent(reminderID string) (bool, error) {
+	return s.repo.MarkSent(reminderID, time.Now())
+}
@@ -0,0 +1,44 @@
+package reminder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		note     string
+		remindAt time.Time
+		valid    bool
+	}{
+		{"valid", "  call mum ", now.Add(time.Hour), true},
+		{"empty note", "   ", now.Add(time.Hour), false},
+		{"too long", strings.Repeat("a", maxReminderNoteLength+1), now.Add(time.Hour), false},
+		{"in the past", "call mum", now.Add(-time.Minute), false},
+		{"too far ahead", "call mum", now.AddDate(2, 0, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note, err := Validate(tt.note, tt.remindAt, now)
+			if tt.valid {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if note != "call mum" {
+					t.Errorf("expected trimmed note, got %q", note)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidReminder) {
+				t.Errorf("expected ErrInvalidReminder, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/conversation"
+	"github.com/zjoart/eunoia/internal/conversation/platforms"
+	"github.com/zjoart/eunoia/pkg/a2a"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// largest Events API request body accepted
+const maxBodyBytes = 1 << 20
+
+// how long a reply may take to post once the model has answered
+const postTimeout = 30 * time.Second
+
+// ConversationService is the part of the conversation service the adapter needs
+type ConversationService interface {
+	ProcessMessage(req *conversation.ChatRequest) (*conversation.ChatResponse, error)
+}
+
+// Handler receives Events API callbacks and answers direct messages and mentions
+type Handler struct {
+	service       ConversationService
+	client        *Client
+	platform      *platforms.SlackPlatform
+	signingSecret string
+	now           func() time.Time
+	// background tracks events still being answered after Slack was acknowledged
+	background sync.WaitGroup
+}
+
+func NewHandler(service ConversationService, client *Client, signingSecret string) *Handler {
+	return &Handler{
+		service:       service,
+		client:        client,
+		platform:      platforms.NewSlackPlatform(),
+		signingSecret: signingSecret,
+		now:           time.Now,
+	}
+}
+
+// HandleEvents serves the Events API request URL. Slack expects an answer within three seconds,
+// so events are acknowledged at once and answered in the background.
+func (h *Handler) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if err := Verify(h.signingSecret, r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader), body, h.now()); err != nil {
+		logger.Warn("rejected slack request with invalid signature", logger.Fields{"path": r.URL.Path})
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	switch envelope.Type {
+	case TypeURLVerification:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"challenge": envelope.Challenge})
+		return
+	case TypeEventCallback:
+	default:
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// the first delivery is already being answered, so retries are only acknowledged
+	if r.Header.Get(RetryNumHeader) != "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if event := envelope.Event; event != nil && h.shouldAnswer(event) {
+		h.background.Add(1)
+		go func() {
+			defer h.background.Done()
+			h.answer(&envelope, event)
+		}()
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// shouldAnswer picks direct messages and mentions written by people. Channel messages only
+// count as mentions, which arrive as their own app_mention event.
+func (h *Handler) shouldAnswer(event *Event) bool {
+	if event.BotID != "" || event.Subtype != "" || event.User == "" {
+		return false
+	}
+
+	switch event.Type {
+	case EventAppMention:
+		return true
+	case EventMessage:
+		return event.ChannelType == ChannelTypeIM
+	default:
+		return false
+	}
+}
+
+func (h *Handler) answer(envelope *Envelope, event *Event) {
+	// mentions are answered in a thread; direct messages only when they were sent in one
+	threadTS := event.ThreadTS
+	if threadTS == "" && event.Type == EventAppMention {
+		threadTS = event.TS
+	}
+
+	metadata := map[string]interface{}{
+		platforms.SlackUserKey:    event.User,
+		platforms.SlackTeamKey:    envelope.TeamID,
+		platforms.SlackChannelKey: event.Channel,
+		platforms.SlackThreadKey:  threadTS,
+	}
+
+	fields := logger.Fields{
+		"event_id":   envelope.EventID,
+		"event_type": event.Type,
+		"channel_id": event.Channel,
+	}
+
+	userID, err := h.platform.ExtractUserID(metadata)
+	if err != nil {
+		logger.Warn("ignored slack event without a user", fields)
+		return
+	}
+	channelID, _ := h.platform.ExtractChannelID(metadata)
+
+	text := removeMentions(event.Text, envelope.Authorizations)
+	message := h.platform.ExtractMessage([]a2a.A2APart{{Kind: a2a.PartKindText, Text: text}})
+	if message == "" {
+		return
+	}
+
+	messageID := event.ClientMsgID
+	if messageID == "" {
+		messageID = event.TS
+	}
+
+	resp, err := h.service.ProcessMessage(&conversation.ChatRequest{
+		PlatformUserID: platforms.NamespacedUserID(h.platform.Name(), userID),
+		Message:        message,
+		MessageID:      messageID,
+		ChannelID:      channelID,
+	})
+	if err != nil {
+		logger.Error("failed to process slack message", logger.Merge(logger.WithError(err), fields))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postTimeout)
+	defer cancel()
+
+	err = h.client.PostMessage(ctx, &Message{
+		Channel:  event.Channel,
+		Text:     ToMrkdwn(resp.Response),
+		ThreadTS: threadTS,
+		Mrkdwn:   true,
+	})
+	if err != nil {
+		logger.Error("failed to post slack reply", logger.Merge(logger.WithError(err), fields))
+		return
+	}
+
+	logger.Info("slack message answered", fields)
+}
+
+// SendReminder posts a due reminder to the user's direct messages with the app; userID may carry the team
+func (h *Handler) SendReminder(ctx context.Context, userID, note string) error {
+	if i := strings.LastIndex(userID, ":"); i >= 0 {
+		userID = userID[i+1:]
+	}
+
+	return h.client.PostMessage(ctx, &Message{
+		Channel: userID,
+		Text:    ToMrkdwn("Reminder: " + note),
+		Mrkdwn:  true,
+	})
+}
+
+// removeMentions drops mentions of the app's own bot user, which address Eunoia rather than add to the message
+func removeMentions(text string, authorizations []Authorization) string {
+	for _, authorization := range authorizations {
+		if authorization.IsBot && authorization.UserID != "" {
+			text = strings.ReplaceAll(text, "<@"+authorization.UserID+">", "")
+		}
+	}
+	return strings.TrimSpace(text)
+}
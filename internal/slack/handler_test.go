@@ -0,0 +1,288 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/conversation"
+)
+
+const testSecret = "signing-secret"
+
+var testNow = time.Unix(1700000000, 0)
+
+type mockService struct {
+	mu       sync.Mutex
+	requests []*conversation.ChatRequest
+	err      error
+}
+
+func (m *mockService) ProcessMessage(req *conversation.ChatRequest) (*conversation.ChatResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = append(m.requests, req)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &conversation.ChatResponse{Response: "That sounds **hard**. Want to talk about it?"}, nil
+}
+
+// fakeSlack records chat.postMessage calls the way the Web API would receive them
+type fakeSlack struct {
+	mu       sync.Mutex
+	messages []Message
+	tokens   []string
+	reply    string
+}
+
+func newFakeSlack(t *testing.T) (*fakeSlack, *httptest.Server) {
+	fake := &fakeSlack{reply: `{"ok":true,"ts":"1700000001.000100"}`}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat.postMessage" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var message Message
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("failed to decode message: %v", err)
+		}
+
+		fake.mu.Lock()
+		fake.messages = append(fake.messages, message)
+		fake.tokens = append(fake.tokens, r.Header.Get("Authorization"))
+		fake.mu.Unlock()
+
+		w.Write([]byte(fake.reply))
+	}))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func newTestHandler(t *testing.T, service ConversationService) (*Handler, *fakeSlack) {
+	fake, server := newFakeSlack(t)
+	handler := NewHandler(service, NewClient(server.URL, "xoxb-test", time.Second), testSecret)
+	handler.now = func() time.Time { return testNow }
+	return handler, fake
+}
+
+func postEvent(t *testing.T, handler *Handler, envelope any, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, _ := json.Marshal(envelope)
+	timestamp := strconv.FormatInt(testNow.Unix(), 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/slack/events", bytes.NewReader(body))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(testSecret, timestamp, body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	w := httptest.NewRecorder()
+	handler.HandleEvents(w, req)
+	handler.background.Wait()
+	return w
+}
+
+func eventCallback(event *Event) *Envelope {
+	return &Envelope{
+		Type:           TypeEventCallback,
+		TeamID:         "T123",
+		EventID:        "Ev123",
+		Event:          event,
+		Authorizations: []Authorization{{TeamID: "T123", UserID: "UBOT", IsBot: true}},
+	}
+}
+
+func TestHandleEvents_URLVerification(t *testing.T) {
+	handler, _ := newTestHandler(t, &mockService{})
+
+	w := postEvent(t, handler, &Envelope{Type: TypeURLVerification, Challenge: "abc123"}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp map[string]string
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp["challenge"] != "abc123" {
+		t.Errorf("expected the challenge back, got %v", resp)
+	}
+}
+
+func TestHandleEvents_InvalidSignature(t *testing.T) {
+	handler, _ := newTestHandler(t, &mockService{})
+
+	body := []byte(`{"type":"url_verification","challenge":"abc123"}`)
+	stale := strconv.FormatInt(testNow.Add(-10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+	}{
+		{"missing", "", ""},
+		{"wrong secret", strconv.FormatInt(testNow.Unix(), 10), Sign("other", strconv.FormatInt(testNow.Unix(), 10), body)},
+		{"stale", stale, Sign(testSecret, stale, body)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/slack/events", bytes.NewReader(body))
+			req.Header.Set(TimestampHeader, tt.timestamp)
+			req.Header.Set(SignatureHeader, tt.signature)
+			w := httptest.NewRecorder()
+
+			handler.HandleEvents(w, req)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("expected 401, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestHandleEvents_DirectMessage(t *testing.T) {
+	service := &mockService{}
+	handler, fake := newTestHandler(t, service)
+
+	w := postEvent(t, handler, eventCallback(&Event{
+		Type:        EventMessage,
+		User:        "U456",
+		Text:        "rough day &amp; I can't focus",
+		Channel:     "D789",
+		ChannelType: ChannelTypeIM,
+		TS:          "1700000000.000100",
+		ClientMsgID: "msg-1",
+	}), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	if len(service.requests) != 1 {
+		t.Fatalf("expected one processed message, got %d", len(service.requests))
+	}
+	req := service.requests[0]
+	if req.PlatformUserID != "slack:T123:U456" || req.ChannelID != "D789" || req.MessageID != "msg-1" {
+		t.Errorf("unexpected chat request %+v", req)
+	}
+	if req.Message != "rough day & I can't focus" {
+		t.Errorf("unexpected message %q", req.Message)
+	}
+
+	if len(fake.messages) != 1 {
+		t.Fatalf("expected one posted reply, got %d", len(fake.messages))
+	}
+	posted := fake.messages[0]
+	if posted.Channel != "D789" || posted.ThreadTS != "" || !posted.Mrkdwn {
+		t.Errorf("unexpected reply %+v", posted)
+	}
+	if posted.Text != "That sounds *hard*. Want to talk about it?" {
+		t.Errorf("expected mrkdwn text, got %q", posted.Text)
+	}
+	if fake.tokens[0] != "Bearer xoxb-test" {
+		t.Errorf("expected the bot token, got %q", fake.tokens[0])
+	}
+}
+
+func TestHandleEvents_MentionRepliesInThread(t *testing.T) {
+	service := &mockService{}
+	handler, fake := newTestHandler(t, service)
+
+	postEvent(t, handler, eventCallback(&Event{
+		Type:    EventAppMention,
+		User:    "U456",
+		Text:    "<@UBOT> I feel stuck",
+		Channel: "C111",
+		TS:      "1700000000.000200",
+	}), nil)
+
+	if len(service.requests) != 1 {
+		t.Fatalf("expected one processed message, got %d", len(service.requests))
+	}
+	req := service.requests[0]
+	if req.Message != "I feel stuck" || req.ChannelID != "C111:1700000000.000200" || req.MessageID != "1700000000.000200" {
+		t.Errorf("unexpected chat request %+v", req)
+	}
+
+	if len(fake.messages) != 1 || fake.messages[0].ThreadTS != "1700000000.000200" {
+		t.Errorf("expected a threaded reply, got %+v", fake.messages)
+	}
+}
+
+func TestHandleEvents_IgnoredEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   *Event
+		headers map[string]string
+	}{
+		{"channel message", &Event{Type: EventMessage, User: "U456", Text: "hi", Channel: "C111", ChannelType: "channel", TS: "1"}, nil},
+		{"bot message", &Event{Type: EventMessage, BotID: "B1", User: "UBOT", Text: "hi", Channel: "D789", ChannelType: ChannelTypeIM, TS: "1"}, nil},
+		{"edited message", &Event{Type: EventMessage, Subtype: "message_changed", Channel: "D789", ChannelType: ChannelTypeIM, TS: "1"}, nil},
+		{"bare mention", &Event{Type: EventAppMention, User: "U456", Text: "<@UBOT>", Channel: "C111", TS: "1"}, nil},
+		{"retry", &Event{Type: EventMessage, User: "U456", Text: "hi", Channel: "D789", ChannelType: ChannelTypeIM, TS: "1"},
+			map[string]string{RetryNumHeader: "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockService{}
+			handler, fake := newTestHandler(t, service)
+
+			w := postEvent(t, handler, eventCallback(tt.event), tt.headers)
+			if w.Code != http.StatusOK {
+				t.Errorf("expected 200, got %d", w.Code)
+			}
+			if len(service.requests) != 0 || len(fake.messages) != 0 {
+				t.Errorf("expected the event to be ignored, got %d requests and %d replies", len(service.requests), len(fake.messages))
+			}
+		})
+	}
+}
+
+func TestHandleEvents_ProcessingFailure(t *testing.T) {
+	handler, fake := newTestHandler(t, &mockService{err: errors.New("model unavailable")})
+
+	w := postEvent(t, handler, eventCallback(&Event{
+		Type: EventMessage, User: "U456", Text: "hi", Channel: "D789", ChannelType: ChannelTypeIM, TS: "1",
+	}), nil)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected Slack to be acknowledged, got %d", w.Code)
+	}
+	if len(fake.messages) != 0 {
+		t.Errorf("expected no reply, got %+v", fake.messages)
+	}
+}
+
+func TestClient_PostMessageError(t *testing.T) {
+	fake, server := newFakeSlack(t)
+	fake.reply = `{"ok":false,"error":"channel_not_found"}`
+
+	err := NewClient(server.URL, "xoxb-test", time.Second).PostMessage(t.Context(), &Message{Channel: "C0", Text: "hi"})
+	if err == nil || err.Error() != "chat.postMessage failed: channel_not_found" {
+		t.Errorf("expected the Web API error, got %v", err)
+	}
+}
+
+func TestSendReminder(t *testing.T) {
+	handler, fake := newTestHandler(t, &mockService{})
+
+	if err := handler.SendReminder(context.Background(), "T123:U456", "take a **walk**"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fake.messages) != 1 {
+		t.Fatalf("expected one posted message, got %d", len(fake.messages))
+	}
+	if posted := fake.messages[0]; posted.Channel != "U456" || posted.Text != "Reminder: take a *walk*" {
+		t.Errorf("expected a direct message to U456, got %+v", posted)
+	}
+}
@@ -0,0 +1,56 @@
+package slack
+
+// envelope types sent to the Events API request URL
+const (
+	TypeURLVerification = "url_verification"
+	TypeEventCallback   = "event_callback"
+)
+
+// event types Eunoia answers
+const (
+	EventMessage    = "message"
+	EventAppMention = "app_mention"
+)
+
+// ChannelTypeIM marks a direct message with the app
+const ChannelTypeIM = "im"
+
+// Envelope is the body of an Events API request
+type Envelope struct {
+	Type      string `json:"type"`
+	Token     string `json:"token,omitempty"`
+	Challenge string `json:"challenge,omitempty"`
+	TeamID    string `json:"team_id,omitempty"`
+	EventID   string `json:"event_id,omitempty"`
+	Event     *Event `json:"event,omitempty"`
+	// Authorizations name the app's own bot user, whose mentions are removed from messages
+	Authorizations []Authorization `json:"authorizations,omitempty"`
+}
+
+type Authorization struct {
+	TeamID string `json:"team_id"`
+	UserID string `json:"user_id"`
+	IsBot  bool   `json:"is_bot"`
+}
+
+// Event is a message or app_mention event
+type Event struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype,omitempty"`
+	User        string `json:"user,omitempty"`
+	BotID       string `json:"bot_id,omitempty"`
+	Text        string `json:"text"`
+	Channel     string `json:"channel"`
+	ChannelType string `json:"channel_type,omitempty"`
+	TS          string `json:"ts"`
+	ThreadTS    string `json:"thread_ts,omitempty"`
+	ClientMsgID string `json:"client_msg_id,omitempty"`
+}
+
+// Message is the body of chat.postMessage
+type Message struct {
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	ThreadTS string `json:"thread_ts,omitempty"`
+	Mrkdwn   bool   `json:"mrkdwn"`
+}
@@ -0,0 +1,45 @@
+package slack
+
+import (
+	"regexp"
+	"strings"
+)
+
+// marks bold text while single asterisks are turned into italics
+const boldMarker = "\x00"
+
+var (
+	headingPattern = regexp.MustCompile(`(?m)^#{1,6}\s+(.+?)\s*#*$`)
+	bulletPattern  = regexp.MustCompile(`(?m)^(\s*)[-*+]\s+`)
+	boldPattern    = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	italicPattern  = regexp.MustCompile(`\*([^*\n]+)\*`)
+	strikePattern  = regexp.MustCompile(`~~(.+?)~~`)
+	linkPattern    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// ToMrkdwn converts the Markdown the model writes into Slack's mrkdwn, escaping the characters
+// Slack reserves for its own markup. Fenced code blocks are left as they are.
+func ToMrkdwn(markdown string) string {
+	segments := strings.Split(markdown, "```")
+	for i := range segments {
+		if i%2 == 1 {
+			segments[i] = escaper.Replace(segments[i])
+			continue
+		}
+		segments[i] = convertSegment(segments[i])
+	}
+	return strings.Join(segments, "```")
+}
+
+func convertSegment(text string) string {
+	text = escaper.Replace(text)
+	text = headingPattern.ReplaceAllString(text, boldMarker+"$1"+boldMarker)
+	text = bulletPattern.ReplaceAllString(text, "$1• ")
+	text = boldPattern.ReplaceAllString(text, boldMarker+"$1$2"+boldMarker)
+	text = italicPattern.ReplaceAllString(text, "_${1}_")
+	text = strikePattern.ReplaceAllString(text, "~$1~")
+	text = linkPattern.ReplaceAllString(text, "<$2|$1>")
+	return strings.ReplaceAll(text, boldMarker, "*")
+}
@@ -0,0 +1,30 @@
+package slack
+
+import "testing"
+
+func TestToMrkdwn(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "hello there", "hello there"},
+		{"bold", "this is **important**", "this is *important*"},
+		{"italic", "a *gentle* reminder", "a _gentle_ reminder"},
+		{"bold and italic", "**rest** and *breathe*", "*rest* and _breathe_"},
+		{"strike", "~~never~~", "~never~"},
+		{"link", "see [this guide](https://example.com/a?b=1)", "see <https://example.com/a?b=1|this guide>"},
+		{"heading", "## Things to try", "*Things to try*"},
+		{"bullets", "- walk\n* stretch", "• walk\n• stretch"},
+		{"escaping", "1 < 2 & 3 > 2", "1 &lt; 2 &amp; 3 &gt; 2"},
+		{"code block", "run:\n```**not bold** <tag>```", "run:\n```**not bold** &lt;tag&gt;```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToMrkdwn(tt.in); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package task
+
+import (
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
+// Task is the persisted state of an A2A task
+type Task struct {
+	ID             string                 `json:"id"`
+	ContextID      string                 `json:"context_id"`
+	PlatformUserID string                 `json:"platform_user_id"`
+	State          string                 `json:"state"`
+	StatusMessage  *a2a.A2AMessageResult  `json:"status_message,omitempty"`
+	History        []a2a.A2AMessageResult `json:"history"`
+	Artifacts      []a2a.A2AArtifact      `json:"artifacts"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+// IsTerminal reports whether a task in this state can no longer change
+func IsTerminal(state string) bool {
+	switch state {
+	case a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled:
+		return true
+	}
+	return false
+}
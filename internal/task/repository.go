@@ -0,0 +1,167 @@
+package task
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateTask(task *Task) error {
+	query := `INSERT INTO tasks (id, context_id, platform_user_id, state, status_message, history, artifacts, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	statusMessage, history, artifacts, err := encodeTask(task)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(query, task.ID, task.ContextID, task.PlatformUserID, task.State, statusMessage,
+		history, artifacts, task.CreatedAt, task.UpdatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTask returns nil when no task has the given ID
+func (r *Repository) GetTask(taskID string) (*Task, error) {
+	query := `SELECT id, context_id, platform_user_id, state, status_message, history, artifacts, created_at, updated_at
+			  FROM tasks
+			  WHERE id = ?`
+
+	task := &Task{}
+	var statusMessage, history, artifacts []byte
+	err := r.db.QueryRow(query, taskID).Scan(&task.ID, &task.ContextID, &task.PlatformUserID, &task.State,
+		&statusMessage, &history, &artifacts, &task.CreatedAt, &task.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decodeTask(task, statusMessage, history, artifacts); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// UpdateTask saves a task's state and contents unless it has already reached a final state,
+// reporting whether it was updated. An empty context ID keeps the stored one.
+func (r *Repository) UpdateTask(task *Task) (bool, error) {
+	query := `UPDATE tasks
+			  SET context_id = COALESCE(NULLIF(?, ''), context_id), state = ?, status_message = ?,
+			  history = ?, artifacts = ?, updated_at = ?
+			  WHERE id = ? AND state NOT IN (?, ?, ?)`
+
+	statusMessage, history, artifacts, err := encodeTask(task)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.db.Exec(query, task.ContextID, task.State, statusMessage, history, artifacts, task.UpdatedAt, task.ID,
+		a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// UpdateState moves a task that is not yet final to a new state, reporting whether it changed
+func (r *Repository) UpdateState(taskID, state string, updatedAt time.Time) (bool, error) {
+	query := `UPDATE tasks SET state = ?, updated_at = ? WHERE id = ? AND state NOT IN (?, ?, ?)`
+
+	result, err := r.db.Exec(query, state, updatedAt, taskID,
+		a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func encodeTask(task *Task) ([]byte, []byte, []byte, error) {
+	var statusMessage []byte
+	if task.StatusMessage != nil {
+		encoded, err := json.Marshal(task.StatusMessage)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		statusMessage = encoded
+	}
+
+	history, err := json.Marshal(nonNilHistory(task.History))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	artifacts, err := json.Marshal(nonNilArtifacts(task.Artifacts))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return statusMessage, history, artifacts, nil
+}
+
+func decodeTask(task *Task, statusMessage, history, artifacts []byte) error {
+	if len(statusMessage) > 0 {
+		task.StatusMessage = &a2a.A2AMessageResult{}
+		if err := json.Unmarshal(statusMessage, task.StatusMessage); err != nil {
+			return err
+		}
+	}
+
+	if len(history) > 0 {
+		if err := json.Unmarshal(history, &task.History); err != nil {
+			return err
+		}
+	}
+
+	if len(artifacts) > 0 {
+		if err := json.Unmarshal(artifacts, &task.Artifacts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func nonNilHistory(history []a2a.A2AMessageResult) []a2a.A2AMessageResult {
+	if history == nil {
+		return []a2a.A2AMessageResult{}
+	}
+	return history
+}
+
+func nonNilArtifacts(artifacts []a2a.A2AArtifact) []a2a.A2AArtifact {
+	if artifacts == nil {
+		return []a2a.A2AArtifact{}
+	}
+	return artifacts
+}
@@ -0,0 +1,134 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
+func TestCreateTask(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	task := &Task{
+		ID:             "task-123",
+		ContextID:      "context-456",
+		PlatformUserID: "user-789",
+		State:          a2a.TaskStateSubmitted,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	mock.ExpectExec("INSERT INTO tasks").
+		WithArgs(task.ID, task.ContextID, task.PlatformUserID, task.State, []byte(nil), []byte("[]"), []byte("[]"),
+			task.CreatedAt, task.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := repo.CreateTask(task); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetTask(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "context_id", "platform_user_id", "state", "status_message",
+		"history", "artifacts", "created_at", "updated_at"}).
+		AddRow("task-123", "context-456", "user-789", a2a.TaskStateCompleted,
+			[]byte(`{"kind":"message","role":"agent","parts":[{"kind":"text","text":"Hi"}],"messageId":"m-2"}`),
+			[]byte(`[{"kind":"message","role":"user","parts":[{"kind":"text","text":"Hello"}],"messageId":"m-1"}]`),
+			[]byte(`[]`), now, now)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks").
+		WithArgs("task-123").
+		WillReturnRows(rows)
+
+	task, err := repo.GetTask("task-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if task == nil {
+		t.Fatal("expected task, got nil")
+	}
+	if task.StatusMessage == nil || task.StatusMessage.Parts[0].Text != "Hi" {
+		t.Errorf("expected decoded status message, got %+v", task.StatusMessage)
+	}
+	if len(task.History) != 1 || task.History[0].MessageID != "m-1" {
+		t.Errorf("expected decoded history, got %+v", task.History)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetTask_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	task, err := repo.GetTask("missing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if task != nil {
+		t.Errorf("expected nil task, got %+v", task)
+	}
+}
+
+func TestUpdateState_FinishedTask(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE tasks SET state").
+		WithArgs(a2a.TaskStateCanceled, now, "task-123",
+			a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	updated, err := repo.UpdateState("task-123", a2a.TaskStateCanceled, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if updated {
+		t.Error("expected a finished task not to be updated")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/a2a"
+	"github.com/zjoart/eunoia/pkg/id"
+)
+
+var (
+	ErrTaskNotFound      = errors.New("task not found")
+	ErrTaskNotCancelable = errors.New("task cannot be canceled")
+	// ErrTaskFinished is returned when updating a task that was canceled or finished elsewhere
+	ErrTaskFinished = errors.New("task already finished")
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+// Create stores a new task in the submitted state
+func (s *Service) Create(platformUserID, contextID string) (*Task, error) {
+	if contextID == "" {
+		contextID = id.Generate()
+	}
+
+	now := time.Now()
+	task := &Task{
+		ID:             id.Generate(),
+		ContextID:      contextID,
+		PlatformUserID: platformUserID,
+		State:          a2a.TaskStateSubmitted,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.repo.CreateTask(task); err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+
+	return task, nil
+}
+
+// SetState moves a task to a non-final state such as working
+func (s *Service) SetState(taskID, state string) error {
+	updated, err := s.repo.UpdateState(taskID, state, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+	if !updated {
+		return ErrTaskFinished
+	}
+
+	return nil
+}
+
+// Finish saves the result of processing a task
+func (s *Service) Finish(taskID string, result *a2a.A2AResult) error {
+	task := &Task{
+		ID:            taskID,
+		ContextID:     result.ContextID,
+		State:         result.Status.State,
+		StatusMessage: &result.Status.Message,
+		History:       result.History,
+		Artifacts:     result.Artifacts,
+		UpdatedAt:     time.Now(),
+	}
+
+	updated, err := s.repo.UpdateTask(task)
+	if err != nil {
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+	if !updated {
+		return ErrTaskFinished
+	}
+
+	return nil
+}
+
+// Fail marks a task as failed with an agent message explaining why
+func (s *Service) Fail(taskID, reason string) error {
+	task := &Task{
+		ID:    taskID,
+		State: a2a.TaskStateFailed,
+		StatusMessage: &a2a.A2AMessageResult{
+			MessageID: id.Generate(),
+			Role:      "agent",
+			Parts:     []a2a.A2APart{{Kind: "text", Text: reason}},
+			Kind:      "message",
+			TaskID:    taskID,
+		},
+		UpdatedAt: time.Now(),
+	}
+
+	if _, err := s.repo.UpdateTask(task); err != nil {
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns a task as an A2A result, keeping only the last historyLength messages when set
+func (s *Service) Get(taskID string, historyLength *int) (*a2a.A2AResult, error) {
+	task, err := s.repo.GetTask(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	return ToResult(task, historyLength), nil
+}
+
+// GetForUser is Get for a task the platform user owns; another user's task is reported as not found
+func (s *Service) GetForUser(taskID, platformUserID string, historyLength *int) (*a2a.A2AResult, error) {
+	task, err := s.getOwned(taskID, platformUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToResult(task, historyLength), nil
+}
+
+// Cancel stops a task of the platform user's that has not finished yet
+func (s *Service) Cancel(taskID, platformUserID string) (*a2a.A2AResult, error) {
+	task, err := s.getOwned(taskID, platformUserID)
+	if err != nil {
+		return nil, err
+	}
+	if IsTerminal(task.State) {
+		return nil, ErrTaskNotCancelable
+	}
+
+	now := time.Now()
+	updated, err := s.repo.UpdateState(taskID, a2a.TaskStateCanceled, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel task: %w", err)
+	}
+	if !updated {
+		// it finished between the read and the update
+		return nil, ErrTaskNotCancelable
+	}
+
+	task.State = a2a.TaskStateCanceled
+	task.UpdatedAt = now
+
+	return ToResult(task, nil), nil
+}
+
+func (s *Service) getOwned(taskID, platformUserID string) (*Task, error) {
+	task, err := s.repo.GetTask(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if task == nil || task.PlatformUserID != platformUserID {
+		return nil, ErrTaskNotFound
+	}
+
+	return task, nil
+}
+
+// ToResult converts a stored task into its A2A representation
+func ToResult(task *Task, historyLength *int) *a2a.A2AResult {
+	history := task.History
+	if historyLength != nil && *historyLength >= 0 && len(history) > *historyLength {
+		history = history[len(history)-*historyLength:]
+	}
+
+	status := a2a.A2ATaskStatus{
+		State:     task.State,
+		Timestamp: task.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if task.StatusMessage != nil {
+		status.Message = *task.StatusMessage
+	}
+
+	return &a2a.A2AResult{
+		ID:        task.ID,
+		ContextID: task.ContextID,
+		Status:    status,
+		Artifacts: task.Artifacts,
+		History:   history,
+		Kind:      "task",
+	}
+}
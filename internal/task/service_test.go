@@ -0,0 +1,124 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/zjoart/eunoia/pkg/a2a"
+)
+
+func TestToResult_HistoryLength(t *testing.T) {
+	task := &Task{
+		ID:        "task-1",
+		ContextID: "context-1",
+		State:     a2a.TaskStateCompleted,
+		History: []a2a.A2AMessageResult{
+			{MessageID: "m-1"}, {MessageID: "m-2"}, {MessageID: "m-3"},
+		},
+		UpdatedAt: time.Now(),
+	}
+
+	tests := []struct {
+		name          string
+		historyLength *int
+		expected      int
+	}{
+		{"unset keeps everything", nil, 3},
+		{"zero drops history", intPtr(0), 0},
+		{"keeps the latest", intPtr(2), 2},
+		{"longer than history", intPtr(10), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ToResult(task, tt.historyLength)
+			if len(result.History) != tt.expected {
+				t.Fatalf("expected %d messages, got %d", tt.expected, len(result.History))
+			}
+			if tt.expected > 0 && result.History[len(result.History)-1].MessageID != "m-3" {
+				t.Errorf("expected the latest message to be kept")
+			}
+		})
+	}
+}
+
+func TestCancel_FinishedTask(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := NewService(NewRepository(db))
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "context_id", "platform_user_id", "state", "status_message",
+		"history", "artifacts", "created_at", "updated_at"}).
+		AddRow("task-1", "context-1", "user-1", a2a.TaskStateCompleted, nil, []byte(`[]`), []byte(`[]`), now, now)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks").
+		WithArgs("task-1").
+		WillReturnRows(rows)
+
+	if _, err := service.Cancel("task-1", "user-1"); !errors.Is(err, ErrTaskNotCancelable) {
+		t.Errorf("expected ErrTaskNotCancelable, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetForUser_OtherUsersTask(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := NewService(NewRepository(db))
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery("SELECT (.+) FROM tasks").
+			WithArgs("task-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "context_id", "platform_user_id", "state", "status_message",
+				"history", "artifacts", "created_at", "updated_at"}).
+				AddRow("task-1", "context-1", "telex:user-1", a2a.TaskStateWorking, nil, []byte(`[]`), []byte(`[]`), now, now))
+	}
+
+	if _, err := service.GetForUser("task-1", "telex:user-2", nil); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expected another user's task to be hidden, got %v", err)
+	}
+	if _, err := service.Cancel("task-1", "telex:user-2"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expected another user's task not to be canceled, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGet_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := NewService(NewRepository(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := service.Get("missing", nil); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -0,0 +1,118 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the base URL of the Telegram Bot API
+const DefaultAPIURL = "https://api.telegram.org"
+
+// Client calls the Bot API with a bot token
+type Client struct {
+	baseURL    string
+	token      string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// NewClient creates a Bot API client; timeout bounds each call, on top of a long poll's own wait
+func NewClient(baseURL, token string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		timeout:    timeout,
+		httpClient: &http.Client{},
+	}
+}
+
+// SendMessage calls sendMessage
+func (c *Client) SendMessage(ctx context.Context, message *SendMessageRequest) error {
+	return c.call(ctx, "sendMessage", message, nil, 0)
+}
+
+// AnswerCallbackQuery stops the client's loading indicator on a pressed button, optionally showing text
+func (c *Client) AnswerCallbackQuery(ctx context.Context, callbackQueryID, text string) error {
+	params := map[string]string{"callback_query_id": callbackQueryID}
+	if text != "" {
+		params["text"] = text
+	}
+	return c.call(ctx, "answerCallbackQuery", params, nil, 0)
+}
+
+// SendTyping shows the typing indicator in a chat while a reply is generated
+func (c *Client) SendTyping(ctx context.Context, chatID int64) error {
+	return c.call(ctx, "sendChatAction", map[string]any{"chat_id": chatID, "action": "typing"}, nil, 0)
+}
+
+// GetUpdates long polls for updates after offset, waiting up to wait for one to arrive
+func (c *Client) GetUpdates(ctx context.Context, offset int64, wait time.Duration) ([]Update, error) {
+	params := map[string]any{
+		"offset":          offset,
+		"timeout":         int(wait.Seconds()),
+		"allowed_updates": []string{"message", "callback_query"},
+	}
+
+	var updates []Update
+	if err := c.call(ctx, "getUpdates", params, &updates, wait); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// DeleteWebhook removes the webhook, which the Bot API requires before getUpdates can be used
+func (c *Client) DeleteWebhook(ctx context.Context) error {
+	return c.call(ctx, "deleteWebhook", map[string]any{}, nil, 0)
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result any, wait time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout+wait)
+	defer cancel()
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s params: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/bot"+c.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// the URL carries the token, so only the method is reported
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s request failed: %w", method, ctx.Err())
+		}
+		return fmt.Errorf("%s request failed", method)
+	}
+	defer resp.Body.Close()
+
+	// failures come back as JSON with ok set to false, whatever the status
+	var decoded struct {
+		OK          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		Description string          `json:"description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 8<<20)).Decode(&decoded); err != nil {
+		return fmt.Errorf("failed to decode %s response (status %d): %w", method, resp.StatusCode, err)
+	}
+	if !decoded.OK {
+		return fmt.Errorf("%s failed: %s", method, decoded.Description)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(decoded.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s result: %w", method, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package telegram
+
+import "strings"
+
+// characters MarkdownV2 reserves, which must be escaped wherever they appear as text
+var markdownV2Escaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+	"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// EscapeMarkdownV2 escapes text so MarkdownV2 shows it literally
+func EscapeMarkdownV2(text string) string {
+	return markdownV2Escaper.Replace(text)
+}
+
+// ToMarkdownV2 escapes a reply for MarkdownV2, keeping the model's **bold** spans bold. An unpaired
+// ** is shown as written.
+func ToMarkdownV2(markdown string) string {
+	segments := strings.Split(markdown, "**")
+
+	var b strings.Builder
+	for i, segment := range segments {
+		switch {
+		case i%2 == 0:
+			b.WriteString(EscapeMarkdownV2(segment))
+		case i == len(segments)-1:
+			b.WriteString(EscapeMarkdownV2("**" + segment))
+		case segment == "":
+			b.WriteString(EscapeMarkdownV2("****"))
+		default:
+			b.WriteString("*" + EscapeMarkdownV2(segment) + "*")
+		}
+	}
+	return b.String()
+}
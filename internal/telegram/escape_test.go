@@ -0,0 +1,27 @@
+package telegram
+
+import "testing"
+
+func TestToMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "hello", "hello"},
+		{"reserved", "Take 5-10 min. (Really!)", `Take 5\-10 min\. \(Really\!\)`},
+		{"bold", "a **small** step", `a *small* step`},
+		{"escaped bold", "**1.** breathe", `*1\.* breathe`},
+		{"unpaired", "2 ** 3", `2 \*\* 3`},
+		{"single asterisk", "*sigh*", `\*sigh\*`},
+		{"backslash", `C:\path`, `C:\\path`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToMarkdownV2(tt.in); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
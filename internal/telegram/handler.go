@@ -0,0 +1,238 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/conversation"
+	"github.com/zjoart/eunoia/internal/conversation/platforms"
+	"github.com/zjoart/eunoia/pkg/a2a"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// SecretTokenHeader carries the secret_token given to setWebhook on every webhook call
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// callback data of the mood buttons is the prefix and the score
+const moodCallbackPrefix = "mood:"
+
+// largest webhook body accepted
+const maxBodyBytes = 1 << 20
+
+// how long answering one update may take, model call included
+const updateTimeout = 2 * time.Minute
+
+const (
+	welcomeText = "Hi, I'm Eunoia. You can tell me how your day is going, or tap a number to check in with your mood."
+	checkInText = "How are you feeling right now, from 1 (very low) to 10 (great)?"
+)
+
+// ConversationService is the part of the conversation service the adapter needs
+type ConversationService interface {
+	ProcessMessage(req *conversation.ChatRequest) (*conversation.ChatResponse, error)
+}
+
+// Handler answers private chats with the bot, from webhook calls or a Poller
+type Handler struct {
+	service     ConversationService
+	client      *Client
+	platform    *platforms.TelegramPlatform
+	secretToken string
+	// background tracks updates still being answered after the webhook call returned
+	background sync.WaitGroup
+}
+
+// NewHandler creates the Telegram adapter; secretToken is only checked on webhook calls
+func NewHandler(service ConversationService, client *Client, secretToken string) *Handler {
+	return &Handler{
+		service:     service,
+		client:      client,
+		platform:    platforms.NewTelegramPlatform(),
+		secretToken: secretToken,
+	}
+}
+
+// HandleWebhook serves the webhook URL, answering updates in the background so Telegram
+// does not redeliver them while the model is slow
+func (h *Handler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	provided := r.Header.Get(SecretTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(provided), []byte(h.secretToken)) != 1 {
+		logger.Warn("rejected telegram webhook with invalid secret token", logger.Fields{"path": r.URL.Path})
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var update Update
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodyBytes)).Decode(&update); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	h.background.Add(1)
+	go func() {
+		defer h.background.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+		defer cancel()
+		h.HandleUpdate(ctx, &update)
+	}()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleUpdate answers one update: commands, mood buttons and free text in private chats
+func (h *Handler) HandleUpdate(ctx context.Context, update *Update) {
+	switch {
+	case update.CallbackQuery != nil:
+		h.handleCallback(ctx, update.CallbackQuery)
+	case update.Message != nil:
+		h.handleMessage(ctx, update.Message)
+	}
+}
+
+func (h *Handler) handleMessage(ctx context.Context, message *Message) {
+	if message.Chat.Type != ChatTypePrivate || message.From == nil || message.From.IsBot {
+		return
+	}
+
+	text := strings.TrimSpace(message.Text)
+	if text == "" {
+		return
+	}
+
+	switch command(text) {
+	case "/start":
+		h.send(ctx, message.Chat.ID, EscapeMarkdownV2(welcomeText), moodKeyboard())
+		return
+	case "/checkin", "/mood":
+		h.send(ctx, message.Chat.ID, EscapeMarkdownV2(checkInText), moodKeyboard())
+		return
+	}
+
+	h.reply(ctx, message.From, message.Chat, message.MessageID, text, nil)
+}
+
+func (h *Handler) handleCallback(ctx context.Context, query *CallbackQuery) {
+	score, ok := moodScore(query.Data)
+	if !ok || query.Message == nil || query.Message.Chat.Type != ChatTypePrivate {
+		h.answerCallback(ctx, query.ID, "")
+		return
+	}
+
+	h.answerCallback(ctx, query.ID, fmt.Sprintf("Mood %d/10 noted", score))
+
+	data := []map[string]interface{}{{"mood_score": float64(score)}}
+	h.reply(ctx, &query.From, query.Message.Chat, query.Message.MessageID, "", data)
+}
+
+// reply passes a message or check-in to the conversation service and sends the answer to the chat
+func (h *Handler) reply(ctx context.Context, from *User, chat Chat, messageID int64, text string, data []map[string]interface{}) {
+	metadata := map[string]interface{}{
+		platforms.TelegramUserKey: strconv.FormatInt(from.ID, 10),
+		platforms.TelegramChatKey: strconv.FormatInt(chat.ID, 10),
+	}
+
+	fields := logger.Fields{"chat_id": chat.ID, "message_id": messageID}
+
+	userID, err := h.platform.ExtractUserID(metadata)
+	if err != nil {
+		return
+	}
+	channelID, _ := h.platform.ExtractChannelID(metadata)
+
+	if err := h.client.SendTyping(ctx, chat.ID); err != nil {
+		logger.Warn("failed to send telegram typing indicator", logger.Merge(logger.WithError(err), fields))
+	}
+
+	resp, err := h.service.ProcessMessage(&conversation.ChatRequest{
+		PlatformUserID: platforms.NamespacedUserID(h.platform.Name(), userID),
+		Message:        h.platform.ExtractMessage([]a2a.A2APart{{Kind: a2a.PartKindText, Text: text}}),
+		MessageID:      fmt.Sprintf("%d:%d", chat.ID, messageID),
+		ChannelID:      channelID,
+		Data:           data,
+	})
+	if err != nil {
+		logger.Error("failed to process telegram message", logger.Merge(logger.WithError(err), fields))
+		return
+	}
+
+	h.send(ctx, chat.ID, ToMarkdownV2(resp.Response), nil)
+}
+
+func (h *Handler) send(ctx context.Context, chatID int64, text string, keyboard *InlineKeyboardMarkup) {
+	err := h.client.SendMessage(ctx, &SendMessageRequest{
+		ChatID:      chatID,
+		Text:        text,
+		ParseMode:   ParseModeMarkdownV2,
+		ReplyMarkup: keyboard,
+	})
+	if err != nil {
+		logger.Error("failed to send telegram message", logger.Merge(
+			logger.WithError(err),
+			logger.Fields{"chat_id": chatID},
+		))
+	}
+}
+
+// SendReminder sends a due reminder to the user's private chat with the bot, whose ID is the user's
+func (h *Handler) SendReminder(ctx context.Context, userID, note string) error {
+	chatID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid telegram user id %q", userID)
+	}
+
+	return h.client.SendMessage(ctx, &SendMessageRequest{
+		ChatID:    chatID,
+		Text:      ToMarkdownV2("Reminder: " + note),
+		ParseMode: ParseModeMarkdownV2,
+	})
+}
+
+func (h *Handler) answerCallback(ctx context.Context, queryID, text string) {
+	if err := h.client.AnswerCallbackQuery(ctx, queryID, text); err != nil {
+		logger.Warn("failed to answer telegram callback query", logger.WithError(err))
+	}
+}
+
+// moodKeyboard offers the scores 1 to 10 as two rows of buttons
+func moodKeyboard() *InlineKeyboardMarkup {
+	keyboard := &InlineKeyboardMarkup{InlineKeyboard: make([][]InlineKeyboardButton, 2)}
+	for score := 1; score <= 10; score++ {
+		row := (score - 1) / 5
+		keyboard.InlineKeyboard[row] = append(keyboard.InlineKeyboard[row], InlineKeyboardButton{
+			Text:         strconv.Itoa(score),
+			CallbackData: moodCallbackPrefix + strconv.Itoa(score),
+		})
+	}
+	return keyboard
+}
+
+func moodScore(data string) (int, bool) {
+	value, ok := strings.CutPrefix(data, moodCallbackPrefix)
+	if !ok {
+		return 0, false
+	}
+	score, err := strconv.Atoi(value)
+	if err != nil || score < 1 || score > 10 {
+		return 0, false
+	}
+	return score, true
+}
+
+// command returns the command a message starts with, without any @botname suffix
+func command(text string) string {
+	if !strings.HasPrefix(text, "/") {
+		return ""
+	}
+	name := strings.Fields(text)[0]
+	name, _, _ = strings.Cut(name, "@")
+	return strings.ToLower(name)
+}
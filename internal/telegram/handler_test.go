@@ -0,0 +1,281 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/conversation"
+)
+
+type mockService struct {
+	mu       sync.Mutex
+	requests []*conversation.ChatRequest
+}
+
+func (m *mockService) ProcessMessage(req *conversation.ChatRequest) (*conversation.ChatResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = append(m.requests, req)
+	return &conversation.ChatResponse{Response: "Thanks for sharing. That's **really** okay."}, nil
+}
+
+type apiCall struct {
+	method string
+	params map[string]any
+}
+
+// fakeBotAPI records Bot API calls and serves queued getUpdates batches
+type fakeBotAPI struct {
+	mu      sync.Mutex
+	calls   []apiCall
+	updates [][]Update
+	cancel  context.CancelFunc
+}
+
+func newFakeBotAPI(t *testing.T) (*fakeBotAPI, *Client) {
+	fake := &fakeBotAPI{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, method, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/bot"), "/")
+		if token != "test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"ok":false,"error_code":401,"description":"Unauthorized"}`))
+			return
+		}
+
+		var params map[string]any
+		json.NewDecoder(r.Body).Decode(&params)
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		fake.calls = append(fake.calls, apiCall{method: method, params: params})
+
+		result := any(true)
+		if method == "getUpdates" {
+			if len(fake.updates) == 0 {
+				fake.cancel()
+				result = []Update{}
+			} else {
+				result = fake.updates[0]
+				fake.updates = fake.updates[1:]
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, NewClient(server.URL, "test-token", time.Second)
+}
+
+func (f *fakeBotAPI) sent(method string) []map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var params []map[string]any
+	for _, call := range f.calls {
+		if call.method == method {
+			params = append(params, call.params)
+		}
+	}
+	return params
+}
+
+func privateMessage(text string) *Update {
+	return &Update{
+		UpdateID: 1,
+		Message: &Message{
+			MessageID: 42,
+			From:      &User{ID: 1001, FirstName: "Ada"},
+			Chat:      Chat{ID: 1001, Type: ChatTypePrivate},
+			Text:      text,
+		},
+	}
+}
+
+func postWebhook(t *testing.T, handler *Handler, update *Update, secret string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, _ := json.Marshal(update)
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", bytes.NewReader(body))
+	req.Header.Set(SecretTokenHeader, secret)
+	w := httptest.NewRecorder()
+
+	handler.HandleWebhook(w, req)
+	handler.background.Wait()
+	return w
+}
+
+func TestHandleWebhook_SecretToken(t *testing.T) {
+	service := &mockService{}
+	_, client := newFakeBotAPI(t)
+	handler := NewHandler(service, client, "webhook-secret")
+
+	for _, secret := range []string{"", "wrong"} {
+		if w := postWebhook(t, handler, privateMessage("hi"), secret); w.Code != http.StatusUnauthorized {
+			t.Errorf("secret %q: expected 401, got %d", secret, w.Code)
+		}
+	}
+	if len(service.requests) != 0 {
+		t.Errorf("expected no processed messages, got %d", len(service.requests))
+	}
+}
+
+func TestHandleWebhook_Message(t *testing.T) {
+	service := &mockService{}
+	fake, client := newFakeBotAPI(t)
+	handler := NewHandler(service, client, "webhook-secret")
+
+	if w := postWebhook(t, handler, privateMessage("long week, feeling drained"), "webhook-secret"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	if len(service.requests) != 1 {
+		t.Fatalf("expected one processed message, got %d", len(service.requests))
+	}
+	req := service.requests[0]
+	if req.PlatformUserID != "telegram:1001" || req.ChannelID != "1001" || req.MessageID != "1001:42" || req.Message != "long week, feeling drained" {
+		t.Errorf("unexpected chat request %+v", req)
+	}
+
+	if typing := fake.sent("sendChatAction"); len(typing) != 1 || typing[0]["action"] != "typing" {
+		t.Errorf("expected a typing indicator, got %v", typing)
+	}
+
+	sent := fake.sent("sendMessage")
+	if len(sent) != 1 {
+		t.Fatalf("expected one reply, got %d", len(sent))
+	}
+	if sent[0]["text"] != `Thanks for sharing\. That's *really* okay\.` || sent[0]["parse_mode"] != ParseModeMarkdownV2 {
+		t.Errorf("unexpected reply %v", sent[0])
+	}
+}
+
+func TestHandleUpdate_CheckInKeyboard(t *testing.T) {
+	service := &mockService{}
+	fake, client := newFakeBotAPI(t)
+	handler := NewHandler(service, client, "")
+
+	handler.HandleUpdate(t.Context(), privateMessage("/checkin@EunoiaBot"))
+
+	if len(service.requests) != 0 {
+		t.Errorf("expected the command to be answered without the model, got %d requests", len(service.requests))
+	}
+
+	sent := fake.sent("sendMessage")
+	if len(sent) != 1 {
+		t.Fatalf("expected one message, got %d", len(sent))
+	}
+	markup, _ := sent[0]["reply_markup"].(map[string]any)
+	rows, _ := markup["inline_keyboard"].([]any)
+	if len(rows) != 2 || len(rows[0].([]any)) != 5 || len(rows[1].([]any)) != 5 {
+		t.Fatalf("expected two rows of five buttons, got %v", markup)
+	}
+	last := rows[1].([]any)[4].(map[string]any)
+	if last["text"] != "10" || last["callback_data"] != "mood:10" {
+		t.Errorf("unexpected button %v", last)
+	}
+}
+
+func TestHandleUpdate_MoodButton(t *testing.T) {
+	service := &mockService{}
+	fake, client := newFakeBotAPI(t)
+	handler := NewHandler(service, client, "")
+
+	message := privateMessage(checkInText).Message
+	handler.HandleUpdate(t.Context(), &Update{
+		UpdateID:      2,
+		CallbackQuery: &CallbackQuery{ID: "cb-1", From: *message.From, Message: message, Data: "mood:7"},
+	})
+
+	if answers := fake.sent("answerCallbackQuery"); len(answers) != 1 || answers[0]["text"] != "Mood 7/10 noted" {
+		t.Errorf("expected the button press to be answered, got %v", answers)
+	}
+
+	if len(service.requests) != 1 {
+		t.Fatalf("expected one processed check-in, got %d", len(service.requests))
+	}
+	req := service.requests[0]
+	if req.Message != "" || len(req.Data) != 1 || req.Data[0]["mood_score"] != float64(7) {
+		t.Errorf("expected a data check-in, got %+v", req)
+	}
+	if len(fake.sent("sendMessage")) != 1 {
+		t.Error("expected a reply to the check-in")
+	}
+}
+
+func TestHandleUpdate_Ignored(t *testing.T) {
+	group := privateMessage("hello everyone")
+	group.Message.Chat.Type = "group"
+
+	bot := privateMessage("hi")
+	bot.Message.From.IsBot = true
+
+	invalid := &Update{CallbackQuery: &CallbackQuery{ID: "cb-2", Message: privateMessage("").Message, Data: "mood:11"}}
+
+	for name, update := range map[string]*Update{"group": group, "bot": bot, "invalid mood": invalid} {
+		t.Run(name, func(t *testing.T) {
+			service := &mockService{}
+			fake, client := newFakeBotAPI(t)
+			NewHandler(service, client, "").HandleUpdate(t.Context(), update)
+
+			if len(service.requests) != 0 || len(fake.sent("sendMessage")) != 0 {
+				t.Errorf("expected the update to be ignored")
+			}
+		})
+	}
+}
+
+func TestPoller_Run(t *testing.T) {
+	service := &mockService{}
+	fake, client := newFakeBotAPI(t)
+	handler := NewHandler(service, client, "")
+
+	first := privateMessage("first")
+	second := privateMessage("second")
+	second.UpdateID = 2
+	fake.updates = [][]Update{{*first, *second}}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	fake.cancel = cancel
+
+	if err := NewPoller(client, handler).Run(ctx); err != context.Canceled {
+		t.Fatalf("expected the poller to stop when cancelled, got %v", err)
+	}
+
+	if len(fake.sent("deleteWebhook")) != 1 {
+		t.Error("expected the webhook to be removed before polling")
+	}
+	if len(service.requests) != 2 {
+		t.Errorf("expected both updates to be answered, got %d", len(service.requests))
+	}
+
+	polls := fake.sent("getUpdates")
+	if len(polls) != 2 || polls[1]["offset"] != float64(3) {
+		t.Errorf("expected the second poll to confirm both updates, got %v", polls)
+	}
+}
+
+func TestSendReminder(t *testing.T) {
+	fake, client := newFakeBotAPI(t)
+	handler := NewHandler(&mockService{}, client, "")
+
+	if err := handler.SendReminder(context.Background(), "42", "take a walk."); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sent := fake.sent("sendMessage")
+	if len(sent) != 1 || sent[0]["chat_id"] != float64(42) || sent[0]["text"] != `Reminder: take a walk\.` {
+		t.Errorf("expected the reminder in the user's private chat, got %+v", sent)
+	}
+
+	if err := handler.SendReminder(context.Background(), "not-a-number", "x"); err == nil {
+		t.Error("expected an invalid user ID to be rejected")
+	}
+}
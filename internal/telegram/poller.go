@@ -0,0 +1,60 @@
+package telegram
+
+import (
+	"context"
+	"time"
+
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// how long one getUpdates call waits for an update, and the pause after a failed call
+const (
+	pollWait     = 30 * time.Second
+	pollFailWait = 5 * time.Second
+)
+
+// Poller fetches updates with getUpdates instead of a webhook, for local development
+type Poller struct {
+	client  *Client
+	handler *Handler
+}
+
+func NewPoller(client *Client, handler *Handler) *Poller {
+	return &Poller{client: client, handler: handler}
+}
+
+// Run answers updates one at a time until ctx ends. It removes any webhook first, since the
+// Bot API refuses getUpdates while one is set.
+func (p *Poller) Run(ctx context.Context) error {
+	if err := p.client.DeleteWebhook(ctx); err != nil {
+		return err
+	}
+
+	logger.Info("telegram long polling started")
+
+	var offset int64
+	for {
+		updates, err := p.client.GetUpdates(ctx, offset, pollWait)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			logger.Warn("telegram getUpdates failed", logger.WithError(err))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pollFailWait):
+			}
+			continue
+		}
+
+		for i := range updates {
+			// confirmed on the next call, so an update is not fetched again once handled
+			offset = updates[i].UpdateID + 1
+
+			updateCtx, cancel := context.WithTimeout(ctx, updateTimeout)
+			p.handler.HandleUpdate(updateCtx, &updates[i])
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/user"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+const (
+	dateLayout        = "2006-01-02"
+	defaultReportDays = 30
+)
+
+type Handler struct {
+	service ServiceInterface
+}
+
+func NewHandler(service ServiceInterface) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) HandleReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	req := &ReportRequest{
+		From:           today.AddDate(0, 0, -defaultReportDays),
+		To:             today.AddDate(0, 0, 1),
+		GroupBy:        query.Get("group_by"),
+		PlatformUserID: query.Get("platform_user_id"),
+	}
+
+	if from := query.Get("from"); from != "" {
+		date, err := time.ParseInLocation(dateLayout, from, now.Location())
+		if err != nil {
+			h.sendJSONError(w, http.StatusBadRequest, "from must be a date in YYYY-MM-DD format")
+			return
+		}
+		req.From = date
+	}
+
+	if to := query.Get("to"); to != "" {
+		date, err := time.ParseInLocation(dateLayout, to, now.Location())
+		if err != nil {
+			h.sendJSONError(w, http.StatusBadRequest, "to must be a date in YYYY-MM-DD format")
+			return
+		}
+		// make the end date inclusive
+		req.To = date.AddDate(0, 0, 1)
+	}
+
+	report, err := h.service.Report(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidReport):
+			h.sendJSONError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, user.ErrUserNotFound):
+			h.sendJSONError(w, http.StatusNotFound, "user not found")
+		default:
+			logger.Error("failed to build usage report", logger.WithError(err))
+			h.sendJSONError(w, http.StatusInternalServerError, "failed to build usage report")
+		}
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, report)
+}
+
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+func (h *Handler) sendJSONError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
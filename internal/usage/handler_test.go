@@ -0,0 +1,108 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zjoart/eunoia/internal/user"
+)
+
+func TestHandleReport(t *testing.T) {
+	var received *ReportRequest
+	mockService := &MockService{
+		ReportFunc: func(req *ReportRequest) (*Report, error) {
+			received = req
+			row := &ReportRow{Group: "chat", Calls: 3, PromptTokens: 900, CompletionTokens: 200}
+			return &Report{From: req.From, To: req.To, GroupBy: GroupByPurpose, Rows: []*ReportRow{row}, Totals: row}, nil
+		},
+	}
+	handler := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/usage/report?from=2025-01-01&to=2025-01-31&group_by=purpose", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleReport(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	if received.From.Format(dateLayout) != "2025-01-01" {
+		t.Errorf("expected from 2025-01-01, got %s", received.From.Format(dateLayout))
+	}
+	if received.To.Format(dateLayout) != "2025-02-01" {
+		t.Errorf("expected inclusive to date to become 2025-02-01, got %s", received.To.Format(dateLayout))
+	}
+
+	var report Report
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(report.Rows) != 1 || report.Rows[0].Group != "chat" {
+		t.Errorf("unexpected rows: %v", report.Rows)
+	}
+}
+
+func TestHandleReport_InvalidDate(t *testing.T) {
+	handler := NewHandler(&MockService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/usage/report?from=yesterday", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleReport(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestHandleReport_InvalidGroupBy(t *testing.T) {
+	mockService := &MockService{
+		ReportFunc: func(req *ReportRequest) (*Report, error) {
+			return nil, errUnknownGroupBy
+		},
+	}
+	handler := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/usage/report?group_by=colour", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleReport(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestHandleReport_UnknownUser(t *testing.T) {
+	mockService := &MockService{
+		ReportFunc: func(req *ReportRequest) (*Report, error) {
+			return nil, fmt.Errorf("user not found: %w", user.ErrUserNotFound)
+		},
+	}
+	handler := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/usage/report?platform_user_id=ghost", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleReport(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+type MockService struct {
+	ReportFunc func(req *ReportRequest) (*Report, error)
+}
+
+func (m *MockService) Report(req *ReportRequest) (*Report, error) {
+	if m.ReportFunc != nil {
+		return m.ReportFunc(req)
+	}
+	return nil, nil
+}
@@ -0,0 +1,6 @@
+package usage
+
+// ServiceInterface defines the methods needed by the handler
+type ServiceInterface interface {
+	Report(req *ReportRequest) (*Report, error)
+}
@@ -0,0 +1,53 @@
+package usage
+
+import (
+	"time"
+
+	"github.com/zjoart/eunoia/internal/agent"
+)
+
+const (
+	GroupByPurpose = "purpose"
+	GroupByModel   = "model"
+	GroupByUser    = "user"
+	GroupByDay     = "day"
+)
+
+type Record struct {
+	ID               string    `json:"id"`
+	UserID           string    `json:"user_id,omitempty"`
+	Purpose          string    `json:"purpose"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	LatencyMs        int64     `json:"latency_ms"`
+	EstimatedCost    float64   `json:"estimated_cost"`
+	Success          bool      `json:"success"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type ReportRequest struct {
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	GroupBy        string    `json:"group_by"`
+	PlatformUserID string    `json:"platform_user_id,omitempty"`
+}
+
+type ReportRow struct {
+	Group            string  `json:"group"`
+	Calls            int     `json:"calls"`
+	Failures         int     `json:"failures"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	EstimatedCost    float64 `json:"estimated_cost"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+}
+
+type Report struct {
+	From          time.Time         `json:"from"`
+	To            time.Time         `json:"to"`
+	GroupBy       string            `json:"group_by"`
+	Rows          []*ReportRow      `json:"rows"`
+	Totals        *ReportRow        `json:"totals"`
+	AnalysisCache *agent.CacheStats `json:"analysis_cache,omitempty"`
+}
@@ -0,0 +1,93 @@
+package usage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// columns a report may be grouped by, keyed by GroupBy value
+var groupColumns = map[string]string{
+	GroupByPurpose: "purpose",
+	GroupByModel:   "model",
+	GroupByUser:    "COALESCE(user_id, '')",
+	GroupByDay:     "DATE_FORMAT(created_at, '%Y-%m-%d')",
+}
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateRecord(record *Record) error {
+	query := `INSERT INTO llm_usage (id, user_id, purpose, model, prompt_tokens, completion_tokens,
+			  latency_ms, estimated_cost, success, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, record.ID, sql.NullString{String: record.UserID, Valid: record.UserID != ""},
+		record.Purpose, record.Model, record.PromptTokens, record.CompletionTokens,
+		record.LatencyMs, record.EstimatedCost, record.Success, record.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetReport aggregates usage between from and to; an empty userID covers all users
+func (r *Repository) GetReport(from, to time.Time, groupBy, userID string) ([]*ReportRow, error) {
+	column, ok := groupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported group_by: %s", groupBy)
+	}
+
+	query := fmt.Sprintf(`SELECT %s AS group_key, COUNT(*), SUM(CASE WHEN success THEN 0 ELSE 1 END),
+			  SUM(prompt_tokens), SUM(completion_tokens), SUM(estimated_cost), AVG(latency_ms)
+			  FROM llm_usage
+			  WHERE created_at >= ? AND created_at < ?`, column)
+	args := []interface{}{from, to}
+
+	if userID != "" {
+		query += ` AND user_id = ?`
+		args = append(args, userID)
+	}
+
+	query += ` GROUP BY group_key ORDER BY SUM(estimated_cost) DESC, COUNT(*) DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var report []*ReportRow
+	for rows.Next() {
+		row := &ReportRow{}
+		err := rows.Scan(&row.Group, &row.Calls, &row.Failures, &row.PromptTokens,
+			&row.CompletionTokens, &row.EstimatedCost, &row.AvgLatencyMs)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, row)
+	}
+
+	return report, nil
+}
+
+// GetTokensSince returns the prompt plus completion tokens a user has consumed since the given time
+func (r *Repository) GetTokensSince(userID string, since time.Time) (int64, error) {
+	query := `SELECT COALESCE(SUM(prompt_tokens + completion_tokens), 0)
+			  FROM llm_usage
+			  WHERE user_id = ? AND created_at >= ?`
+
+	var tokens int64
+	if err := r.db.QueryRow(query, userID, since).Scan(&tokens); err != nil {
+		return 0, err
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,127 @@
+package usage
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCreateRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	record := &Record{
+		ID:               "usage-123",
+		UserID:           "user-456",
+		Purpose:          "chat",
+		Model:            "gemini-2.5-flash",
+		PromptTokens:     1200,
+		CompletionTokens: 300,
+		LatencyMs:        850,
+		EstimatedCost:    0.0011,
+		Success:          true,
+		CreatedAt:        time.Now(),
+	}
+
+	mock.ExpectExec("INSERT INTO llm_usage").
+		WithArgs(record.ID, sql.NullString{String: record.UserID, Valid: true}, record.Purpose, record.Model,
+			record.PromptTokens, record.CompletionTokens, record.LatencyMs, record.EstimatedCost,
+			record.Success, record.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := repo.CreateRecord(record); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetReport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	from := time.Now().AddDate(0, 0, -7)
+	to := time.Now()
+
+	rows := sqlmock.NewRows([]string{"group_key", "calls", "failures", "prompt_tokens", "completion_tokens", "estimated_cost", "avg_latency"}).
+		AddRow("chat", 10, 1, 12000, 3000, 0.011, 900.5).
+		AddRow("embedding", 20, 0, 4000, 0, 0.0, 120.0)
+
+	mock.ExpectQuery("SELECT purpose AS group_key,(.+) FROM llm_usage (.+) AND user_id = \\? GROUP BY group_key").
+		WithArgs(from, to, "user-456").
+		WillReturnRows(rows)
+
+	report, err := repo.GetReport(from, to, GroupByPurpose, "user-456")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(report))
+	}
+
+	if report[0].Group != "chat" || report[0].Calls != 10 || report[0].PromptTokens != 12000 {
+		t.Errorf("unexpected first row: %+v", report[0])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetReport_UnsupportedGroupBy(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	if _, err := repo.GetReport(time.Now(), time.Now(), "user_id; DROP TABLE llm_usage", ""); err == nil {
+		t.Error("expected error for unsupported group_by")
+	}
+}
+
+func TestGetTokensSince(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository(db)
+
+	since := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(prompt_tokens \\+ completion_tokens\\), 0\\) FROM llm_usage").
+		WithArgs("user-456", since).
+		WillReturnRows(sqlmock.NewRows([]string{"tokens"}).AddRow(4200))
+
+	tokens, err := repo.GetTokensSince("user-456", since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tokens != 4200 {
+		t.Errorf("expected 4200 tokens, got %d", tokens)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
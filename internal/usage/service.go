@@ -0,0 +1,156 @@
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zjoart/eunoia/internal/agent"
+	"github.com/zjoart/eunoia/internal/user"
+	"github.com/zjoart/eunoia/pkg/id"
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+var (
+	ErrQuotaExceeded  = errors.New("daily token quota exceeded")
+	ErrInvalidReport  = errors.New("invalid report request")
+	errUnknownGroupBy = fmt.Errorf("%w: group_by must be one of purpose, model, user, day", ErrInvalidReport)
+)
+
+type Service struct {
+	repo            *Repository
+	userRepo        *user.Repository
+	dailyTokenQuota int
+	analysisCache   *agent.AnalysisCache
+}
+
+// NewService creates the usage service; a dailyTokenQuota of 0 disables quotas and analysisCache may be nil
+func NewService(repo *Repository, userRepo *user.Repository, dailyTokenQuota int, analysisCache *agent.AnalysisCache) *Service {
+	return &Service{
+		repo:            repo,
+		userRepo:        userRepo,
+		dailyTokenQuota: dailyTokenQuota,
+		analysisCache:   analysisCache,
+	}
+}
+
+// RecordUsage implements agent.UsageRecorder, saving in the background so calls are not slowed down
+func (s *Service) RecordUsage(u *agent.Usage) {
+	record := &Record{
+		ID:               id.Generate(),
+		UserID:           u.UserID,
+		Purpose:          u.Purpose,
+		Model:            u.Model,
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		LatencyMs:        u.Latency.Milliseconds(),
+		EstimatedCost:    u.EstimatedCost,
+		Success:          u.Success,
+		CreatedAt:        u.CreatedAt,
+	}
+
+	go func() {
+		if err := s.repo.CreateRecord(record); err != nil {
+			logger.Warn("failed to record llm usage", logger.Merge(
+				logger.WithError(err),
+				logger.Fields{
+					"purpose": record.Purpose,
+					"model":   record.Model,
+				},
+			))
+		}
+	}()
+}
+
+// CheckQuota returns ErrQuotaExceeded once the user has used their daily token allowance
+func (s *Service) CheckQuota(userID string) error {
+	if s.dailyTokenQuota <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	used, err := s.repo.GetTokensSince(userID, startOfDay)
+	if err != nil {
+		// never block a conversation because accounting is unavailable
+		logger.Warn("failed to check token quota", logger.Merge(
+			logger.WithError(err),
+			logger.WithUserID(userID),
+		))
+		return nil
+	}
+
+	if used >= int64(s.dailyTokenQuota) {
+		logger.Warn("daily token quota exceeded", logger.Fields{
+			"user_id": userID,
+			"used":    used,
+			"quota":   s.dailyTokenQuota,
+		})
+		return ErrQuotaExceeded
+	}
+
+	return nil
+}
+
+func (s *Service) Report(req *ReportRequest) (*Report, error) {
+	groupBy := req.GroupBy
+	if groupBy == "" {
+		groupBy = GroupByPurpose
+	}
+	if _, ok := groupColumns[groupBy]; !ok {
+		return nil, errUnknownGroupBy
+	}
+
+	if !req.From.Before(req.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidReport)
+	}
+
+	var userID string
+	if req.PlatformUserID != "" {
+		userRecord, err := s.userRepo.GetUserByPlatformID(req.PlatformUserID)
+		if err != nil {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+		userID = userRecord.ID
+	}
+
+	rows, err := s.repo.GetReport(req.From, req.To, groupBy, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build usage report: %w", err)
+	}
+
+	if rows == nil {
+		rows = []*ReportRow{}
+	}
+
+	return &Report{
+		From:    req.From,
+		To:      req.To,
+		GroupBy: groupBy,
+		Rows:    rows,
+		Totals:  totalRows(rows),
+		// process-wide counters, not limited to the report period
+		AnalysisCache: s.analysisCache.Stats(),
+	}, nil
+}
+
+func totalRows(rows []*ReportRow) *ReportRow {
+	totals := &ReportRow{Group: "total"}
+
+	var latencySum float64
+	for _, row := range rows {
+		totals.Calls += row.Calls
+		totals.Failures += row.Failures
+		totals.PromptTokens += row.PromptTokens
+		totals.CompletionTokens += row.CompletionTokens
+		totals.EstimatedCost += row.EstimatedCost
+		latencySum += row.AvgLatencyMs * float64(row.Calls)
+	}
+
+	if totals.Calls > 0 {
+		totals.AvgLatencyMs = latencySum / float64(totals.Calls)
+	}
+
+	return totals
+}
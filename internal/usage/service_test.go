@@ -0,0 +1,74 @@
+package usage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCheckQuota(t *testing.T) {
+	tests := []struct {
+		name     string
+		used     int64
+		expected error
+	}{
+		{"under quota", 999, nil},
+		{"at quota", 1000, ErrQuotaExceeded},
+		{"over quota", 1500, ErrQuotaExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer db.Close()
+
+			service := NewService(NewRepository(db), nil, 1000, nil)
+
+			mock.ExpectQuery("SELECT (.+) FROM llm_usage").
+				WithArgs("user-1", sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"tokens"}).AddRow(tt.used))
+
+			if err := service.CheckQuota("user-1"); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckQuota_Disabled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	if err := NewService(NewRepository(db), nil, 0, nil).CheckQuota("user-1"); err != nil {
+		t.Errorf("expected no quota when disabled, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected no usage lookup, got %v", err)
+	}
+}
+
+func TestCheckQuota_AllowsWhenAccountingFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM llm_usage").WillReturnError(errors.New("connection refused"))
+
+	if err := NewService(NewRepository(db), nil, 1000, nil).CheckQuota("user-1"); err != nil {
+		t.Errorf("expected the conversation to continue, got %v", err)
+	}
+}
@@ -2,12 +2,14 @@ package user
 
 import (
 	"database/sql"
-	"fmt"
+	"errors"
 	"time"
 
 	"github.com/zjoart/eunoia/pkg/id"
 )
 
+var ErrUserNotFound = errors.New("user not found")
+
 type Repository struct {
 	db *sql.DB
 }
@@ -41,7 +43,7 @@ func (r *Repository) GetUserByPlatformID(platformUserID string) (*User, error) {
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	if err != nil {
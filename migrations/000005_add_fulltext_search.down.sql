@@ -0,0 +1,2 @@
+ALTER TABLE conversation_history DROP INDEX ft_conversation_message_content;
+ALTER TABLE reflections DROP INDEX ft_reflections_content;
@@ -0,0 +1,3 @@
+-- Full-text indexes for searching reflections and conversation history
+ALTER TABLE reflections ADD FULLTEXT INDEX ft_reflections_content (content);
+ALTER TABLE conversation_history ADD FULLTEXT INDEX ft_conversation_message_content (message_content);
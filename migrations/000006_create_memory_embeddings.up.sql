@@ -0,0 +1,15 @@
+-- Embeddings of reflections and past messages for semantic memory retrieval
+CREATE TABLE IF NOT EXISTS memory_embeddings (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    source_type VARCHAR(20) NOT NULL,
+    source_id VARCHAR(36) NOT NULL,
+    content TEXT NOT NULL,
+    embedding MEDIUMBLOB NOT NULL,
+    dimensions INT NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uq_memory_source (source_type, source_id),
+    INDEX idx_user_memories (user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
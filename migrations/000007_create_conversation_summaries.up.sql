@@ -0,0 +1,14 @@
+-- Rolling summaries of older conversation history for long-term continuity
+CREATE TABLE IF NOT EXISTS conversation_summaries (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    summary TEXT NOT NULL,
+    first_message_id VARCHAR(36) NOT NULL,
+    last_message_id VARCHAR(36) NOT NULL,
+    first_message_at TIMESTAMP NOT NULL,
+    last_message_at TIMESTAMP NOT NULL,
+    message_count INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_summaries (user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,13 @@
+-- Facts Eunoia has learned about each user, traceable to the message they came from
+CREATE TABLE IF NOT EXISTS user_facts (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    category VARCHAR(30) NOT NULL,
+    content VARCHAR(500) NOT NULL,
+    source_message_id VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (source_message_id) REFERENCES conversation_history(id) ON DELETE SET NULL,
+    INDEX idx_user_facts (user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,16 @@
+-- One row per LLM call for cost accounting and quotas
+CREATE TABLE IF NOT EXISTS llm_usage (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36),
+    purpose VARCHAR(50) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    prompt_tokens INT NOT NULL DEFAULT 0,
+    completion_tokens INT NOT NULL DEFAULT 0,
+    latency_ms INT NOT NULL DEFAULT 0,
+    estimated_cost DECIMAL(14, 8) NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    INDEX idx_usage_user (user_id, created_at),
+    INDEX idx_usage_purpose (purpose, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
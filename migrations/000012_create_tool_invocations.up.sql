@@ -0,0 +1,16 @@
+-- Audit log of actions the model proposed through tools and what the user decided
+CREATE TABLE IF NOT EXISTS tool_invocations (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    message_id VARCHAR(255),
+    tool_name VARCHAR(63) NOT NULL,
+    arguments JSON NOT NULL,
+    summary VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    result JSON,
+    error_message TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_tool_invocations_user (user_id, status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
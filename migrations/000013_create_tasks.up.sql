@@ -0,0 +1,14 @@
+-- A2A tasks, one per message/send call, so clients can query or cancel them later
+CREATE TABLE IF NOT EXISTS tasks (
+    id VARCHAR(36) PRIMARY KEY,
+    context_id VARCHAR(255) NOT NULL,
+    platform_user_id VARCHAR(255) NOT NULL,
+    state VARCHAR(20) NOT NULL,
+    status_message JSON,
+    history JSON NOT NULL,
+    artifacts JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tasks_context (context_id, created_at),
+    INDEX idx_tasks_user (platform_user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+DROP INDEX idx_history_session ON conversation_history;
+ALTER TABLE conversation_history DROP COLUMN session_id;
+DROP TABLE IF EXISTS conversation_sessions;
@@ -0,0 +1,15 @@
+-- Conversation sessions group messages under an A2A contextId, one active session per user and channel
+CREATE TABLE IF NOT EXISTS conversation_sessions (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    channel_id VARCHAR(255) NOT NULL DEFAULT '',
+    context_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_active_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uq_sessions_context (context_id),
+    INDEX idx_sessions_user_channel (user_id, channel_id, last_active_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE conversation_history ADD COLUMN session_id VARCHAR(36) NULL;
+CREATE INDEX idx_history_session ON conversation_history (session_id, created_at);
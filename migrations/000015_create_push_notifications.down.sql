@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS push_deliveries;
+DROP TABLE IF EXISTS push_notification_configs;
@@ -0,0 +1,24 @@
+-- Where to send a task's result when it was submitted with blocking=false
+CREATE TABLE IF NOT EXISTS push_notification_configs (
+    task_id VARCHAR(36) PRIMARY KEY,
+    config_id VARCHAR(255),
+    url VARCHAR(2048) NOT NULL,
+    token VARCHAR(255),
+    auth_schemes JSON,
+    credentials TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- One row per push notification attempt
+CREATE TABLE IF NOT EXISTS push_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    task_id VARCHAR(36) NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    attempt INT NOT NULL,
+    status_code INT,
+    error_message TEXT,
+    delivered BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_push_deliveries_task (task_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
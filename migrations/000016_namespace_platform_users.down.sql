@@ -0,0 +1,2 @@
+UPDATE tasks SET platform_user_id = SUBSTRING(platform_user_id, 7) WHERE platform_user_id LIKE 'telex:%';
+UPDATE users SET platform_user_id = SUBSTRING(platform_user_id, 7) WHERE platform_user_id LIKE 'telex:%';
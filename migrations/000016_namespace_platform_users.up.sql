@@ -0,0 +1,3 @@
+-- Platform user IDs are namespaced by platform; every existing user came from Telex
+UPDATE users SET platform_user_id = CONCAT('telex:', platform_user_id) WHERE platform_user_id NOT LIKE 'telex:%';
+UPDATE tasks SET platform_user_id = CONCAT('telex:', platform_user_id) WHERE platform_user_id NOT LIKE 'telex:%';
@@ -0,0 +1,3 @@
+ALTER TABLE conversation_summaries DROP COLUMN last_message_seq;
+DROP INDEX idx_history_user_seq ON conversation_history;
+ALTER TABLE conversation_history DROP COLUMN seq;
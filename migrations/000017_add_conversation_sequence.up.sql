@@ -0,0 +1,10 @@
+-- A monotonic sequence orders messages saved in the same second; existing rows are numbered by time
+ALTER TABLE conversation_history ADD COLUMN seq BIGINT UNSIGNED NULL;
+SET @seq := 0;
+UPDATE conversation_history SET seq = (@seq := @seq + 1) ORDER BY created_at, id;
+ALTER TABLE conversation_history MODIFY seq BIGINT UNSIGNED NOT NULL AUTO_INCREMENT, ADD UNIQUE KEY uq_history_seq (seq);
+CREATE INDEX idx_history_user_seq ON conversation_history (user_id, seq);
+
+-- summaries resume from the sequence of the last message they cover
+ALTER TABLE conversation_summaries ADD COLUMN last_message_seq BIGINT UNSIGNED NOT NULL DEFAULT 0;
+UPDATE conversation_summaries s JOIN conversation_history h ON h.id = s.last_message_id SET s.last_message_seq = h.seq;
@@ -0,0 +1,72 @@
+package a2a
+
+// ProtocolVersion is the A2A specification version the agent card follows
+const ProtocolVersion = "0.3.0"
+
+// TransportJSONRPC is the only transport the agent serves
+const TransportJSONRPC = "JSONRPC"
+
+// agent card served at /.well-known/agent.json
+type AgentCard struct {
+	ProtocolVersion    string                    `json:"protocolVersion"`
+	Name               string                    `json:"name"`
+	Description        string                    `json:"description"`
+	URL                string                    `json:"url"`
+	PreferredTransport string                    `json:"preferredTransport"`
+	Version            string                    `json:"version"`
+	Provider           *AgentProvider            `json:"provider,omitempty"`
+	DocumentationURL   string                    `json:"documentationUrl,omitempty"`
+	Capabilities       AgentCapabilities         `json:"capabilities"`
+	SecuritySchemes    map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+	Security           []map[string][]string     `json:"security,omitempty"`
+	DefaultInputModes  []string                  `json:"defaultInputModes"`
+	DefaultOutputModes []string                  `json:"defaultOutputModes"`
+	Skills             []AgentSkill              `json:"skills"`
+}
+
+// organization publishing the agent
+type AgentProvider struct {
+	Organization string `json:"organization"`
+	URL          string `json:"url"`
+}
+
+// optional protocol features the agent supports
+type AgentCapabilities struct {
+	Streaming              bool `json:"streaming"`
+	PushNotifications      bool `json:"pushNotifications"`
+	StateTransitionHistory bool `json:"stateTransitionHistory"`
+}
+
+// authentication scheme, in the OpenAPI security scheme format
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Scheme      string `json:"scheme,omitempty"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// something the agent can do for a user
+type AgentSkill struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Examples    []string `json:"examples,omitempty"`
+	InputModes  []string `json:"inputModes,omitempty"`
+	OutputModes []string `json:"outputModes,omitempty"`
+}
+
+// Capabilities derives the advertised capabilities from the methods the server handles
+func Capabilities(methods []string) AgentCapabilities {
+	var capabilities AgentCapabilities
+	for _, method := range methods {
+		switch method {
+		case MethodMessageStream:
+			capabilities.Streaming = true
+		case MethodSetPushConfig:
+			capabilities.PushNotifications = true
+		}
+	}
+	return capabilities
+}
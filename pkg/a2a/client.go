@@ -0,0 +1,344 @@
+package a2a
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// where agents publish their card; the legacy path predates protocol 0.3
+const (
+	AgentCardPath       = "/.well-known/agent-card.json"
+	LegacyAgentCardPath = "/.well-known/agent.json"
+)
+
+// largest single server-sent event the client reads from a stream
+const maxStreamEventBytes = 1 << 20
+
+// Client calls another agent's JSON-RPC endpoint
+type Client struct {
+	url        string
+	httpClient *http.Client
+	token      string
+	nextID     atomic.Int64
+}
+
+// NewClient creates a client for the endpoint in an agent card's url field
+func NewClient(url string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{url: url, httpClient: httpClient}
+}
+
+// SetBearerToken sends the token as an Authorization header on every call
+func (c *Client) SetBearerToken(token string) {
+	c.token = token
+}
+
+// DiscoverAgent fetches the agent card published under baseURL, trying the legacy path when the current one is missing
+func DiscoverAgent(ctx context.Context, httpClient *http.Client, baseURL string) (*AgentCard, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	card, err := fetchAgentCard(ctx, httpClient, baseURL+AgentCardPath)
+	if errors.Is(err, errCardNotFound) {
+		card, err = fetchAgentCard(ctx, httpClient, baseURL+LegacyAgentCardPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if card.URL == "" {
+		return nil, fmt.Errorf("agent card at %s has no url", baseURL)
+	}
+
+	return card, nil
+}
+
+var errCardNotFound = errors.New("agent card not found")
+
+func fetchAgentCard(ctx context.Context, httpClient *http.Client, cardURL string) (*AgentCard, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cardURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build agent card request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch agent card: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errCardNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("agent card request returned status %d", resp.StatusCode)
+	}
+
+	var card AgentCard
+	if err := json.NewDecoder(io.LimitReader(resp.Body, DefaultMaxBodyBytes)).Decode(&card); err != nil {
+		return nil, fmt.Errorf("failed to decode agent card: %w", err)
+	}
+
+	return &card, nil
+}
+
+// SendMessage calls message/send. An agent may answer with a bare message instead of a task;
+// that reply comes back as a completed task without an ID whose status message is the reply.
+func (c *Client) SendMessage(ctx context.Context, params *MessageSendParams) (*A2AResult, error) {
+	var raw json.RawMessage
+	if err := c.call(ctx, MethodMessageSend, params, &raw); err != nil {
+		return nil, err
+	}
+
+	event, err := decodeEvent(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case event.Task != nil:
+		return event.Task, nil
+	case event.Message != nil:
+		return &A2AResult{
+			ContextID: event.Message.ContextID,
+			Status: A2ATaskStatus{
+				State:   TaskStateCompleted,
+				Message: *event.Message,
+			},
+			Kind: "task",
+		}, nil
+	default:
+		return nil, fmt.Errorf("unexpected message/send result kind %q", event.Kind)
+	}
+}
+
+// GetTask calls tasks/get; a nil historyLength leaves the length to the agent
+func (c *Client) GetTask(ctx context.Context, taskID string, historyLength *int) (*A2AResult, error) {
+	var result A2AResult
+	err := c.call(ctx, MethodTasksGet, &TaskQueryParams{ID: taskID, HistoryLength: historyLength}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CancelTask calls tasks/cancel
+func (c *Client) CancelTask(ctx context.Context, taskID string) (*A2AResult, error) {
+	var result A2AResult
+	if err := c.call(ctx, MethodTasksCancel, &TaskIDParams{ID: taskID}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WaitForTask polls tasks/get every interval until the task reaches a final state or ctx ends
+func (c *Client) WaitForTask(ctx context.Context, taskID string, interval time.Duration) (*A2AResult, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := c.GetTask(ctx, taskID, nil)
+		if err != nil {
+			return nil, err
+		}
+		if IsFinalState(result.Status.State) {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// StreamEvent is one result from a message/stream; exactly one of the pointers is set, matching Kind
+type StreamEvent struct {
+	Kind     string
+	Task     *A2AResult
+	Message  *A2AMessageResult
+	Status   *TaskStatusUpdateEvent
+	Artifact *TaskArtifactUpdateEvent
+}
+
+// StreamMessage calls message/stream and hands each event to handle until the agent closes the stream,
+// a final status arrives or handle returns an error
+func (c *Client) StreamMessage(ctx context.Context, params *MessageSendParams, handle func(*StreamEvent) error) error {
+	resp, err := c.post(ctx, MethodMessageStream, params, "text/event-stream")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// agents that reject the call answer with a plain JSON-RPC error
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var raw json.RawMessage
+		if err := decodeRPC(resp.Body, &raw); err != nil {
+			return err
+		}
+		return fmt.Errorf("agent did not open an event stream")
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamEventBytes)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "data:") {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			continue
+		}
+		// a blank line ends the event; comments and other fields are ignored
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+
+		var raw json.RawMessage
+		if err := decodeRPC(strings.NewReader(data.String()), &raw); err != nil {
+			return err
+		}
+		data.Reset()
+
+		event, err := decodeEvent(raw)
+		if err != nil {
+			return err
+		}
+		if err := handle(event); err != nil {
+			return err
+		}
+		if event.Status != nil && event.Status.Final {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event stream: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	resp, err := c.post(ctx, method, params, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return decodeRPC(io.LimitReader(resp.Body, DefaultMaxBodyBytes), result)
+}
+
+func (c *Client) post(ctx context.Context, method string, params any, accept string) (*http.Response, error) {
+	encodedParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s params: %w", method, err)
+	}
+
+	body, err := json.Marshal(RPCRequest{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage(fmt.Sprintf("%d", c.nextID.Add(1))),
+		Method:  method,
+		Params:  encodedParams,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", method, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s request returned status %d", method, resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+// decodeRPC reads a JSON-RPC response, returning its error as an *A2AError
+func decodeRPC(body io.Reader, result any) error {
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *A2AError       `json:"error"`
+	}
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return fmt.Errorf("failed to decode JSON-RPC response: %w", err)
+	}
+
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if len(resp.Result) == 0 {
+		return fmt.Errorf("JSON-RPC response has no result")
+	}
+
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("failed to decode JSON-RPC result: %w", err)
+	}
+	return nil
+}
+
+func decodeEvent(raw json.RawMessage) (*StreamEvent, error) {
+	var kind struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(raw, &kind); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	event := &StreamEvent{Kind: kind.Kind}
+
+	var target any
+	switch kind.Kind {
+	case "task":
+		event.Task = &A2AResult{}
+		target = event.Task
+	case "message":
+		event.Message = &A2AMessageResult{}
+		target = event.Message
+	case "status-update":
+		event.Status = &TaskStatusUpdateEvent{}
+		target = event.Status
+	case "artifact-update":
+		event.Artifact = &TaskArtifactUpdateEvent{}
+		target = event.Artifact
+	default:
+		return nil, fmt.Errorf("unknown result kind %q", kind.Kind)
+	}
+
+	if err := json.Unmarshal(raw, target); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", kind.Kind, err)
+	}
+	return event, nil
+}
@@ -0,0 +1,142 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDiscoverAgent_LegacyPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != LegacyAgentCardPath {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(AgentCard{Name: "Meditation Guide", URL: "https://meditation.example/a2a"})
+	}))
+	defer server.Close()
+
+	card, err := DiscoverAgent(context.Background(), server.Client(), server.URL+"/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if card.Name != "Meditation Guide" || card.URL != "https://meditation.example/a2a" {
+		t.Errorf("unexpected card %+v", card)
+	}
+}
+
+func TestClient_SendMessage(t *testing.T) {
+	s := NewServer()
+	s.OnMessageSend(func(r *http.Request, params *MessageSendParams) (*A2AResult, *A2AError) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			return nil, NewError(InvalidRequest, "missing token")
+		}
+		return &A2AResult{
+			ID:     "task-1",
+			Status: A2ATaskStatus{State: TaskStateCompleted},
+			Artifacts: []A2AArtifact{{
+				Parts: []A2APart{{Kind: "text", Text: "echo: " + params.Message.Parts[0].Text}},
+			}},
+			Kind: "task",
+		}, nil
+	})
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	client := NewClient(server.URL, server.Client())
+	client.SetBearerToken("secret")
+
+	result, err := client.SendMessage(context.Background(), &MessageSendParams{
+		Message: A2AMessage{Kind: "message", Role: "user", Parts: []A2APart{{Kind: "text", Text: "hello"}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != "task-1" || result.Artifacts[0].Parts[0].Text != "echo: hello" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestClient_MessageResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"kind":"message","role":"agent","messageId":"m1","contextId":"ctx-1","parts":[{"kind":"text","text":"hi"}]}}`)
+	}))
+	defer server.Close()
+
+	result, err := NewClient(server.URL, server.Client()).SendMessage(context.Background(), &MessageSendParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status.State != TaskStateCompleted || result.Status.Message.Parts[0].Text != "hi" || result.ContextID != "ctx-1" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestClient_RPCError(t *testing.T) {
+	server := httptest.NewServer(NewServer())
+	defer server.Close()
+
+	_, err := NewClient(server.URL, server.Client()).GetTask(context.Background(), "task-1", nil)
+
+	var rpcErr *A2AError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != UnsupportedOperation {
+		t.Errorf("expected UnsupportedOperation, got %v", err)
+	}
+}
+
+func TestClient_WaitForTask(t *testing.T) {
+	calls := 0
+	s := NewServer()
+	s.OnGetTask(func(r *http.Request, params *TaskQueryParams) (*A2AResult, *A2AError) {
+		calls++
+		state := TaskStateWorking
+		if calls == 3 {
+			state = TaskStateCompleted
+		}
+		return &A2AResult{ID: params.ID, Status: A2ATaskStatus{State: state}, Kind: "task"}, nil
+	})
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	result, err := NewClient(server.URL, server.Client()).WaitForTask(context.Background(), "task-1", time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status.State != TaskStateCompleted || calls != 3 {
+		t.Errorf("expected completion on the third poll, got %s after %d", result.Status.State, calls)
+	}
+}
+
+func TestClient_StreamMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("expected event stream accept header, got %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"kind\":\"task\",\"id\":\"task-1\",\"status\":{\"state\":\"submitted\"}}}\n\n")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"kind\":\"artifact-update\",\"taskId\":\"task-1\",\"artifact\":{\"artifactId\":\"a1\",\"parts\":[{\"kind\":\"text\",\"text\":\"breathe\"}]}}}\n\n")
+		fmt.Fprint(w, "data: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"kind\":\"status-update\",\"taskId\":\"task-1\",\"status\":{\"state\":\"completed\"},\"final\":true}}\n\n")
+		fmt.Fprint(w, "data: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"kind\":\"message\"}}\n\n")
+	}))
+	defer server.Close()
+
+	var kinds []string
+	err := NewClient(server.URL, server.Client()).StreamMessage(context.Background(), &MessageSendParams{}, func(event *StreamEvent) error {
+		kinds = append(kinds, event.Kind)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"task", "artifact-update", "status-update"}
+	if fmt.Sprint(kinds) != fmt.Sprint(want) {
+		t.Errorf("expected events %v, got %v", want, kinds)
+	}
+}
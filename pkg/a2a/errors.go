@@ -0,0 +1,65 @@
+package a2a
+
+import "fmt"
+
+// JSON-RPC 2.0 Error Codes
+const (
+	ParseError = -32700
+
+	InvalidRequest = -32600
+
+	MethodNotFound = -32601
+
+	InvalidParams = -32602
+
+	InternalError = -32603
+)
+
+// A2A specific error codes
+const (
+	TaskNotFound = -32001
+
+	TaskNotCancelable = -32002
+
+	PushNotificationNotSupported = -32003
+
+	UnsupportedOperation = -32004
+
+	ContentTypeNotSupported = -32005
+
+	InvalidAgentResponse = -32006
+
+	AuthenticatedExtendedCardNotConfigured = -32007
+)
+
+var errorMessages = map[int]string{
+	ParseError:     "Parse error",
+	InvalidRequest: "Invalid Request",
+	MethodNotFound: "Method not found",
+	InvalidParams:  "Invalid params",
+	InternalError:  "Internal error",
+
+	TaskNotFound:                           "Task not found",
+	TaskNotCancelable:                      "Task cannot be canceled",
+	PushNotificationNotSupported:           "Push Notification is not supported",
+	UnsupportedOperation:                   "This operation is not supported",
+	ContentTypeNotSupported:                "Incompatible content types",
+	InvalidAgentResponse:                   "Invalid agent response",
+	AuthenticatedExtendedCardNotConfigured: "Authenticated Extended Card is not configured",
+}
+
+// NewError builds an error with the standard message for its code, when it has one
+func NewError(code int, data any) *A2AError {
+	message, ok := errorMessages[code]
+	if !ok {
+		message = "Server error"
+	}
+	return &A2AError{Code: code, Message: message, Data: data}
+}
+
+func (e *A2AError) Error() string {
+	if e.Data != nil {
+		return fmt.Sprintf("%s (%d): %v", e.Message, e.Code, e.Data)
+	}
+	return fmt.Sprintf("%s (%d)", e.Message, e.Code)
+}
@@ -0,0 +1,279 @@
+package a2a
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/zjoart/eunoia/pkg/logger"
+)
+
+// default upper bounds for a single HTTP request
+const (
+	DefaultMaxBatchSize = 20
+	DefaultMaxBodyBytes = 1 << 20
+)
+
+// RPCRequest is a JSON-RPC 2.0 request. ID is kept raw so string, number and null ids are echoed unchanged.
+type RPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification reports whether the request has no id, in which case no response is sent
+func (r *RPCRequest) IsNotification() bool {
+	return r.ID == nil
+}
+
+// IDString returns a string id unquoted and any other id as its JSON text
+func (r *RPCRequest) IDString() string {
+	var id string
+	if err := json.Unmarshal(r.ID, &id); err == nil {
+		return id
+	}
+	return string(r.ID)
+}
+
+// DecodeParams unmarshals the request params into v, returning an Invalid params error on failure
+func (r *RPCRequest) DecodeParams(v any) *A2AError {
+	if len(r.Params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(r.Params, v); err != nil {
+		return NewError(InvalidParams, err.Error())
+	}
+	return nil
+}
+
+// RPCResponse is a JSON-RPC 2.0 response carrying either a result or an error
+type RPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *A2AError       `json:"error,omitempty"`
+}
+
+// MethodHandler runs one JSON-RPC method; returning a non-nil error sends it instead of the result
+type MethodHandler func(r *http.Request, req *RPCRequest) (any, *A2AError)
+
+// Dispatcher routes JSON-RPC 2.0 requests, single or batched, to registered methods
+type Dispatcher struct {
+	methods      map[string]MethodHandler
+	unknown      func(method string) *A2AError
+	maxBatchSize int
+	maxBodyBytes int64
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		methods:      make(map[string]MethodHandler),
+		maxBatchSize: DefaultMaxBatchSize,
+		maxBodyBytes: DefaultMaxBodyBytes,
+	}
+}
+
+// SetMaxBodyBytes sets the largest request body accepted; larger ones get 413 Request Entity Too Large
+func (d *Dispatcher) SetMaxBodyBytes(n int64) {
+	d.maxBodyBytes = n
+}
+
+// Register adds a method, replacing any handler already registered under the name
+func (d *Dispatcher) Register(method string, handler MethodHandler) {
+	d.methods[method] = handler
+}
+
+// HandleUnknown sets the error returned for methods nothing is registered under;
+// returning nil falls back to MethodNotFound
+func (d *Dispatcher) HandleUnknown(fn func(method string) *A2AError) {
+	d.unknown = fn
+}
+
+// Methods returns the registered method names in alphabetical order
+func (d *Dispatcher) Methods() []string {
+	methods := make([]string, 0, len(d.methods))
+	for method := range d.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// ServeHTTP answers a single request with one response and a batch with an array of responses,
+// leaving out notifications. When nothing needs a reply the status is 204 No Content.
+func (d *Dispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeRPC(w, http.StatusMethodNotAllowed, errorResponse(nil, NewError(InvalidRequest, "method not allowed")))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, d.maxBodyBytes+1))
+	if err != nil {
+		writeRPC(w, http.StatusOK, errorResponse(nil, NewError(ParseError, "failed to read body")))
+		return
+	}
+	if int64(len(body)) > d.maxBodyBytes {
+		writeRPC(w, http.StatusRequestEntityTooLarge, errorResponse(nil, NewError(InvalidRequest, "request body is too large")))
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		d.serveBatch(w, r, body)
+		return
+	}
+
+	response := d.handle(r, body)
+	if response == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeRPC(w, http.StatusOK, response)
+}
+
+func (d *Dispatcher) serveBatch(w http.ResponseWriter, r *http.Request, body []byte) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		writeRPC(w, http.StatusOK, errorResponse(nil, NewError(ParseError, "invalid JSON")))
+		return
+	}
+
+	if len(batch) == 0 {
+		writeRPC(w, http.StatusOK, errorResponse(nil, NewError(InvalidRequest, "batch cannot be empty")))
+		return
+	}
+	if len(batch) > d.maxBatchSize {
+		writeRPC(w, http.StatusOK, errorResponse(nil, NewError(InvalidRequest, fmt.Sprintf("batch cannot hold more than %d requests", d.maxBatchSize))))
+		return
+	}
+
+	results := make([]*RPCResponse, len(batch))
+	var wg sync.WaitGroup
+	for i, raw := range batch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = d.handle(r, raw)
+		}()
+	}
+	wg.Wait()
+
+	responses := []*RPCResponse{}
+	for _, response := range results {
+		if response != nil {
+			responses = append(responses, response)
+		}
+	}
+
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeRPC(w, http.StatusOK, responses)
+}
+
+// handle runs one request, returning nil for notifications
+func (d *Dispatcher) handle(r *http.Request, raw []byte) (response *RPCResponse) {
+	var req RPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		if !json.Valid(raw) {
+			return errorResponse(nil, NewError(ParseError, "invalid JSON"))
+		}
+		return errorResponse(nil, NewError(InvalidRequest, "request must be a JSON-RPC object"))
+	}
+
+	if rpcErr := validateRequest(&req); rpcErr != nil {
+		return errorResponse(validID(req.ID), rpcErr)
+	}
+
+	handler, ok := d.methods[req.Method]
+	if !ok {
+		if req.IsNotification() {
+			return nil
+		}
+		if d.unknown != nil {
+			if rpcErr := d.unknown(req.Method); rpcErr != nil {
+				return errorResponse(req.ID, rpcErr)
+			}
+		}
+		return errorResponse(req.ID, NewError(MethodNotFound, fmt.Sprintf("method %s is not supported", req.Method)))
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.Error("JSON-RPC method panicked", logger.Fields{
+				"method": req.Method,
+				"panic":  fmt.Sprint(recovered),
+			})
+			response = errorResponse(req.ID, NewError(InternalError, nil))
+			if req.IsNotification() {
+				response = nil
+			}
+		}
+	}()
+
+	result, rpcErr := handler(r, &req)
+
+	if req.IsNotification() {
+		return nil
+	}
+
+	if rpcErr != nil {
+		logger.Warn("JSON-RPC method returned an error", logger.Fields{
+			"method":  req.Method,
+			"code":    rpcErr.Code,
+			"message": rpcErr.Message,
+		})
+		return errorResponse(req.ID, rpcErr)
+	}
+
+	return &RPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func validateRequest(req *RPCRequest) *A2AError {
+	if req.JSONRPC != "2.0" {
+		return NewError(InvalidRequest, "jsonrpc version must be 2.0")
+	}
+	if req.Method == "" {
+		return NewError(InvalidRequest, "method is required")
+	}
+	if validID(req.ID) == nil && req.ID != nil {
+		return NewError(InvalidRequest, "id must be a string, number or null")
+	}
+	return nil
+}
+
+// validID returns the id when it is a string, number or null, and nil otherwise
+func validID(id json.RawMessage) json.RawMessage {
+	trimmed := bytes.TrimSpace(id)
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	switch trimmed[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return trimmed
+	}
+	return nil
+}
+
+func errorResponse(id json.RawMessage, rpcErr *A2AError) *RPCResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &RPCResponse{JSONRPC: "2.0", ID: id, Error: rpcErr}
+}
+
+func writeRPC(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,229 @@
+package a2a
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestDispatcher() *Dispatcher {
+	d := NewDispatcher()
+	d.Register("echo", func(r *http.Request, req *RPCRequest) (any, *A2AError) {
+		var params map[string]any
+		if rpcErr := req.DecodeParams(&params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return params, nil
+	})
+	d.Register("fail", func(r *http.Request, req *RPCRequest) (any, *A2AError) {
+		return nil, NewError(TaskNotFound, nil)
+	})
+	d.Register("panic", func(r *http.Request, req *RPCRequest) (any, *A2AError) {
+		panic("boom")
+	})
+	return d
+}
+
+func serve(d http.Handler, method, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/a2a", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	d.ServeHTTP(w, req)
+	return w
+}
+
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) RPCResponse {
+	var resp RPCResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp
+}
+
+func TestDispatcher_IDTypes(t *testing.T) {
+	d := newTestDispatcher()
+
+	for _, id := range []string{`"abc"`, `42`, `-1.5`, `null`} {
+		w := serve(d, http.MethodPost, `{"jsonrpc":"2.0","id":`+id+`,"method":"echo","params":{"a":1}}`)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("id %s: expected status 200, got %d", id, w.Code)
+		}
+
+		resp := decodeResponse(t, w)
+		if string(resp.ID) != id {
+			t.Errorf("expected id %s to be echoed, got %s", id, resp.ID)
+		}
+		if resp.Error != nil {
+			t.Errorf("id %s: unexpected error %+v", id, resp.Error)
+		}
+	}
+}
+
+func TestDispatcher_InvalidID(t *testing.T) {
+	w := serve(newTestDispatcher(), http.MethodPost, `{"jsonrpc":"2.0","id":{"a":1},"method":"echo"}`)
+
+	resp := decodeResponse(t, w)
+	if resp.Error == nil || resp.Error.Code != InvalidRequest {
+		t.Fatalf("expected InvalidRequest, got %+v", resp.Error)
+	}
+	if string(resp.ID) != "null" {
+		t.Errorf("expected a null id, got %s", resp.ID)
+	}
+}
+
+func TestDispatcher_Notification(t *testing.T) {
+	called := false
+	d := NewDispatcher()
+	d.Register("note", func(r *http.Request, req *RPCRequest) (any, *A2AError) {
+		called = true
+		return "ignored", nil
+	})
+
+	w := serve(d, http.MethodPost, `{"jsonrpc":"2.0","method":"note"}`)
+
+	if !called {
+		t.Error("expected the notification to run")
+	}
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("expected an empty 204, got %d %q", w.Code, w.Body.String())
+	}
+
+	// unknown methods are not reported for notifications either
+	w = serve(d, http.MethodPost, `{"jsonrpc":"2.0","method":"missing"}`)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for an unknown notification, got %d", w.Code)
+	}
+}
+
+func TestDispatcher_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"parse error", `{"jsonrpc":`, ParseError},
+		{"empty body", ``, ParseError},
+		{"not an object", `"hello"`, InvalidRequest},
+		{"wrong version", `{"jsonrpc":"1.0","id":1,"method":"echo"}`, InvalidRequest},
+		{"missing method", `{"jsonrpc":"2.0","id":1}`, InvalidRequest},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"missing"}`, MethodNotFound},
+		{"bad params", `{"jsonrpc":"2.0","id":1,"method":"echo","params":[1]}`, InvalidParams},
+		{"method error", `{"jsonrpc":"2.0","id":1,"method":"fail"}`, TaskNotFound},
+		{"panic", `{"jsonrpc":"2.0","id":1,"method":"panic"}`, InternalError},
+		{"empty batch", `[]`, InvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newTestDispatcher(), http.MethodPost, tt.body)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("expected status 200, got %d", w.Code)
+			}
+
+			resp := decodeResponse(t, w)
+			if resp.Error == nil || resp.Error.Code != tt.code {
+				t.Errorf("expected error code %d, got %+v", tt.code, resp.Error)
+			}
+		})
+	}
+}
+
+func TestDispatcher_WrongHTTPMethod(t *testing.T) {
+	w := serve(newTestDispatcher(), http.MethodGet, "")
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+	if w.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("expected Allow: POST, got %q", w.Header().Get("Allow"))
+	}
+}
+
+func TestDispatcher_Batch(t *testing.T) {
+	body := `[
+		{"jsonrpc":"2.0","id":1,"method":"echo","params":{"n":1}},
+		{"jsonrpc":"2.0","method":"echo","params":{"n":2}},
+		{"jsonrpc":"2.0","id":"b","method":"missing"},
+		42,
+		{"jsonrpc":"2.0","id":3,"method":"echo","params":{"n":3}}
+	]`
+
+	w := serve(newTestDispatcher(), http.MethodPost, body)
+
+	var responses []RPCResponse
+	if err := json.NewDecoder(w.Body).Decode(&responses); err != nil {
+		t.Fatalf("failed to decode batch response: %v", err)
+	}
+
+	if len(responses) != 4 {
+		t.Fatalf("expected 4 responses without the notification, got %d", len(responses))
+	}
+
+	expected := []struct {
+		id   string
+		code int
+	}{
+		{`1`, 0},
+		{`"b"`, MethodNotFound},
+		{`null`, InvalidRequest},
+		{`3`, 0},
+	}
+	for i, want := range expected {
+		if string(responses[i].ID) != want.id {
+			t.Errorf("response %d: expected id %s, got %s", i, want.id, responses[i].ID)
+		}
+		code := 0
+		if responses[i].Error != nil {
+			code = responses[i].Error.Code
+		}
+		if code != want.code {
+			t.Errorf("response %d: expected code %d, got %d", i, want.code, code)
+		}
+	}
+}
+
+func TestDispatcher_BatchOfNotifications(t *testing.T) {
+	w := serve(newTestDispatcher(), http.MethodPost, `[{"jsonrpc":"2.0","method":"echo"},{"jsonrpc":"2.0","method":"echo"}]`)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+}
+
+func TestDispatcher_BatchTooLarge(t *testing.T) {
+	d := newTestDispatcher()
+	d.maxBatchSize = 1
+
+	w := serve(d, http.MethodPost, `[{"jsonrpc":"2.0","id":1,"method":"echo"},{"jsonrpc":"2.0","id":2,"method":"echo"}]`)
+
+	resp := decodeResponse(t, w)
+	if resp.Error == nil || resp.Error.Code != InvalidRequest {
+		t.Errorf("expected InvalidRequest, got %+v", resp.Error)
+	}
+}
+
+func TestDispatcher_BodyLimit(t *testing.T) {
+	d := newTestDispatcher()
+	body := `{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"` + strings.Repeat("a", DefaultMaxBodyBytes) + `"}}`
+
+	if w := serve(d, http.MethodPost, body); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413 over the default limit, got %d", w.Code)
+	}
+
+	d.SetMaxBodyBytes(2 * DefaultMaxBodyBytes)
+	w := serve(d, http.MethodPost, body)
+	if resp := decodeResponse(t, w); w.Code != http.StatusOK || resp.Error != nil {
+		t.Errorf("expected the larger body to be accepted, got %d %+v", w.Code, resp.Error)
+	}
+}
+
+func TestDispatcher_Methods(t *testing.T) {
+	methods := newTestDispatcher().Methods()
+
+	if strings.Join(methods, ",") != "echo,fail,panic" {
+		t.Errorf("unexpected methods %v", methods)
+	}
+}
@@ -0,0 +1,116 @@
+package a2a
+
+// methods defined by the A2A specification
+const (
+	MethodMessageSend      = "message/send"
+	MethodMessageStream    = "message/stream"
+	MethodTasksGet         = "tasks/get"
+	MethodTasksCancel      = "tasks/cancel"
+	MethodTasksResubscribe = "tasks/resubscribe"
+	MethodSetPushConfig    = "tasks/pushNotificationConfig/set"
+	MethodGetPushConfig    = "tasks/pushNotificationConfig/get"
+	MethodListPushConfig   = "tasks/pushNotificationConfig/list"
+	MethodDeletePushConfig = "tasks/pushNotificationConfig/delete"
+	MethodGetExtendedCard  = "agent/getAuthenticatedExtendedCard"
+)
+
+// SpecMethods lists every method the specification defines, so unregistered ones can be
+// answered with UnsupportedOperation instead of MethodNotFound
+var SpecMethods = []string{
+	MethodMessageSend,
+	MethodMessageStream,
+	MethodTasksGet,
+	MethodTasksCancel,
+	MethodTasksResubscribe,
+	MethodSetPushConfig,
+	MethodGetPushConfig,
+	MethodListPushConfig,
+	MethodDeletePushConfig,
+	MethodGetExtendedCard,
+}
+
+// Validator is implemented by params that check themselves once decoded
+type Validator interface {
+	Validate() *A2AError
+}
+
+// MessageSendParams are the params of message/send and message/stream
+type MessageSendParams struct {
+	Message       A2AMessage             `json:"message"`
+	Configuration A2AConfig              `json:"configuration"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (p *MessageSendParams) Validate() *A2AError {
+	if len(p.Message.Parts) == 0 {
+		return NewError(InvalidParams, "message parts are required")
+	}
+	if p.Configuration.HistoryLength < 0 {
+		return NewError(InvalidParams, "historyLength must not be negative")
+	}
+	return nil
+}
+
+// TaskQueryParams are the params of tasks/get
+type TaskQueryParams struct {
+	ID            string                 `json:"id"`
+	HistoryLength *int                   `json:"historyLength,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (p *TaskQueryParams) Validate() *A2AError {
+	if p.ID == "" {
+		return NewError(InvalidParams, "task id is required")
+	}
+	if p.HistoryLength != nil && *p.HistoryLength < 0 {
+		return NewError(InvalidParams, "historyLength must not be negative")
+	}
+	return nil
+}
+
+// TaskIDParams are the params of tasks/cancel and tasks/resubscribe
+type TaskIDParams struct {
+	ID       string                 `json:"id"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (p *TaskIDParams) Validate() *A2AError {
+	if p.ID == "" {
+		return NewError(InvalidParams, "task id is required")
+	}
+	return nil
+}
+
+// GetTaskPushConfigParams are the params of tasks/pushNotificationConfig/get
+type GetTaskPushConfigParams struct {
+	ID                       string                 `json:"id"`
+	PushNotificationConfigID string                 `json:"pushNotificationConfigId,omitempty"`
+	Metadata                 map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (p *GetTaskPushConfigParams) Validate() *A2AError {
+	if p.ID == "" {
+		return NewError(InvalidParams, "task id is required")
+	}
+	return nil
+}
+
+// A2ATaskPushConfig doubles as the params of tasks/pushNotificationConfig/set
+func (p *A2ATaskPushConfig) Validate() *A2AError {
+	if p.TaskID == "" || p.PushNotificationConfig.URL == "" {
+		return NewError(InvalidParams, "taskId and pushNotificationConfig are required")
+	}
+	return nil
+}
+
+// unsupportedError is the answer to a spec method the server has no handler for
+func unsupportedError(method string) *A2AError {
+	switch method {
+	case MethodSetPushConfig, MethodGetPushConfig, MethodListPushConfig, MethodDeletePushConfig:
+		return NewError(PushNotificationNotSupported, nil)
+	case MethodGetExtendedCard:
+		return NewError(AuthenticatedExtendedCardNotConfigured, nil)
+	default:
+		return NewError(UnsupportedOperation, method+" is not supported by this agent")
+	}
+}
@@ -0,0 +1,112 @@
+package a2a
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// part kinds defined by the specification
+const (
+	PartKindText = "text"
+	PartKindFile = "file"
+	PartKindData = "data"
+)
+
+// wire form of A2APart, with data left raw until its shape is known
+type partJSON struct {
+	Kind     string                 `json:"kind"`
+	Text     string                 `json:"text,omitempty"`
+	File     *A2AFile               `json:"file,omitempty"`
+	Data     json.RawMessage        `json:"data,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (p *A2APart) UnmarshalJSON(raw []byte) error {
+	var decoded partJSON
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return err
+	}
+
+	*p = A2APart{
+		Kind:     decoded.Kind,
+		Text:     decoded.Text,
+		File:     decoded.File,
+		Metadata: decoded.Metadata,
+	}
+
+	data := bytes.TrimSpace(decoded.Data)
+	switch {
+	case len(data) == 0 || bytes.Equal(data, []byte("null")):
+	case data[0] == '[':
+		if err := json.Unmarshal(data, &p.Data); err != nil {
+			return fmt.Errorf("invalid data part list: %w", err)
+		}
+	case data[0] == '{':
+		if err := json.Unmarshal(data, &p.Object); err != nil {
+			return fmt.Errorf("invalid data part object: %w", err)
+		}
+	default:
+		return fmt.Errorf("data part must be an object or a list")
+	}
+
+	return nil
+}
+
+func (p A2APart) MarshalJSON() ([]byte, error) {
+	encoded := partJSON{
+		Kind:     p.Kind,
+		Text:     p.Text,
+		File:     p.File,
+		Metadata: p.Metadata,
+	}
+
+	var err error
+	switch {
+	case p.Object != nil:
+		encoded.Data, err = json.Marshal(p.Object)
+	case len(p.Data) > 0:
+		encoded.Data, err = json.Marshal(p.Data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(encoded)
+}
+
+// Decode returns the inline bytes of the file; files sent by URI have none
+func (f *A2AFile) Decode() ([]byte, error) {
+	if f.Bytes == "" {
+		return nil, fmt.Errorf("file has no inline bytes")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(f.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("file bytes are not valid base64: %w", err)
+	}
+	return data, nil
+}
+
+// FileParts returns the files in parts, in order
+func FileParts(parts []A2APart) []*A2AFile {
+	var files []*A2AFile
+	for _, part := range parts {
+		if part.Kind == PartKindFile && part.File != nil {
+			files = append(files, part.File)
+		}
+	}
+	return files
+}
+
+// DataObjects returns the JSON objects of the data parts in parts, in order
+func DataObjects(parts []A2APart) []map[string]interface{} {
+	var objects []map[string]interface{}
+	for _, part := range parts {
+		if part.Kind == PartKindData && part.Object != nil {
+			objects = append(objects, part.Object)
+		}
+	}
+	return objects
+}
@@ -0,0 +1,75 @@
+package a2a
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestA2APart_UnmarshalDataShapes(t *testing.T) {
+	var parts []A2APart
+	raw := `[
+		{"kind":"data","data":{"mood_score":7}},
+		{"kind":"data","data":[{"kind":"text","text":"<p>earlier</p>"}]},
+		{"kind":"file","file":{"name":"sky.png","mimeType":"image/png","bytes":"aGVsbG8="}}
+	]`
+	if err := json.Unmarshal([]byte(raw), &parts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if parts[0].Object["mood_score"] != float64(7) || parts[0].Data != nil {
+		t.Errorf("expected an object data part, got %+v", parts[0])
+	}
+	if len(parts[1].Data) != 1 || parts[1].Data[0].Text != "<p>earlier</p>" || parts[1].Object != nil {
+		t.Errorf("expected a list data part, got %+v", parts[1])
+	}
+
+	data, err := parts[2].File.Decode()
+	if err != nil || string(data) != "hello" {
+		t.Errorf("expected decoded file bytes, got %q (%v)", data, err)
+	}
+}
+
+func TestA2APart_RoundTrip(t *testing.T) {
+	original := []A2APart{
+		{Kind: PartKindText, Text: "hi"},
+		{Kind: PartKindData, Object: map[string]interface{}{"risk": "low"}},
+		{Kind: PartKindData, Data: []A2APart{{Kind: PartKindText, Text: "nested"}}},
+	}
+
+	encoded, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded []A2APart
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded[1].Object["risk"] != "low" || decoded[2].Data[0].Text != "nested" || decoded[0].Text != "hi" {
+		t.Errorf("parts did not survive a round trip: %s", encoded)
+	}
+}
+
+func TestA2APart_InvalidData(t *testing.T) {
+	var part A2APart
+	if err := json.Unmarshal([]byte(`{"kind":"data","data":"text"}`), &part); err == nil {
+		t.Error("expected a scalar data part to be rejected")
+	}
+}
+
+func TestFilePartsAndDataObjects(t *testing.T) {
+	parts := []A2APart{
+		{Kind: PartKindText, Text: "hi"},
+		{Kind: PartKindFile, File: &A2AFile{URI: "https://example.com/a.png"}},
+		{Kind: PartKindData, Object: map[string]interface{}{"mood": 5.0}},
+		{Kind: PartKindData, Data: []A2APart{{Kind: PartKindText}}},
+	}
+
+	if files := FileParts(parts); len(files) != 1 || files[0].URI != "https://example.com/a.png" {
+		t.Errorf("unexpected files %+v", files)
+	}
+	if objects := DataObjects(parts); len(objects) != 1 || objects[0]["mood"] != 5.0 {
+		t.Errorf("unexpected data objects %+v", objects)
+	}
+}
@@ -0,0 +1,99 @@
+package a2a
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Middleware wraps the handler of one method; it sees every call, including notifications
+type Middleware func(method string, next MethodHandler) MethodHandler
+
+// Server is an A2A JSON-RPC endpoint. Spec methods without a handler answer with
+// UnsupportedOperation (or the push and extended card equivalents) rather than MethodNotFound.
+type Server struct {
+	dispatcher *Dispatcher
+	middleware []Middleware
+}
+
+func NewServer() *Server {
+	s := &Server{dispatcher: NewDispatcher()}
+	s.dispatcher.HandleUnknown(func(method string) *A2AError {
+		if slices.Contains(SpecMethods, method) {
+			return unsupportedError(method)
+		}
+		return nil
+	})
+	return s
+}
+
+// SetMaxBodyBytes sets the largest request body accepted, DefaultMaxBodyBytes unless changed
+func (s *Server) SetMaxBodyBytes(n int64) {
+	s.dispatcher.SetMaxBodyBytes(n)
+}
+
+// Use appends middleware; it applies to every method, whether registered before or after
+func (s *Server) Use(middleware ...Middleware) {
+	s.middleware = append(s.middleware, middleware...)
+}
+
+// Handle registers an untyped method handler that decodes its own params
+func (s *Server) Handle(method string, handler MethodHandler) {
+	s.dispatcher.Register(method, func(r *http.Request, req *RPCRequest) (any, *A2AError) {
+		wrapped := handler
+		// the first middleware added runs outermost
+		for i := len(s.middleware) - 1; i >= 0; i-- {
+			wrapped = s.middleware[i](method, wrapped)
+		}
+		return wrapped(r, req)
+	})
+}
+
+// Register adds a method whose params are decoded into P and validated when P implements Validator
+func Register[P, R any](s *Server, method string, fn func(r *http.Request, params *P) (R, *A2AError)) {
+	s.Handle(method, func(r *http.Request, req *RPCRequest) (any, *A2AError) {
+		params := new(P)
+		if rpcErr := req.DecodeParams(params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		if validator, ok := any(params).(Validator); ok {
+			if rpcErr := validator.Validate(); rpcErr != nil {
+				return nil, rpcErr
+			}
+		}
+		return fn(r, params)
+	})
+}
+
+// OnMessageSend registers the message/send handler
+func (s *Server) OnMessageSend(fn func(r *http.Request, params *MessageSendParams) (*A2AResult, *A2AError)) {
+	Register(s, MethodMessageSend, fn)
+}
+
+// OnGetTask registers the tasks/get handler
+func (s *Server) OnGetTask(fn func(r *http.Request, params *TaskQueryParams) (*A2AResult, *A2AError)) {
+	Register(s, MethodTasksGet, fn)
+}
+
+// OnCancelTask registers the tasks/cancel handler
+func (s *Server) OnCancelTask(fn func(r *http.Request, params *TaskIDParams) (*A2AResult, *A2AError)) {
+	Register(s, MethodTasksCancel, fn)
+}
+
+// OnSetPushConfig registers the tasks/pushNotificationConfig/set handler
+func (s *Server) OnSetPushConfig(fn func(r *http.Request, params *A2ATaskPushConfig) (*A2ATaskPushConfig, *A2AError)) {
+	Register(s, MethodSetPushConfig, fn)
+}
+
+// OnGetPushConfig registers the tasks/pushNotificationConfig/get handler
+func (s *Server) OnGetPushConfig(fn func(r *http.Request, params *GetTaskPushConfigParams) (*A2ATaskPushConfig, *A2AError)) {
+	Register(s, MethodGetPushConfig, fn)
+}
+
+// Methods returns the registered method names in alphabetical order
+func (s *Server) Methods() []string {
+	return s.dispatcher.Methods()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.dispatcher.ServeHTTP(w, r)
+}
@@ -0,0 +1,112 @@
+package a2a
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestServer_RegisterTypedParams(t *testing.T) {
+	s := NewServer()
+	s.OnGetTask(func(r *http.Request, params *TaskQueryParams) (*A2AResult, *A2AError) {
+		return &A2AResult{ID: params.ID, Kind: "task"}, nil
+	})
+
+	resp := decodeResponse(t, serve(s, http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"tasks/get","params":{"id":"task-1"}}`))
+	if resp.Error != nil {
+		t.Fatalf("unexpected error %+v", resp.Error)
+	}
+	result, ok := resp.Result.(map[string]any)
+	if !ok || result["id"] != "task-1" {
+		t.Errorf("expected task-1 in result, got %v", resp.Result)
+	}
+}
+
+func TestServer_ValidatesParams(t *testing.T) {
+	called := false
+	s := NewServer()
+	s.OnCancelTask(func(r *http.Request, params *TaskIDParams) (*A2AResult, *A2AError) {
+		called = true
+		return &A2AResult{}, nil
+	})
+
+	resp := decodeResponse(t, serve(s, http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"tasks/cancel","params":{}}`))
+	if resp.Error == nil || resp.Error.Code != InvalidParams {
+		t.Errorf("expected InvalidParams, got %+v", resp.Error)
+	}
+	if called {
+		t.Error("handler should not run when params are invalid")
+	}
+}
+
+func TestServer_UnregisteredMethods(t *testing.T) {
+	s := NewServer()
+
+	tests := []struct {
+		method string
+		code   int
+	}{
+		{MethodTasksGet, UnsupportedOperation},
+		{MethodMessageStream, UnsupportedOperation},
+		{MethodSetPushConfig, PushNotificationNotSupported},
+		{MethodGetExtendedCard, AuthenticatedExtendedCardNotConfigured},
+		{"tasks/unknown", MethodNotFound},
+	}
+
+	for _, tt := range tests {
+		resp := decodeResponse(t, serve(s, http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"`+tt.method+`"}`))
+		if resp.Error == nil || resp.Error.Code != tt.code {
+			t.Errorf("%s: expected code %d, got %+v", tt.method, tt.code, resp.Error)
+		}
+	}
+}
+
+func TestServer_MiddlewareOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(method string, next MethodHandler) MethodHandler {
+			return func(r *http.Request, req *RPCRequest) (any, *A2AError) {
+				calls = append(calls, name+":"+method)
+				return next(r, req)
+			}
+		}
+	}
+
+	s := NewServer()
+	s.Use(record("outer"))
+	s.Handle("echo", func(r *http.Request, req *RPCRequest) (any, *A2AError) {
+		calls = append(calls, "handler")
+		return "ok", nil
+	})
+	// added after registration and still applied
+	s.Use(record("inner"))
+
+	serve(s, http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"echo"}`)
+
+	want := []string{"outer:echo", "inner:echo", "handler"}
+	if len(calls) != len(want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d: expected %s, got %s", i, want[i], calls[i])
+		}
+	}
+}
+
+func TestServer_MiddlewareCanReject(t *testing.T) {
+	s := NewServer()
+	s.Use(func(method string, next MethodHandler) MethodHandler {
+		return func(r *http.Request, req *RPCRequest) (any, *A2AError) {
+			return nil, NewError(InvalidRequest, "rejected")
+		}
+	})
+	s.OnMessageSend(func(r *http.Request, params *MessageSendParams) (*A2AResult, *A2AError) {
+		t.Error("handler should not run")
+		return nil, nil
+	})
+
+	resp := decodeResponse(t, serve(s, http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"message/send","params":{"message":{"parts":[{"kind":"text","text":"hi"}]}}}`))
+	if resp.Error == nil || resp.Error.Code != InvalidRequest {
+		t.Errorf("expected InvalidRequest, got %+v", resp.Error)
+	}
+}
@@ -0,0 +1,218 @@
+package a2a
+
+import "strings"
+
+// A2A task lifecycle states
+const (
+	TaskStateSubmitted     = "submitted"
+	TaskStateWorking       = "working"
+	TaskStateInputRequired = "input-required"
+	TaskStateCompleted     = "completed"
+	TaskStateFailed        = "failed"
+	TaskStateCanceled      = "canceled"
+	TaskStateRejected      = "rejected"
+	TaskStateAuthRequired  = "auth-required"
+)
+
+// incoming A2A message request
+type A2ARequest struct {
+	JSONRPC string    `json:"jsonrpc"`
+	ID      string    `json:"id"`
+	Method  string    `json:"method"`
+	Params  A2AParams `json:"params"`
+}
+
+// parameters for an A2A request; ID and HistoryLength are used by the tasks/* methods,
+// TaskID and PushNotificationConfig by tasks/pushNotificationConfig/set
+type A2AParams struct {
+	Message                A2AMessage             `json:"message"`
+	Configuration          A2AConfig              `json:"configuration"`
+	ID                     string                 `json:"id,omitempty"`
+	HistoryLength          *int                   `json:"historyLength,omitempty"`
+	TaskID                 string                 `json:"taskId,omitempty"`
+	PushNotificationConfig *A2APushNotification   `json:"pushNotificationConfig,omitempty"`
+	Metadata               map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// message in the A2A protocol
+type A2AMessage struct {
+	Kind      string                 `json:"kind"`
+	Role      string                 `json:"role"`
+	Parts     []A2APart              `json:"parts"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	MessageID string                 `json:"messageId"`
+	// ContextID groups messages into one conversation; clients echo the one returned to continue it
+	ContextID string `json:"contextId,omitempty"`
+}
+
+// part of an A2A message: text, file or data. A data part carries either a JSON object (Object)
+// or, as Telex sends conversation history, a list of nested parts (Data).
+type A2APart struct {
+	Kind     string                 `json:"kind"`
+	Text     string                 `json:"text,omitempty"`
+	File     *A2AFile               `json:"file,omitempty"`
+	Data     []A2APart              `json:"-"`
+	Object   map[string]interface{} `json:"-"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// file in a file part, sent inline as base64 Bytes or by URI
+type A2AFile struct {
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Bytes    string `json:"bytes,omitempty"`
+	URI      string `json:"uri,omitempty"`
+}
+
+// configuration for A2A requests
+type A2AConfig struct {
+	AcceptedOutputModes    []string             `json:"acceptedOutputModes,omitempty"`
+	HistoryLength          int                  `json:"historyLength,omitempty"`
+	PushNotificationConfig *A2APushNotification `json:"pushNotificationConfig,omitempty"`
+	// Blocking is nil when the client did not say; only an explicit false processes in the background
+	Blocking *bool `json:"blocking,omitempty"`
+}
+
+// NonBlocking reports whether the client asked for the task to be processed in the background
+func (c A2AConfig) NonBlocking() bool {
+	return c.Blocking != nil && !*c.Blocking
+}
+
+// push notification configuration
+type A2APushNotification struct {
+	ID             string                 `json:"id,omitempty"`
+	URL            string                 `json:"url"`
+	Token          string                 `json:"token,omitempty"`
+	Authentication *A2APushAuthentication `json:"authentication,omitempty"`
+}
+
+// authentication the agent uses when calling a push notification URL
+type A2APushAuthentication struct {
+	Schemes     []string `json:"schemes"`
+	Credentials string   `json:"credentials,omitempty"`
+}
+
+// push notification config bound to a task
+type A2ATaskPushConfig struct {
+	TaskID                 string              `json:"taskId"`
+	PushNotificationConfig A2APushNotification `json:"pushNotificationConfig"`
+	// Metadata identifies the caller when the type is used as params
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// A2A response for the tasks/pushNotificationConfig/* methods
+type A2APushConfigResponse struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      string            `json:"id"`
+	Result  A2ATaskPushConfig `json:"result"`
+}
+
+// A2A response
+type A2AResponse struct {
+	JSONRPC string    `json:"jsonrpc"`
+	ID      string    `json:"id"`
+	Result  A2AResult `json:"result,omitempty"`
+	Error   *A2AError `json:"error,omitempty"`
+}
+
+// task result
+type A2AResult struct {
+	ID        string             `json:"id"`
+	ContextID string             `json:"contextId,omitempty"`
+	Status    A2ATaskStatus      `json:"status"`
+	Artifacts []A2AArtifact      `json:"artifacts,omitempty"`
+	History   []A2AMessageResult `json:"history,omitempty"`
+	Kind      string             `json:"kind"`
+}
+
+// status of a task with message
+type A2ATaskStatus struct {
+	State     string           `json:"state"`
+	Timestamp string           `json:"timestamp"`
+	Message   A2AMessageResult `json:"message"`
+}
+
+// message in A2A responses
+type A2AMessageResult struct {
+	MessageID string                 `json:"messageId"`
+	Role      string                 `json:"role"`
+	Parts     []A2APart              `json:"parts"`
+	Kind      string                 `json:"kind"`
+	TaskID    string                 `json:"taskId"`
+	ContextID string                 `json:"contextId,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// artifact in A2A responses
+type A2AArtifact struct {
+	ArtifactID string    `json:"artifactId"`
+	Name       string    `json:"name"`
+	Parts      []A2APart `json:"parts"`
+}
+
+// error in A2A responses
+type A2AError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// internal chat response
+type ChatResponse struct {
+	Response string `json:"response"`
+	// InputRequired is set when the agent is waiting for the user to confirm an action
+	InputRequired bool `json:"input_required,omitempty"`
+}
+
+// status change sent on a message/stream; Final marks the last event of the stream
+type TaskStatusUpdateEvent struct {
+	TaskID    string        `json:"taskId"`
+	ContextID string        `json:"contextId"`
+	Kind      string        `json:"kind"`
+	Status    A2ATaskStatus `json:"status"`
+	Final     bool          `json:"final"`
+}
+
+// artifact chunk sent on a message/stream; LastChunk ends the artifact
+type TaskArtifactUpdateEvent struct {
+	TaskID    string      `json:"taskId"`
+	ContextID string      `json:"contextId"`
+	Kind      string      `json:"kind"`
+	Artifact  A2AArtifact `json:"artifact"`
+	Append    bool        `json:"append,omitempty"`
+	LastChunk bool        `json:"lastChunk,omitempty"`
+}
+
+// IsFinalState reports whether a task in this state needs nothing more from the agent;
+// input-required and auth-required wait on the client instead
+func IsFinalState(state string) bool {
+	switch state {
+	case TaskStateCompleted, TaskStateFailed, TaskStateCanceled, TaskStateRejected,
+		TaskStateInputRequired, TaskStateAuthRequired:
+		return true
+	}
+	return false
+}
+
+// MIME types Eunoia produces
+const (
+	MediaTypeText = "text/plain"
+	MediaTypeJSON = "application/json"
+)
+
+// Accepts reports whether the client listed mediaType, directly or through a type/* or */* wildcard;
+// a client that listed nothing accepts anything
+func (c A2AConfig) Accepts(mediaType string) bool {
+	if len(c.AcceptedOutputModes) == 0 {
+		return true
+	}
+
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	for _, mode := range c.AcceptedOutputModes {
+		mode = strings.ToLower(strings.TrimSpace(mode))
+		if mode == mediaType || mode == "*/*" || mode == mainType+"/*" {
+			return true
+		}
+	}
+	return false
+}